@@ -0,0 +1,78 @@
+// Apply the schema migrations to the database of MONGO_CLUSTER_URL and
+// MONGO_DB_NAME.
+//
+//	migrate           apply every pending version
+//	migrate -to 3     apply pending versions up to 3
+//	migrate -status   list the versions and whether they are applied
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/migration"
+	"github.com/takeme-id/core/utils/database"
+)
+
+func main() {
+	status := flag.Bool("status", false, "list migration versions without applying them")
+	target := flag.Int("to", 0, "apply pending versions up to this one, 0 apply all")
+	flag.Parse()
+
+	err := run(*status, *target)
+	if err != nil {
+		log.Error(fmt.Sprintf("Migration failed because %v", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func run(status bool, target int) error {
+	ctx := context.Background()
+
+	err := database.SetupDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer database.CloseDB(ctx)
+
+	runner, err := migration.NewRunner(database.DBClient.Database(os.Getenv("MONGO_DB_NAME")), migration.Migrations)
+	if err != nil {
+		return err
+	}
+
+	if status {
+		results, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			applied := "pending"
+			if result.Applied {
+				applied = "applied " + result.AppliedTime.String()
+			}
+
+			fmt.Printf("%4d  %-40s  %v\n", result.Version, applied, result.Description)
+		}
+
+		return nil
+	}
+
+	versions, err := runner.Up(ctx, target)
+	for _, version := range versions {
+		fmt.Printf("Applied version %v\n", version)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("Schema is up to date")
+	}
+
+	return nil
+}
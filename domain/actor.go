@@ -50,9 +50,9 @@ type ActorAble interface {
 	GetActorBalance() primitive.ObjectID
 	GetBalances() []AccessBalance
 	GetPIN() string
-	GetTemporaryPIN() string
 	IsFaceAsPIN() bool
 	IsVerify() bool
+	GetKYCTier() string
 	ToActorObject() ActorObject
 	ToTransactionObject() TransactionObject
 }
@@ -18,6 +18,11 @@ type Balance struct {
 	Amount      int                `json:"amount" bson:"amount"`
 	VA          []VirtualAccount   `json:"va" bson:"va,omitempty"`
 	Currency    string             `json:"currency" bson:"currency,omitempty"`
+
+	// Amount and both counters only change together through an atomic adjust,
+	// version is also raised by every other update of the document
+	Version           int64 `json:"version" bson:"version"`
+	StatementSequence int64 `json:"statement_sequence" bson:"statement_sequence"`
 }
 
 type VirtualAccount struct {
@@ -25,6 +30,11 @@ type VirtualAccount struct {
 	AccountNumber string `json:"account_number" bson:"account_number,omitempty"`
 }
 
+func (domain Balance) ToMoney() Money {
+	return NewMoney(domain.Amount, domain.Currency)
+}
+
+// Interface for mongo document result
 func (domain *Balance) SetDocumentID(ID primitive.ObjectID) {
 	domain.ID = ID
 }
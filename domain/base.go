@@ -11,6 +11,6 @@ type BaseModel interface {
 }
 
 type Audit struct {
-	CreatedTime string `json:"created_time" bson:"created_time,omitempty"`
-	UpdatedTime string `json:"updated_time" bson:"updated_time,omitempty"`
+	CreatedTime Timestamp `json:"created_time" bson:"created_time,omitempty"`
+	UpdatedTime Timestamp `json:"updated_time" bson:"updated_time,omitempty"`
 }
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const BIOMETRIC_CHALLENGE_COLLECTION string = "biometric_challenge"
+
+const (
+	BIOMETRIC_PURPOSE_LOGIN         = "face_login"
+	BIOMETRIC_PURPOSE_TEMPORARY_PIN = "temporary_pin"
+)
+
+// One time challenge issued before a face capture. Face is accepted only
+// with an unused challenge of the same user, purpose and device. Nonce is
+// returned once on issue and only its hash is stored.
+type BiometricChallenge struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"-" bson:"corporate_id"`
+	UserID      primitive.ObjectID `json:"-" bson:"user_id"`
+	Purpose     string             `json:"purpose" bson:"purpose"`
+	DeviceID    string             `json:"device_id" bson:"device_id"`
+	Nonce       string             `json:"nonce" bson:"-"`
+	NonceHash   string             `json:"-" bson:"nonce_hash"`
+	Used        bool               `json:"-" bson:"used"`
+	ExpiredAt   time.Time          `json:"expired_at" bson:"expired_at"`
+}
+
+func IsBiometricPurpose(purpose string) bool {
+	return purpose == BIOMETRIC_PURPOSE_LOGIN || purpose == BIOMETRIC_PURPOSE_TEMPORARY_PIN
+}
+
+// Interface for mongo document result
+func (domain *BiometricChallenge) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *BiometricChallenge) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *BiometricChallenge) CollectionName() string {
+	return BIOMETRIC_CHALLENGE_COLLECTION
+}
@@ -4,11 +4,19 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 
 const BULK_TRANSFER_COLLECTION string = "bulk_transfer"
 const BULK_INQUIRY_COLLECTION string = "bulk_inquiry"
+const BULK_APPROVAL_HISTORY_COLLECTION string = "bulk_approval_history"
 
 const (
-	BULK_UNEXECUTED_STATUS = "Unexecuted"
-	BULK_PROGRESS_STATUS   = "Progress"
-	BULK_COMPLETED_STATUS  = "Completed"
+	BULK_PENDING_APPROVAL_STATUS = "PendingApproval"
+	BULK_UNEXECUTED_STATUS       = "Unexecuted"
+	BULK_PROGRESS_STATUS         = "Progress"
+	BULK_COMPLETED_STATUS        = "Completed"
+	BULK_REJECTED_STATUS         = "Rejected"
+)
+
+const (
+	BULK_APPROVAL_APPROVED = "Approved"
+	BULK_APPROVAL_REJECTED = "Rejected"
 )
 
 type BulkTransfer struct {
@@ -17,13 +25,61 @@ type BulkTransfer struct {
 	BalanceID    primitive.ObjectID `json:"balance_id" bson:"balance_id,omitempty"`
 	Reference    string             `json:"reference" bson:"reference,omitempty"`
 	Owner        ActorObject        `json:"owner" bson:"owner,omitempty"`
-	Time         string             `json:"time" bson:"time,omitempty"`
+	Time         Timestamp          `json:"time" bson:"time,omitempty"`
 	SubAmount    int                `json:"sub_amount" bson:"sub_amount,omitempty"`
 	Amount       int                `json:"amount" bson:"amount,omitempty"`
 	List         []Transfer         `json:"list" bson:"list,omitempty"`
 	TotalList    int                `json:"total_list" bson:"total_list,omitempty"`
 	Status       string             `json:"status" bson:"status,omitempty"`
 	FailedNumber []int              `json:"failedNumber" bson:"failedNumber,omitempty"`
+
+	RequiredApprovals int            `json:"required_approvals" bson:"required_approvals"`
+	Approvals         []BulkApproval `json:"approvals" bson:"approvals,omitempty"`
+}
+
+type BulkApproval struct {
+	Approver ActorObject `json:"approver" bson:"approver"`
+	Action   string      `json:"action" bson:"action"`
+	Note     string      `json:"note" bson:"note,omitempty"`
+	Time     Timestamp   `json:"time" bson:"time"`
+}
+
+// Bulk with amount at least MinimumAmount need RequiredApprovals approver
+// other than the maker before it can be executed
+type BulkApprovalThreshold struct {
+	MinimumAmount     int `json:"minimum_amount" bson:"minimum_amount"`
+	RequiredApprovals int `json:"required_approvals" bson:"required_approvals"`
+}
+
+// Audit of every change on Corporate.BulkApprovals
+type BulkApprovalHistory struct {
+	ID          primitive.ObjectID      `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID      `json:"corporate_id" bson:"corporate_id"`
+	Actor       ActorObject             `json:"actor" bson:"actor"`
+	Previous    []BulkApprovalThreshold `json:"previous" bson:"previous"`
+	Current     []BulkApprovalThreshold `json:"current" bson:"current"`
+	Time        Timestamp               `json:"time" bson:"time"`
+}
+
+func (self BulkTransfer) ApprovedCount() int {
+	count := 0
+	for _, approval := range self.Approvals {
+		if approval.Action == BULK_APPROVAL_APPROVED {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (self BulkTransfer) HasReviewed(actorID primitive.ObjectID) bool {
+	for _, approval := range self.Approvals {
+		if approval.Approver.ID == actorID {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Transfer struct {
@@ -42,7 +98,7 @@ type BulkInquiry struct {
 	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
 	Reference   string             `json:"reference" bson:"reference,omitempty"`
 	Owner       ActorObject        `json:"owner" bson:"owner,omitempty"`
-	Time        string             `json:"time" bson:"time,omitempty"`
+	Time        Timestamp          `json:"time" bson:"time,omitempty"`
 	List        []Inquiry          `json:"list" bson:"list,omitempty"`
 	TotalList   int                `json:"total_list" bson:"total_list,omitempty"`
 	Status      string             `json:"status" bson:"status,omitempty"`
@@ -82,3 +138,16 @@ func (domain *BulkInquiry) GetDocumentID() primitive.ObjectID {
 func (model *BulkInquiry) CollectionName() string {
 	return BULK_INQUIRY_COLLECTION
 }
+
+// Interface for mongo document result
+func (domain *BulkApprovalHistory) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *BulkApprovalHistory) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (model *BulkApprovalHistory) CollectionName() string {
+	return BULK_APPROVAL_HISTORY_COLLECTION
+}
@@ -6,7 +6,7 @@ const CALLBACK_HISTORY_COLLECTION string = "callback_history"
 
 type CallbackHistory struct {
 	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Time            string             `json:"time" bson:"time,omitempty"`
+	Time            Timestamp          `json:"time" bson:"time,omitempty"`
 	URL             string             `json:"url" bson:"url,omitempty"`
 	TransactionCode string             `json:"transaction_code" bson:"transaction_code,omitempty"`
 	RequestBody     string             `json:"request_body" bson:"request_body,omitempty"`
@@ -15,6 +15,7 @@ type Claims struct {
 	SAAS            bool     `json:"saas"`
 	Resources       []string `json:"resources"`
 	CorporateURL    string   `json:"corporate_url"`
+	SessionID       string   `json:"sid"`
 	jwt.StandardClaims
 }
 
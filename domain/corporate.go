@@ -7,9 +7,9 @@ const CORPORATE_COLLECTION string = "corporate"
 type Corporate struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	CreatedBy   primitive.ObjectID `json:"created_by" bson:"created_by,omitempty"`
-	CreatedTime string             `json:"created_time" bson:"created_time,omitempty"`
+	CreatedTime Timestamp          `json:"created_time" bson:"created_time,omitempty"`
 	UpdatedBy   primitive.ObjectID `json:"updated_by" bson:"updated_by,omitempty"`
-	UpdatedTime string             `json:"updated_time" bson:"updated_time,omitempty"`
+	UpdatedTime Timestamp          `json:"updated_time" bson:"updated_time,omitempty"`
 	Name        string             `json:"name" bson:"name,omitempty"`
 	Secret      string             `json:"secret" bson:"secret,omitempty"`
 
@@ -47,13 +47,18 @@ type Corporate struct {
 	CallbackToken             string               `json:"callback_token" bson:"callback_token"`
 	Parent                    primitive.ObjectID   `json:"parent" bson:"parent,omitempty"`
 	PIN                       string               `json:"-" bson:"pin,omitempty"`
-	ChangePIN                 string               `json:"change_pin" bson:"change_pin,omitempty"`
+	ChangePIN                 string               `json:"-" bson:"change_pin,omitempty"`
 	ChangePINCode             string               `json:"change_pin_code" bson:"change_pin_code,omitempty"`
 	VACode                    string               `json:"va_code" bson:"va_code,omitempty"`
 	Code                      string               `json:"_" bson:"code,omitempty"`
 	Products                  []string             `json:"products" bson:"products,omitempty"`
 	SAAS                      bool                 `json:"saas" bson:"saas,omitempty"`
 	Currency                  string               `json:"currency" bson:"currency,omitempty"`
+
+	BulkApprovals []BulkApprovalThreshold `json:"bulk_approvals" bson:"bulk_approvals,omitempty"`
+	Lockout       Lockout                 `json:"lockout" bson:"lockout"`
+	Messaging     MessagingSetting        `json:"messaging" bson:"messaging,omitempty"`
+	Identity      IdentitySetting         `json:"identity" bson:"identity,omitempty"`
 }
 
 type Fee struct {
@@ -64,6 +69,7 @@ type Fee struct {
 	AcceptPaymentCard string `json:"accept_payment_card" bson:"accept_payment_card,omitempty"`
 	Pay               int    `json:"pay" bson:"pay,omitempty"`
 	Biller            int    `json:"biller" bson:"biller,omitempty"`
+	Rounding          string `json:"rounding" bson:"rounding,omitempty"` // rounding policy for percentage fee, default HALF_EVEN
 }
 
 // Interface for mongo document result
@@ -125,18 +131,32 @@ func (self Corporate) GetPIN() string {
 	return self.PIN
 }
 
-func (self Corporate) GetTemporaryPIN() string {
-	return ""
+// Corporate created before money type has no currency and run on idr
+func (self Corporate) GetCurrency() string {
+	if self.Currency == "" {
+		return CURRENCY_IDR
+	}
+
+	return NormalizeCurrency(self.Currency)
 }
 
 func (self Corporate) IsVerify() bool {
 	return true
 }
 
+func (self Corporate) GetKYCTier() string {
+	return KYC_TIER_ORGANIZATION
+}
+
 func (self Corporate) IsFaceAsPIN() bool {
 	return false
 }
 
+// Lock before Lockout exist set Active false and reset access attempt
+func (self Corporate) IsLegacyLocked() bool {
+	return self.Active == false && self.AccessAttempt == 0
+}
+
 func (self Corporate) ToActorObject() ActorObject {
 	return ActorObject{
 		ID:   self.GetActorID(),
@@ -0,0 +1,44 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const DEVICE_COLLECTION string = "device"
+
+const (
+	DEVICE_STATUS_TRUSTED = "Trusted"
+	DEVICE_STATUS_REVOKED = "Revoked"
+)
+
+// Device of a user, registered with its public key on login. PublicKey is
+// base64 of a PKIX DER ECDSA P-256 or Ed25519 key, sensitive action is signed
+// with the private key kept on the device.
+type Device struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID  primitive.ObjectID `json:"-" bson:"corporate_id"`
+	UserID       primitive.ObjectID `json:"-" bson:"user_id"`
+	DeviceID     string             `json:"device_id" bson:"device_id"`
+	Name         string             `json:"name" bson:"name,omitempty"`
+	PublicKey    string             `json:"-" bson:"public_key"`
+	Status       string             `json:"status" bson:"status"`
+	TrustedTime  Timestamp          `json:"trusted_time" bson:"trusted_time"`
+	LastUsedTime Timestamp          `json:"last_used_time" bson:"last_used_time,omitempty"`
+	RevokedTime  Timestamp          `json:"revoked_time,omitempty" bson:"revoked_time,omitempty"`
+	Current      bool               `json:"current" bson:"-"`
+}
+
+func (self Device) IsTrusted() bool {
+	return self.Status == DEVICE_STATUS_TRUSTED
+}
+
+// Interface for mongo document result
+func (domain *Device) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *Device) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *Device) CollectionName() string {
+	return DEVICE_COLLECTION
+}
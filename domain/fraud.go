@@ -1,11 +1,6 @@
 package domain
 
-import (
-	"os"
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
+import "go.mongodb.org/mongo-driver/bson/primitive"
 
 // Mitigation
 const (
@@ -14,25 +9,111 @@ const (
 	USER_LOCKED              = "User locked"
 	CORPORATE_LOCKED         = "Corporate locked"
 	TRANSACTION_CANCELED     = "Transaction canceled because detected as identycal transaction"
+	IP_NOT_ALLOWED           = "Request from IP outside corporate allowlist"
+)
+
+// Transaction risk
+const (
+	TRANSACTION_RISK_ASSESSED = "Transaction risk assessed"
+	TRANSACTION_HELD          = "Transaction held for review"
+	TRANSACTION_BLOCKED       = "Transaction blocked by risk engine"
+)
+
+const (
+	FRAUD_DECISION_ALLOW  = "ALLOW"
+	FRAUD_DECISION_REVIEW = "REVIEW"
+	FRAUD_DECISION_BLOCK  = "BLOCK"
+)
+
+const (
+	FRAUD_RULE_DEVICE_VELOCITY  = "DEVICE_VELOCITY"
+	FRAUD_RULE_BALANCE_VELOCITY = "BALANCE_VELOCITY"
+	FRAUD_RULE_NEW_BENEFICIARY  = "NEW_BENEFICIARY_LARGE_AMOUNT"
+	FRAUD_RULE_ROUND_AMOUNT     = "ROUND_AMOUNT_BURST"
+	FRAUD_RULE_AFTER_PIN_CHANGE = "AFTER_PIN_CHANGE"
+	FRAUD_RULE_NEW_DEVICE       = "NEW_DEVICE_COOLING_OFF"
+)
+
+// Limit breach hold the transaction for review
+const (
+	FRAUD_RULE_AMOUNT_LIMIT  = "AMOUNT_LIMIT"
+	FRAUD_RULE_DAILY_LIMIT   = "DAILY_LIMIT"
+	FRAUD_RULE_MONTHLY_LIMIT = "MONTHLY_LIMIT"
+	FRAUD_RULE_BALANCE_LIMIT = "BALANCE_LIMIT"
+)
+
+const (
+	FRAUD_REVIEW_PENDING = "Pending"
+	FRAUD_REVIEW_CLOSED  = "Closed"
 )
 
 const FRAUD_COLLECTION string = "fraud"
 
 type Fraud struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Description string             `json:"description" bson:"description,omitempty"`
-	Actor       ActorObject        `json:"actor" bson:"actor,omitempty"`
-	Time        string             `json:"time" bson:"time,omitempty"`
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Description     string             `json:"description" bson:"description,omitempty"`
+	Actor           ActorObject        `json:"actor" bson:"actor,omitempty"`
+	Time            Timestamp          `json:"time" bson:"time,omitempty"`
+	CorporateID     primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
+	TransactionCode string             `json:"transaction_code" bson:"transaction_code,omitempty"`
+	Score           int                `json:"score" bson:"score,omitempty"`
+	Rules           []string           `json:"rules" bson:"rules,omitempty"`
+	Decision        string             `json:"decision" bson:"decision,omitempty"`
+	ReviewStatus    string             `json:"review_status" bson:"review_status,omitempty"`
+	Transaction     *Transaction       `json:"transaction,omitempty" bson:"transaction,omitempty"`
+	Reviewer        *ActorObject       `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+	ReviewResult    string             `json:"review_result" bson:"review_result,omitempty"`
+	ReviewTime      Timestamp          `json:"review_time" bson:"review_time,omitempty"`
+	IPAddress       string             `json:"ip_address" bson:"ip_address,omitempty"`
 }
 
 func CreateFraud(description string, actor ActorAble, actorType string) Fraud {
 	return Fraud{
 		Description: description,
-		Time:        time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:        TimestampNow(),
 		Actor:       actor.ToActorObject(),
 	}
 }
 
+func CreateIPFraud(corporate Corporate, ip string) Fraud {
+	return Fraud{
+		Description: IP_NOT_ALLOWED,
+		Time:        TimestampNow(),
+		Actor:       corporate.ToActorObject(),
+		CorporateID: corporate.ID,
+		IPAddress:   ip,
+	}
+}
+
+func CreateFraudDecision(corporateID primitive.ObjectID, actor ActorAble, transaction Transaction,
+	score int, rules []string, decision string) Fraud {
+	description := TRANSACTION_RISK_ASSESSED
+	if decision == FRAUD_DECISION_REVIEW {
+		description = TRANSACTION_HELD
+	} else if decision == FRAUD_DECISION_BLOCK {
+		description = TRANSACTION_BLOCKED
+	}
+
+	fraud := Fraud{
+		Description:     description,
+		Time:            TimestampNow(),
+		Actor:           actor.ToActorObject(),
+		CorporateID:     corporateID,
+		TransactionCode: transaction.TransactionCode,
+		Score:           score,
+		Rules:           rules,
+		Decision:        decision,
+	}
+
+	// Keep snapshot so reviewer can see what was requested
+	if decision == FRAUD_DECISION_REVIEW {
+		fraud.ReviewStatus = FRAUD_REVIEW_PENDING
+		fraud.Transaction = &transaction
+	}
+
+	return fraud
+}
+
 // Interface for mongo document result
 func (domain *Fraud) SetDocumentID(ID primitive.ObjectID) {
 	domain.ID = ID
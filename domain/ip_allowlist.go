@@ -0,0 +1,28 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const IP_ALLOWLIST_HISTORY_COLLECTION string = "ip_allowlist_history"
+
+// Audit of every change on Corporate.WhitelistIP
+type IPAllowlistHistory struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	Actor       ActorObject        `json:"actor" bson:"actor"`
+	Previous    []string           `json:"previous" bson:"previous"`
+	Current     []string           `json:"current" bson:"current"`
+	Time        Timestamp          `json:"time" bson:"time"`
+}
+
+// Interface for mongo document result
+func (domain *IPAllowlistHistory) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *IPAllowlistHistory) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *IPAllowlistHistory) CollectionName() string {
+	return IP_ALLOWLIST_HISTORY_COLLECTION
+}
@@ -0,0 +1,195 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const KYC_CASE_COLLECTION string = "kyc_case"
+
+const (
+	KYC_TIER_BASIC        = "basic"
+	KYC_TIER_FULL         = "full"
+	KYC_TIER_ORGANIZATION = "organization"
+)
+
+const (
+	KYC_STATUS_SUBMITTED        = "Submitted"
+	KYC_STATUS_IN_REVIEW        = "InReview"
+	KYC_STATUS_APPROVED         = "Approved"
+	KYC_STATUS_REJECTED         = "Rejected"
+	KYC_STATUS_MORE_INFO_NEEDED = "MoreInfoNeeded"
+)
+
+const (
+	KYC_ACTION_SUBMIT       = "SUBMIT"
+	KYC_ACTION_RESUBMIT     = "RESUBMIT"
+	KYC_ACTION_START_REVIEW = "START_REVIEW"
+	KYC_ACTION_APPROVE      = "APPROVE"
+	KYC_ACTION_REJECT       = "REJECT"
+	KYC_ACTION_REQUEST_INFO = "REQUEST_INFO"
+)
+
+const (
+	KYC_DOCUMENT_IDENTITY = "identity"
+	KYC_DOCUMENT_SELFIE   = "selfie"
+	KYC_DOCUMENT_AKTA     = "akta"
+	KYC_DOCUMENT_NPWP     = "npwp"
+	KYC_DOCUMENT_NIB      = "nib"
+)
+
+// Selfie is not uploaded, it is the face matched by eKYC against the NIK
+var KYC_REQUIRED_DOCUMENTS = map[string][]string{
+	KYC_TIER_BASIC:        {KYC_DOCUMENT_IDENTITY},
+	KYC_TIER_FULL:         {KYC_DOCUMENT_IDENTITY, KYC_DOCUMENT_SELFIE},
+	KYC_TIER_ORGANIZATION: {KYC_DOCUMENT_IDENTITY, KYC_DOCUMENT_AKTA, KYC_DOCUMENT_NPWP, KYC_DOCUMENT_NIB},
+}
+
+// Higher rank include every right of the lower one
+var KYC_TIER_RANK = map[string]int{
+	KYC_TIER_BASIC:        1,
+	KYC_TIER_FULL:         2,
+	KYC_TIER_ORGANIZATION: 3,
+}
+
+// Transaction type an actor can create with the granted tier
+var KYC_TIER_TRANSACTION_TYPES = map[string][]string{
+	KYC_TIER_BASIC: {TRANSFER_WALLET, PAY_QR, BILLER},
+	KYC_TIER_FULL:  {TRANSFER_WALLET, PAY_QR, BILLER, TRANSFER_BANK, TRANSFER_CASH, DEDUCT},
+	KYC_TIER_ORGANIZATION: {TRANSFER_WALLET, PAY_QR, BILLER, TRANSFER_BANK, TRANSFER_CASH, DEDUCT,
+		ACCEPT_PAYMENT_CARD},
+}
+
+type KYCCase struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID        primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	UserID             primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Tier               string             `json:"tier" bson:"tier"`
+	Status             string             `json:"status" bson:"status"`
+	NIK                string             `json:"nik" bson:"nik,omitempty"`
+	LegalName          string             `json:"legal_name" bson:"legal_name,omitempty"`
+	LegalAddress       string             `json:"legal_address" bson:"legal_address,omitempty"`
+	DigitalID          string             `json:"-" bson:"digital_id,omitempty"`
+	DeviceID           string             `json:"-" bson:"device_id,omitempty"`
+	Documents          []KYCDocument      `json:"documents" bson:"documents"`
+	RequestedDocuments []string           `json:"requested_documents" bson:"requested_documents,omitempty"`
+	Reason             string             `json:"reason" bson:"reason,omitempty"`
+	Reviewer           ActorObject        `json:"reviewer" bson:"reviewer,omitempty"`
+	Actions            []KYCAction        `json:"actions" bson:"actions"`
+	Time               Timestamp          `json:"time" bson:"time"`
+	UpdatedTime        Timestamp          `json:"updated_time" bson:"updated_time"`
+}
+
+// Selfie File is the provider and transaction of the eKYC match, Score is
+// the match confidence
+type KYCDocument struct {
+	Type  string    `json:"type" bson:"type"`
+	File  string    `json:"file" bson:"file"`
+	Score float64   `json:"score,omitempty" bson:"score,omitempty"`
+	Time  Timestamp `json:"time" bson:"time"`
+}
+
+// Identity verification setting of corporate, empty Provider use EKYC_PROVIDER
+// and zero threshold use the default threshold
+type IdentitySetting struct {
+	Provider          string  `json:"provider" bson:"provider,omitempty"`
+	MatchThreshold    float64 `json:"match_threshold" bson:"match_threshold,omitempty"`
+	LivenessThreshold float64 `json:"liveness_threshold" bson:"liveness_threshold,omitempty"`
+}
+
+// Every state change, kept inside the case as audit trail
+type KYCAction struct {
+	Actor      ActorObject `json:"actor" bson:"actor"`
+	Action     string      `json:"action" bson:"action"`
+	FromStatus string      `json:"from_status" bson:"from_status,omitempty"`
+	ToStatus   string      `json:"to_status" bson:"to_status"`
+	Note       string      `json:"note" bson:"note,omitempty"`
+	Time       Timestamp   `json:"time" bson:"time"`
+}
+
+func IsKYCTier(tier string) bool {
+	_, ok := KYC_TIER_RANK[tier]
+	return ok
+}
+
+func IsKYCDocument(documentType string) bool {
+	switch documentType {
+	case KYC_DOCUMENT_IDENTITY, KYC_DOCUMENT_SELFIE, KYC_DOCUMENT_AKTA, KYC_DOCUMENT_NPWP, KYC_DOCUMENT_NIB:
+		return true
+	}
+
+	return false
+}
+
+func IsTransactionAllowedForTier(tier string, transactionType string) bool {
+	for _, element := range KYC_TIER_TRANSACTION_TYPES[tier] {
+		if element == transactionType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Case still waiting for reviewer or user
+func (self KYCCase) IsOpen() bool {
+	return self.Status == KYC_STATUS_SUBMITTED || self.Status == KYC_STATUS_IN_REVIEW ||
+		self.Status == KYC_STATUS_MORE_INFO_NEEDED
+}
+
+func (self KYCCase) Document(documentType string) (KYCDocument, bool) {
+	for _, document := range self.Documents {
+		if document.Type == documentType {
+			return document, true
+		}
+	}
+
+	return KYCDocument{}, false
+}
+
+func (self KYCCase) MissingDocuments() []string {
+	missing := []string{}
+	for _, documentType := range KYC_REQUIRED_DOCUMENTS[self.Tier] {
+		if _, ok := self.Document(documentType); !ok {
+			missing = append(missing, documentType)
+		}
+	}
+
+	return missing
+}
+
+// Replace document of the same type
+func (self *KYCCase) PutDocument(document KYCDocument) {
+	for index, element := range self.Documents {
+		if element.Type == document.Type {
+			self.Documents[index] = document
+			return
+		}
+	}
+
+	self.Documents = append(self.Documents, document)
+}
+
+func (self *KYCCase) AddAction(actor ActorObject, action string, toStatus string, note string, time Timestamp) {
+	self.Actions = append(self.Actions, KYCAction{
+		Actor:      actor,
+		Action:     action,
+		FromStatus: self.Status,
+		ToStatus:   toStatus,
+		Note:       note,
+		Time:       time,
+	})
+
+	self.Status = toStatus
+	self.UpdatedTime = time
+}
+
+// Interface for mongo document result
+func (domain *KYCCase) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *KYCCase) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *KYCCase) CollectionName() string {
+	return KYC_CASE_COLLECTION
+}
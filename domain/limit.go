@@ -0,0 +1,81 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const LIMIT_COLLECTION string = "limit"
+
+// One document per limited owner, written by every commit checking the owner
+// rolling outflow so two of them never pass the check together
+const LIMIT_USAGE_COLLECTION string = "limit_usage"
+
+// Beside unverified, tier of a limit is a KYC tier. Verified is kept for rule
+// created before KYC tier and match every granted tier.
+const (
+	LIMIT_TIER_UNVERIFIED = "unverified"
+	LIMIT_TIER_VERIFIED   = "verified"
+)
+
+// Wildcard for actor type, tier or transaction type
+const LIMIT_ANY = "*"
+
+// Transaction type which reduce the source balance, used for rolling outflow
+var OUTFLOW_TRANSACTION_TYPES = []string{
+	TRANSFER_WALLET,
+	TRANSFER_BANK,
+	TRANSFER_CASH,
+	DEDUCT,
+	PAY_QR,
+	BILLER,
+}
+
+// Zero value mean no limit
+type Limit struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID     primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
+	ActorType       string             `json:"actor_type" bson:"actor_type,omitempty"`
+	Tier            string             `json:"tier" bson:"tier,omitempty"`
+	TransactionType string             `json:"transaction_type" bson:"transaction_type,omitempty"`
+	Minimum         int                `json:"minimum" bson:"minimum"`
+	Maximum         int                `json:"maximum" bson:"maximum"`
+	DailyOutflow    int                `json:"daily_outflow" bson:"daily_outflow"`
+	MonthlyOutflow  int                `json:"monthly_outflow" bson:"monthly_outflow"`
+	MaximumBalance  int                `json:"maximum_balance" bson:"maximum_balance"`
+	Audit           Audit              `json:"-" bson:"audit,omitempty"`
+}
+
+// Specificity used to pick the closest limit when several rule match
+func (self Limit) Specificity() int {
+	result := 0
+	if self.ActorType != LIMIT_ANY {
+		result += 8
+	}
+
+	if self.TransactionType != LIMIT_ANY {
+		result += 4
+	}
+
+	if self.Tier == LIMIT_TIER_VERIFIED {
+		result += 1
+	} else if self.Tier != LIMIT_ANY {
+		result += 2
+	}
+
+	return result
+}
+
+func IsLimitTier(tier string) bool {
+	return tier == LIMIT_ANY || tier == LIMIT_TIER_UNVERIFIED || tier == LIMIT_TIER_VERIFIED || IsKYCTier(tier)
+}
+
+// Interface for mongo document result
+func (domain *Limit) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *Limit) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *Limit) CollectionName() string {
+	return LIMIT_COLLECTION
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const LOCKOUT_AUDIT_COLLECTION string = "lockout_audit"
+
+const (
+	LOCKOUT_ACTION_LOCK         = "LOCK"
+	LOCKOUT_ACTION_SELF_UNLOCK  = "SELF_UNLOCK"
+	LOCKOUT_ACTION_ADMIN_UNLOCK = "ADMIN_UNLOCK"
+)
+
+const (
+	LOCKOUT_REASON_ACCESS_ATTEMPT = "Access attempt exceeded"
+	LOCKOUT_REASON_LOGIN_ATTEMPT  = "Login attempt exceeded"
+	LOCKOUT_REASON_UNLOCK_CODE    = "Unlock code verified"
+)
+
+// Lockout is separated from Active, Active is only changed by activation or
+// admin while Lockout follow failed attempt. Empty LockedUntil lock forever
+// until unlocked by OTP or admin.
+type Lockout struct {
+	Locked      bool      `json:"locked" bson:"locked"`
+	LockedUntil Timestamp `json:"locked_until" bson:"locked_until,omitempty"`
+	LockCount   int       `json:"lock_count" bson:"lock_count"`
+}
+
+func (self Lockout) IsActive() bool {
+	if !self.Locked {
+		return false
+	}
+
+	if self.LockedUntil.IsZero() {
+		return true
+	}
+
+	return time.Now().Before(self.LockedUntil.Time)
+}
+
+type LockoutAudit struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	Subject     ActorObject        `json:"subject" bson:"subject"`
+	Actor       ActorObject        `json:"actor" bson:"actor"`
+	Action      string             `json:"action" bson:"action"`
+	Reason      string             `json:"reason" bson:"reason,omitempty"`
+	LockedUntil Timestamp          `json:"locked_until" bson:"locked_until,omitempty"`
+	Time        Timestamp          `json:"time" bson:"time"`
+}
+
+func CreateLockoutAudit(corporateID primitive.ObjectID, subject ActorObject, actor ActorObject,
+	action string, reason string, lockedUntil Timestamp) *LockoutAudit {
+	return &LockoutAudit{
+		CorporateID: corporateID,
+		Subject:     subject,
+		Actor:       actor,
+		Action:      action,
+		Reason:      reason,
+		LockedUntil: lockedUntil,
+		Time:        TimestampNow(),
+	}
+}
+
+// Interface for mongo document result
+func (domain *LockoutAudit) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *LockoutAudit) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *LockoutAudit) CollectionName() string {
+	return LOCKOUT_AUDIT_COLLECTION
+}
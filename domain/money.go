@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	CURRENCY_IDR = "idr"
+	CURRENCY_USD = "usd"
+	CURRENCY_SGD = "sgd"
+	CURRENCY_MYR = "myr"
+	CURRENCY_EUR = "eur"
+	CURRENCY_JPY = "jpy"
+)
+
+const (
+	ROUNDING_HALF_EVEN = "HALF_EVEN"
+	ROUNDING_HALF_UP   = "HALF_UP"
+	ROUNDING_DOWN      = "DOWN"
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+var ErrInvalidRate = errors.New("invalid rate")
+
+// Number of minor unit digits per ISO 4217 code. IDR is kept at 0 because
+// balances, VA and bank transfer rails settle in whole rupiah, gateway with
+// another exponent (Stripe use 2 for IDR) convert at its own boundary.
+var currencyExponent = map[string]int{
+	CURRENCY_IDR: 0,
+	CURRENCY_USD: 2,
+	CURRENCY_SGD: 2,
+	CURRENCY_MYR: 2,
+	CURRENCY_EUR: 2,
+	CURRENCY_JPY: 0,
+}
+
+// Money is an amount in the minor unit of its currency (cent for usd, rupiah for idr)
+type Money struct {
+	Amount   int    `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+func NewMoney(amount int, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: NormalizeCurrency(currency),
+	}
+}
+
+// Currency is stored lowercase as used by the payment gateways
+func NormalizeCurrency(currency string) string {
+	return strings.ToLower(strings.TrimSpace(currency))
+}
+
+func IsSupportedCurrency(currency string) bool {
+	_, ok := currencyExponent[NormalizeCurrency(currency)]
+	return ok
+}
+
+func CurrencyExponent(currency string) (int, error) {
+	exponent, ok := currencyExponent[NormalizeCurrency(currency)]
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	return exponent, nil
+}
+
+// Empty currency is treated as legacy document which not yet migrated
+func IsSameCurrency(a string, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+
+	return NormalizeCurrency(a) == NormalizeCurrency(b)
+}
+
+func (money Money) IsZero() bool {
+	return money.Amount == 0
+}
+
+func (money Money) Add(other Money) (Money, error) {
+	if !IsSameCurrency(money.Currency, other.Currency) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return NewMoney(money.Amount+other.Amount, money.pickCurrency(other)), nil
+}
+
+func (money Money) Sub(other Money) (Money, error) {
+	if !IsSameCurrency(money.Currency, other.Currency) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return NewMoney(money.Amount-other.Amount, money.pickCurrency(other)), nil
+}
+
+// Percentage multiply amount with decimal rate (ex: "0.029" for 2.9%) without
+// going through float, then round to minor unit with the given policy
+func (money Money) Percentage(rate string, rounding string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok {
+		return Money{}, ErrInvalidRate
+	}
+
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(money.Amount)), r)
+	amount := roundRat(product, rounding)
+
+	return NewMoney(int(amount), money.Currency), nil
+}
+
+// String format amount with major unit, ex: 12.50 usd
+func (money Money) String() string {
+	exponent, err := CurrencyExponent(money.Currency)
+	if err != nil || exponent == 0 {
+		return strconv.Itoa(money.Amount) + " " + money.Currency
+	}
+
+	value := new(big.Rat).SetFrac64(int64(money.Amount), pow10(exponent))
+	return value.FloatString(exponent) + " " + money.Currency
+}
+
+func (money Money) pickCurrency(other Money) string {
+	if money.Currency != "" {
+		return money.Currency
+	}
+
+	return other.Currency
+}
+
+func roundRat(value *big.Rat, rounding string) int64 {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() == 0 || rounding == ROUNDING_DOWN {
+		return quotient.Int64()
+	}
+
+	// compare 2*|remainder| with denominator to know position from half
+	doubled := new(big.Int).Abs(remainder)
+	doubled.Lsh(doubled, 1)
+	half := doubled.Cmp(value.Denom())
+
+	awayFromZero := half > 0
+	if half == 0 {
+		if rounding == ROUNDING_HALF_UP {
+			awayFromZero = true
+		} else {
+			awayFromZero = quotient.Bit(0) == 1
+		}
+	}
+
+	if awayFromZero {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient.Int64()
+}
+
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result = result * 10
+	}
+
+	return result
+}
@@ -0,0 +1,108 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const MESSAGE_TEMPLATE_COLLECTION string = "message_template"
+const NOTIFICATION_LOG_COLLECTION string = "notification_log"
+
+const (
+	NOTIFICATION_STATUS_SENT   = "Sent"
+	NOTIFICATION_STATUS_FAILED = "Failed"
+)
+
+const (
+	LANGUAGE_ENGLISH    = "en"
+	LANGUAGE_INDONESIAN = "id"
+	DEFAULT_LANGUAGE    = LANGUAGE_ENGLISH
+)
+
+// Placeholder replaced when template is rendered
+const (
+	TEMPLATE_BRAND_PLACEHOLDER  = "{brand}"
+	TEMPLATE_CODE_PLACEHOLDER   = "{code}"
+	TEMPLATE_MINUTE_PLACEHOLDER = "{minute}"
+)
+
+// Template key is the OTP purpose
+var DEFAULT_MESSAGE_TEMPLATES = map[string]map[string]string{
+	LANGUAGE_ENGLISH: {
+		OTP_PURPOSE_ACTIVATION: "{brand}: your signup code is {code}, valid for {minute} minutes. Never share this code.",
+		OTP_PURPOSE_LOGIN:      "{brand}: your login code is {code}, valid for {minute} minutes. Never share this code.",
+		OTP_PURPOSE_FORGOT_PIN: "{brand}: your forgot PIN code is {code}, valid for {minute} minutes. Never share this code.",
+		OTP_PURPOSE_UNLOCK:     "{brand}: your unlock code is {code}, valid for {minute} minutes. Never share this code.",
+	},
+	LANGUAGE_INDONESIAN: {
+		OTP_PURPOSE_ACTIVATION: "{brand}: kode pendaftaran Anda {code}, berlaku {minute} menit. Jangan berikan kode ini kepada siapa pun.",
+		OTP_PURPOSE_LOGIN:      "{brand}: kode masuk Anda {code}, berlaku {minute} menit. Jangan berikan kode ini kepada siapa pun.",
+		OTP_PURPOSE_FORGOT_PIN: "{brand}: kode lupa PIN Anda {code}, berlaku {minute} menit. Jangan berikan kode ini kepada siapa pun.",
+		OTP_PURPOSE_UNLOCK:     "{brand}: kode buka blokir Anda {code}, berlaku {minute} menit. Jangan berikan kode ini kepada siapa pun.",
+	},
+}
+
+// Messaging setting of corporate, empty Providers allow every provider with
+// the default order and empty BrandName use corporate name
+type MessagingSetting struct {
+	Providers []string `json:"providers" bson:"providers,omitempty"`
+	Language  string   `json:"language" bson:"language,omitempty"`
+	BrandName string   `json:"brand_name" bson:"brand_name,omitempty"`
+}
+
+// Template of corporate replacing the default one for the same key and language
+type MessageTemplate struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	Key         string             `json:"key" bson:"key"`
+	Language    string             `json:"language" bson:"language"`
+	Text        string             `json:"text" bson:"text"`
+	Actor       ActorObject        `json:"actor" bson:"actor"`
+	Time        Timestamp          `json:"time" bson:"time"`
+}
+
+// Every attempt to a provider, rendered text is not kept because it contain the code
+type NotificationLog struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id,omitempty"`
+	Key         string             `json:"key" bson:"key"`
+	Language    string             `json:"language" bson:"language"`
+	Provider    string             `json:"provider" bson:"provider"`
+	Destination string             `json:"destination" bson:"destination"`
+	Status      string             `json:"status" bson:"status"`
+	Error       string             `json:"error" bson:"error,omitempty"`
+	Time        Timestamp          `json:"time" bson:"time"`
+}
+
+func IsMessageTemplateKey(key string) bool {
+	_, ok := DEFAULT_MESSAGE_TEMPLATES[DEFAULT_LANGUAGE][key]
+	return ok
+}
+
+func IsLanguage(language string) bool {
+	_, ok := DEFAULT_MESSAGE_TEMPLATES[language]
+	return ok
+}
+
+// Interface for mongo document result
+func (domain *MessageTemplate) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *MessageTemplate) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *MessageTemplate) CollectionName() string {
+	return MESSAGE_TEMPLATE_COLLECTION
+}
+
+func (domain *NotificationLog) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *NotificationLog) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *NotificationLog) CollectionName() string {
+	return NOTIFICATION_LOG_COLLECTION
+}
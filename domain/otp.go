@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const OTP_COLLECTION string = "otp"
+
+const (
+	OTP_PURPOSE_ACTIVATION = "activation"
+	OTP_PURPOSE_LOGIN      = "login"
+	OTP_PURPOSE_FORGOT_PIN = "forgot_pin"
+	OTP_PURPOSE_UNLOCK     = "unlock"
+	// Temporary PIN given after a face match, it is returned instead of sent
+	OTP_PURPOSE_TEMPORARY_PIN = "temporary_pin"
+)
+
+// One code per user and purpose, a resend replace the code. Code is stored
+// hashed and DeleteAt is a native date so TTL index can remove the document
+// once both the code and the resend window are over.
+type OTP struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Purpose     string             `json:"purpose" bson:"purpose"`
+	Destination string             `json:"destination" bson:"destination"`
+	CodeHash    string             `json:"-" bson:"code_hash"`
+	Attempt     int                `json:"attempt" bson:"attempt"`
+	SentCount   int                `json:"sent_count" bson:"sent_count"`
+	Used        bool               `json:"used" bson:"used"`
+	FirstSentAt time.Time          `json:"first_sent_at" bson:"first_sent_at"`
+	LastSentAt  time.Time          `json:"last_sent_at" bson:"last_sent_at"`
+	ExpiredAt   time.Time          `json:"expired_at" bson:"expired_at"`
+	DeleteAt    time.Time          `json:"-" bson:"delete_at"`
+}
+
+func (self OTP) IsExpired() bool {
+	return self.Used || time.Now().After(self.ExpiredAt)
+}
+
+// Interface for mongo document result
+func (domain *OTP) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *OTP) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *OTP) CollectionName() string {
+	return OTP_COLLECTION
+}
@@ -1,9 +1,6 @@
 package domain
 
 import (
-	"os"
-	"time"
-
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -26,7 +23,7 @@ type Request struct {
 	To              TransactionObject  `json:"to" bson:"to,omitempty"`
 	Amount          int                `json:"amount" bson:"amount"`
 	TransactionCode string             `json:"transaction_code" bson:"transaction_code,omitempty"`
-	Time            string             `json:"time" bson:"time,omitempty"`
+	Time            Timestamp          `json:"time" bson:"time,omitempty"`
 	IsRead          bool               `json:"is_read" bson:"is_read"`
 	Message         string             `json:"message" bson:"message,omitempty"`
 }
@@ -51,7 +48,7 @@ func CreateRequest(corporateID primitive.ObjectID, fromUser User,
 			AccountNumber:   toUser.PhoneNumber,
 		},
 		Amount: amount,
-		Time:   time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:   TimestampNow(),
 		IsRead: false,
 	}, nil
 }
@@ -14,7 +14,7 @@ type RequestAccessBalance struct {
 	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	CorporateID      primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
 	BalanceID        primitive.ObjectID `json:"balance_id" bson:"balance_id,omitempty"`
-	Time             string             `json:"time" bson:"time,omitempty"`
+	Time             Timestamp          `json:"time" bson:"time,omitempty"`
 	BalanceRequester ActorObject        `json:"balance_requester" bson:"balance_requester,omitempty"`
 	BalanceOwner     ActorObject        `json:"balance_owner" bson:"balance_owner,omitempty"`
 	Access           string             `json:"access" bson:"access"`
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const REQUEST_NONCE_COLLECTION string = "request_nonce"
+
+// RequestID seen from a corporate, removed by TTL index once ExpiredAt passed.
+// ExpiredAt is a native date because TTL index does not work on string.
+type RequestNonce struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id"`
+	RequestID   string             `json:"request_id" bson:"request_id"`
+	Time        Timestamp          `json:"time" bson:"time"`
+	ExpiredAt   time.Time          `json:"expired_at" bson:"expired_at"`
+}
+
+// Interface for mongo document result
+func (domain *RequestNonce) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *RequestNonce) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *RequestNonce) CollectionName() string {
+	return REQUEST_NONCE_COLLECTION
+}
@@ -0,0 +1,119 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const ROLE_COLLECTION string = "role"
+
+const (
+	ROLE_VIEWER   = "viewer"
+	ROLE_OPERATOR = "operator"
+	ROLE_APPROVER = "approver"
+	ROLE_ADMIN    = "admin"
+)
+
+const (
+	PERMISSION_BALANCE_VIEW         = "balance.view"
+	PERMISSION_TRANSACTION_VIEW     = "transaction.view"
+	PERMISSION_TRANSACTION_CREATE   = "transaction.create"
+	PERMISSION_TRANSACTION_REVIEW   = "transaction.review"
+	PERMISSION_BULK_CREATE          = "bulk.create"
+	PERMISSION_BULK_APPROVE         = "bulk.approve"
+	PERMISSION_BULK_EXECUTE         = "bulk.execute"
+	PERMISSION_BULK_APPROVAL_MANAGE = "bulk_approval.manage"
+	PERMISSION_LIMIT_MANAGE         = "limit.manage"
+	PERMISSION_ROLE_MANAGE          = "role.manage"
+	PERMISSION_CORPORATE_MANAGE     = "corporate.manage"
+	PERMISSION_FRAUD_REVIEW_VIEW    = "fraud.view"
+	PERMISSION_TRANSACTION_EXPORT   = "transaction.export"
+	PERMISSION_USER_MANAGE          = "user.manage"
+	PERMISSION_CALLBACK_MANAGE      = "callback.manage"
+	PERMISSION_BULK_INQUIRY_CREATE  = "bulk_inquiry.create"
+	PERMISSION_KYC_REVIEW           = "kyc.review"
+)
+
+var PERMISSIONS = []string{
+	PERMISSION_BALANCE_VIEW,
+	PERMISSION_TRANSACTION_VIEW,
+	PERMISSION_TRANSACTION_CREATE,
+	PERMISSION_TRANSACTION_REVIEW,
+	PERMISSION_BULK_CREATE,
+	PERMISSION_BULK_APPROVE,
+	PERMISSION_BULK_EXECUTE,
+	PERMISSION_BULK_APPROVAL_MANAGE,
+	PERMISSION_LIMIT_MANAGE,
+	PERMISSION_ROLE_MANAGE,
+	PERMISSION_CORPORATE_MANAGE,
+	PERMISSION_FRAUD_REVIEW_VIEW,
+	PERMISSION_TRANSACTION_EXPORT,
+	PERMISSION_USER_MANAGE,
+	PERMISSION_CALLBACK_MANAGE,
+	PERMISSION_BULK_INQUIRY_CREATE,
+	PERMISSION_KYC_REVIEW,
+}
+
+// Used when corporate does not define its own permission for the role
+var DEFAULT_ROLE_PERMISSIONS = map[string][]string{
+	ROLE_VIEWER: {
+		PERMISSION_BALANCE_VIEW,
+		PERMISSION_TRANSACTION_VIEW,
+	},
+	ROLE_OPERATOR: {
+		PERMISSION_BALANCE_VIEW,
+		PERMISSION_TRANSACTION_VIEW,
+		PERMISSION_TRANSACTION_CREATE,
+		PERMISSION_TRANSACTION_EXPORT,
+		PERMISSION_BULK_CREATE,
+		PERMISSION_BULK_EXECUTE,
+		PERMISSION_BULK_INQUIRY_CREATE,
+	},
+	ROLE_APPROVER: {
+		PERMISSION_BALANCE_VIEW,
+		PERMISSION_TRANSACTION_VIEW,
+		PERMISSION_TRANSACTION_REVIEW,
+		PERMISSION_FRAUD_REVIEW_VIEW,
+		PERMISSION_BULK_APPROVE,
+		PERMISSION_KYC_REVIEW,
+	},
+	ROLE_ADMIN: PERMISSIONS,
+}
+
+type Role struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CorporateID primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
+	Name        string             `json:"name" bson:"name,omitempty"`
+	Permissions []string           `json:"permissions" bson:"permissions"`
+	Audit       Audit              `json:"-" bson:"audit,omitempty"`
+}
+
+func IsPermission(permission string) bool {
+	for _, a := range PERMISSIONS {
+		if a == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self Role) HasPermission(permission string) bool {
+	for _, a := range self.Permissions {
+		if a == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Interface for mongo document result
+func (domain *Role) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *Role) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *Role) CollectionName() string {
+	return ROLE_COLLECTION
+}
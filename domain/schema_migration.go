@@ -0,0 +1,27 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const SCHEMA_MIGRATION_COLLECTION string = "schema_migration"
+const SCHEMA_MIGRATION_LOCK_COLLECTION string = "schema_migration_lock"
+
+// Version of the schema applied by the migration runner
+type SchemaMigration struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Version     int                `json:"version" bson:"version"`
+	Description string             `json:"description" bson:"description"`
+	AppliedTime Timestamp          `json:"applied_time" bson:"applied_time"`
+}
+
+// Interface for mongo document result
+func (domain *SchemaMigration) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *SchemaMigration) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *SchemaMigration) CollectionName() string {
+	return SCHEMA_MIGRATION_COLLECTION
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const SESSION_COLLECTION string = "session"
+const REVOKED_TOKEN_COLLECTION string = "revoked_token"
+
+// Session is created per login and device, refresh token is rotated on every
+// refresh and only its hash is stored
+type Session struct {
+	ID                       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID                   primitive.ObjectID `json:"user_id" bson:"user_id,omitempty"`
+	CorporateID              primitive.ObjectID `json:"corporate_id" bson:"corporate_id,omitempty"`
+	DeviceID                 string             `json:"device_id" bson:"device_id"`
+	RefreshTokenHash         string             `json:"-" bson:"refresh_token_hash"`
+	PreviousRefreshTokenHash string             `json:"-" bson:"previous_refresh_token_hash,omitempty"`
+	TokenID                  string             `json:"-" bson:"token_id"`
+	TokenExpiredTime         Timestamp          `json:"-" bson:"token_expired_time"`
+	CreatedTime              Timestamp          `json:"created_time" bson:"created_time"`
+	LastRefreshTime          Timestamp          `json:"last_refresh_time" bson:"last_refresh_time"`
+	ExpiredTime              Timestamp          `json:"expired_time" bson:"expired_time"`
+	Revoked                  bool               `json:"revoked" bson:"revoked"`
+	RevokedTime              Timestamp          `json:"revoked_time,omitempty" bson:"revoked_time,omitempty"`
+	Current                  bool               `json:"current" bson:"-"`
+}
+
+// Access token revoked before its expiry, checked on every secure request
+type RevokedToken struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TokenID     string             `json:"token_id" bson:"token_id"`
+	ExpiredTime Timestamp          `json:"expired_time" bson:"expired_time"`
+	Time        Timestamp          `json:"time" bson:"time"`
+}
+
+type AuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	SessionID    string `json:"session_id"`
+}
+
+// Interface for mongo document result
+func (domain *Session) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *Session) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *Session) CollectionName() string {
+	return SESSION_COLLECTION
+}
+
+// Interface for mongo document result
+func (domain *RevokedToken) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *RevokedToken) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *RevokedToken) CollectionName() string {
+	return REVOKED_TOKEN_COLLECTION
+}
@@ -11,13 +11,48 @@ const (
 type Statement struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	BalanceID   primitive.ObjectID `json:"balance_id" bson:"balance_id,omitempty"`
-	Time        string             `json:"time" bson:"time,omitempty"`
+	Time        Timestamp          `json:"time" bson:"time,omitempty"`
 	Description string             `json:"description" bson:"description,omitempty"`
 	Reference   string             `json:"reference" bson:"reference,omitempty"`
 	Withdraw    int                `json:"withdraw" bson:"withdraw"`
 	Deposit     int                `json:"deposit" bson:"deposit"`
 	Balance     int                `json:"balance" bson:"balance"`
+	Currency    string             `json:"currency" bson:"currency,omitempty"`
 	Type        string             `json:"type" bson:"type,omitempty"`
+
+	// Sequence of the balance adjust which wrote the statement, start from 1
+	// and has no gap per balance. Statement written before has none.
+	Sequence int64 `json:"sequence" bson:"sequence,omitempty"`
+
+	// Sub balance the deposit is written to, sequence is then of the sub
+	// balance. Zero is the balance itself.
+	SubBalance int `json:"-" bson:"sub_balance,omitempty"`
+}
+
+// Result of checking the statement sequence of a balance against its counter
+type StatementSequenceCheck struct {
+	BalanceID    primitive.ObjectID `json:"balance_id"`
+	SubBalance   int                `json:"sub_balance"`
+	LastSequence int64              `json:"last_sequence"`
+	Missing      []int64            `json:"missing"`
+	Duplicated   []int64            `json:"duplicated"`
+}
+
+func (self StatementSequenceCheck) IsConsistent() bool {
+	return len(self.Missing) == 0 && len(self.Duplicated) == 0
+}
+
+// Amounts are in the minor unit of the statement currency
+func (self Statement) DepositMoney() Money {
+	return NewMoney(self.Deposit, self.Currency)
+}
+
+func (self Statement) WithdrawMoney() Money {
+	return NewMoney(self.Withdraw, self.Currency)
+}
+
+func (self Statement) BalanceMoney() Money {
+	return NewMoney(self.Balance, self.Currency)
 }
 
 // Base interface
@@ -0,0 +1,33 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const SUB_BALANCE_COLLECTION string = "sub_balance"
+
+// Part of a fee collecting balance. Collected fee is deposited to one of the
+// sub balances so concurrent transactions do not all write the same balance,
+// the amount of the balance is its own amount with every sub balance added and
+// the sweep move the sub balances back into the balance. Own amount go below
+// zero when fee not swept yet is spent.
+type SubBalance struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BalanceID         primitive.ObjectID `json:"balance_id" bson:"balance_id"`
+	Index             int                `json:"index" bson:"index"`
+	Amount            int                `json:"amount" bson:"amount"`
+	Currency          string             `json:"currency" bson:"currency,omitempty"`
+	Version           int64              `json:"version" bson:"version"`
+	StatementSequence int64              `json:"statement_sequence" bson:"statement_sequence"`
+}
+
+// Interface for mongo document result
+func (domain *SubBalance) SetDocumentID(ID primitive.ObjectID) {
+	domain.ID = ID
+}
+
+func (domain *SubBalance) GetDocumentID() primitive.ObjectID {
+	return domain.ID
+}
+
+func (domain *SubBalance) CollectionName() string {
+	return SUB_BALANCE_COLLECTION
+}
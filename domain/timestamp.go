@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// JSON timestamp is written as RFC 3339 instead of TIME_FORMAT when
+// TIME_JSON_FORMAT is set to this
+const TIME_JSON_FORMAT_RFC3339 = "rfc3339"
+
+var timeLocations sync.Map
+
+// Timestamp is stored as a BSON date, which is UTC with millisecond precision.
+// TIME_ZONE (IANA name, server local when empty) is the zone it is shown in
+// and the zone of the TIME_FORMAT strings written before dates were stored,
+// those strings are still read. JSON keep the TIME_FORMAT string so clients
+// see no change until TIME_JSON_FORMAT is switched to rfc3339.
+type Timestamp struct {
+	time.Time
+}
+
+func TimestampNow() Timestamp {
+	return NewTimestamp(time.Now())
+}
+
+// Timestamp of t cut to what a BSON date keep, so it compare equal after a
+// round trip to the database
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t.Truncate(time.Millisecond)}
+}
+
+// Parse TIME_FORMAT in TIME_ZONE or RFC 3339, empty value is the zero timestamp
+func ParseTimestamp(value string) (Timestamp, error) {
+	if value == "" {
+		return Timestamp{}, nil
+	}
+
+	parsed, err := time.ParseInLocation(timeLayout(), value, TimeLocation())
+	if err != nil {
+		parsed, err = time.Parse(time.RFC3339Nano, value)
+	}
+
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("invalid timestamp %v", value)
+	}
+
+	return NewTimestamp(parsed), nil
+}
+
+// Zone timestamps are shown and legacy strings are read in
+func TimeLocation() *time.Location {
+	name := os.Getenv("TIME_ZONE")
+	if name == "" {
+		return time.Local
+	}
+
+	if location, ok := timeLocations.Load(name); ok {
+		return location.(*time.Location)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+
+	timeLocations.Store(name, location)
+	return location
+}
+
+func timeLayout() string {
+	layout := os.Getenv("TIME_FORMAT")
+	if layout == "" {
+		return time.RFC3339
+	}
+
+	return layout
+}
+
+// TIME_FORMAT in TIME_ZONE, empty for the zero timestamp as the string field was
+func (self Timestamp) String() string {
+	if self.IsZero() {
+		return ""
+	}
+
+	return self.In(TimeLocation()).Format(timeLayout())
+}
+
+func (self Timestamp) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if self.IsZero() {
+		return bsontype.Null, nil, nil
+	}
+
+	return bsontype.DateTime, bsoncore.AppendDateTime(nil, self.UnixMilli()), nil
+}
+
+func (self *Timestamp) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+
+	switch t {
+	case bsontype.DateTime:
+		self.Time = value.Time()
+	case bsontype.String:
+		parsed, err := ParseTimestamp(value.StringValue())
+		if err != nil {
+			return err
+		}
+
+		*self = parsed
+	case bsontype.Null, bsontype.Undefined:
+		*self = Timestamp{}
+	default:
+		return fmt.Errorf("cannot decode %v into timestamp", t)
+	}
+
+	return nil
+}
+
+func (self Timestamp) MarshalJSON() ([]byte, error) {
+	if !self.IsZero() && os.Getenv("TIME_JSON_FORMAT") == TIME_JSON_FORMAT_RFC3339 {
+		return json.Marshal(self.In(TimeLocation()).Format(time.RFC3339))
+	}
+
+	return json.Marshal(self.String())
+}
+
+// Both TIME_FORMAT and RFC 3339 are accepted during the transition
+func (self *Timestamp) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+
+	*self = parsed
+	return nil
+}
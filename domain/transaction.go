@@ -11,6 +11,14 @@ const (
 	COMPLETED_STATUS = "Completed"
 	PENDING_STATUS   = "Pending"
 	FAILED_STATUS    = "Failed"
+	HELD_STATUS      = "Held"
+	REJECTED_STATUS  = "Rejected"
+)
+
+const (
+	REVIEW_ACTION_HELD     = "HELD"
+	REVIEW_ACTION_APPROVED = "APPROVED"
+	REVIEW_ACTION_REJECTED = "REJECTED"
 )
 
 const (
@@ -87,7 +95,7 @@ type Transaction struct {
 	TotalFee          int                `json:"total_fee" bson:"total_fee"`
 	SubAmount         int                `json:"sub_amount" bson:"sub_amount"`
 	Amount            int                `json:"amount" bson:"amount"`
-	Time              string             `json:"time" bson:"time,omitempty"`
+	Time              Timestamp          `json:"time" bson:"time,omitempty"`
 	Notes             string             `json:"notes" bson:"notes"`
 	Status            string             `json:"status" bson:"status"`
 	RerunStatus       string             `json:"rerun_status" bson:"rerun_status"`
@@ -100,6 +108,28 @@ type Transaction struct {
 	GatewayStrategies []GatewayStrategy  `json:"gateway_strategies" bson:"gateway_strategies"`
 	GatewayHistories  []GatewayHistory   `json:"gateway_histories" bson:"gateway_histories"`
 	Currency          string             `json:"currency" bson:"currency,omitempty"`
+	DeviceID          string             `json:"device_id" bson:"device_id,omitempty"`
+	RiskScore         int                `json:"risk_score" bson:"risk_score"`
+	RiskDecision      string             `json:"risk_decision" bson:"risk_decision,omitempty"`
+	HoldReason        string             `json:"hold_reason" bson:"hold_reason,omitempty"`
+	ReviewHistories   []ReviewHistory    `json:"review_histories" bson:"review_histories,omitempty"`
+
+	// Held transaction only apply withdraw statement (reserved), deposit wait for approval
+	ReservedStatements []Statement `json:"-" bson:"reserved_statements"`
+	HeldStatements     []Statement `json:"-" bson:"held_statements"`
+}
+
+// Amounts are in the minor unit of the transaction currency
+func (domain Transaction) AmountMoney() Money {
+	return NewMoney(domain.Amount, domain.Currency)
+}
+
+func (domain Transaction) SubAmountMoney() Money {
+	return NewMoney(domain.SubAmount, domain.Currency)
+}
+
+func (domain Transaction) TotalFeeMoney() Money {
+	return NewMoney(domain.TotalFee, domain.Currency)
 }
 
 // Interface for mongo document result
@@ -121,10 +151,17 @@ type DetailFee struct {
 	Amount      int                `json:"amount" bson:"amount"`
 }
 
+type ReviewHistory struct {
+	Action string      `json:"action" bson:"action"`
+	Actor  ActorObject `json:"actor" bson:"actor"`
+	Reason string      `json:"reason" bson:"reason,omitempty"`
+	Time   Timestamp   `json:"time" bson:"time,omitempty"`
+}
+
 type GatewayHistory struct {
-	Code      string `json:"code" bson:"code"`
-	Reference string `json:"reference" bson:"reference"`
-	Time      string `json:"time" bson:"time,omitempty"`
+	Code      string    `json:"code" bson:"code"`
+	Reference string    `json:"reference" bson:"reference"`
+	Time      Timestamp `json:"time" bson:"time,omitempty"`
 }
 
 type GatewayStrategy struct {
@@ -14,11 +14,8 @@ type User struct {
 	PhoneNumber      string             `json:"phone_number" bson:"phone_number,omitempty"`
 	FullName         string             `json:"full_name" bson:"full_name,omitempty"`
 	PIN              string             `json:"-" bson:"pin,omitempty"`
-	ChangePIN        string             `json:"change_pin" bson:"change_pin,omitempty"`
-	ChangePINCode    string             `json:"change_pin_code" bson:"change_pin_code,omitempty"`
-	LoginCode        string             `json:"_" bson:"login_code,omitempty"`
-	ActivationCode   string             `json:"_" bson:"activation_code,omitempty"`
-	VerificationCode string             `json:"_" bson:"verification_code,omitempty"`
+	ChangePIN        string             `json:"-" bson:"change_pin,omitempty"`
+	VerificationCode string             `json:"-" bson:"verification_code,omitempty"`
 	Active           bool               `json:"active" bson:"active"`
 	Verified         bool               `json:"verified" bson:"verified"`
 	AccessAttempt    int8               `json:"access_attempt" bson:"access_attempt"`
@@ -40,17 +37,24 @@ type User struct {
 	SavedBankAccount []Bank       `json:"saved_bank_account" bson:"saved_bank_account"`
 	UnReadInbox      bool         `json:"unread_inbox"`
 	NIK              string       `json:"nik" bson:"nik"`
-	ImageUpgrade     string       `json:"_" bson:"image_upgrade"`
+	ImageUpgrade     string       `json:"-" bson:"image_upgrade"`
 	Avatar           string       `json:"avatar" bson:"avatar"`
 	Pending          bool         `json:"pending" bson:"pending"`
 	DeviceID         string       `json:"device_id" bson:"device_id,omitempty"`
 	DigitalID        string       `json:"digital_id" bson:"digital_id,omitempty"`
 	FaceAsPIN        bool         `json:"face_as_pin" bson:"face_as_pin"`
-	TemporaryPIN     string       `json:"_" bson:"temporary_pin,omitempty"`
+	PINUpdatedTime   Timestamp    `json:"-" bson:"pin_updated_time,omitempty"`
+	DeviceMovedTime  Timestamp    `json:"-" bson:"device_moved_time,omitempty"`
 	Remittance       RemitAccount `json:"remittance" bson:"remittance"`
 	IsRemittance     bool         `json:"is_remittance" bson:"is_remittance"`
 	IsAgent          bool         `json:"is_agent" bson:"is_agent"`
 	VerifyData       VerifyData   `json:"verify_data" bson:"verify_data"`
+	Role             string       `json:"role" bson:"role,omitempty"`
+	KYCTier          string       `json:"kyc_tier" bson:"kyc_tier,omitempty"`
+	Lockout          Lockout      `json:"lockout" bson:"lockout"`
+
+	// Resolved from role before JWT is generated, never stored
+	Privileges []string `json:"-" bson:"-"`
 }
 
 type VerifyData struct {
@@ -104,16 +108,17 @@ func (domain User) GetIsPinAlreadySet() bool {
 }
 
 func (domain User) IsLocked() bool {
-	// Validate is user locked
-	if domain.Active == false {
-		return true
-	} else {
-		return false
-	}
+	return domain.Active == false || domain.Lockout.IsActive() || domain.IsLegacyLocked()
+}
+
+// Lock before Lockout exist set Active false and reset access attempt, user
+// waiting for activation still have every attempt
+func (domain User) IsLegacyLocked() bool {
+	return domain.Active == false && domain.AccessAttempt == 0
 }
 
 func (domain User) GetAccessLevel() string {
-	return ""
+	return domain.Role
 }
 
 func (domain User) GetCorporateID() string {
@@ -121,7 +126,7 @@ func (domain User) GetCorporateID() string {
 }
 
 func (domain User) GetPrivileges() []string {
-	return nil
+	return domain.Privileges
 }
 
 // TransactionAble interface
@@ -170,10 +175,6 @@ func (self User) GetPIN() string {
 	return self.PIN
 }
 
-func (self User) GetTemporaryPIN() string {
-	return self.TemporaryPIN
-}
-
 func (self User) IsFaceAsPIN() bool {
 	return self.FaceAsPIN
 }
@@ -182,6 +183,20 @@ func (self User) IsVerify() bool {
 	return self.Verified
 }
 
+// Tier granted by KYC review, user verified before KYC case exist keep the
+// tier of the data they submitted
+func (self User) GetKYCTier() string {
+	if self.KYCTier != "" || !self.Verified {
+		return self.KYCTier
+	}
+
+	if self.VerifyData.Type == VERIFY_ORGANIZATION_TYPE {
+		return KYC_TIER_ORGANIZATION
+	}
+
+	return KYC_TIER_FULL
+}
+
 func (self User) ToActorObject() ActorObject {
 	return ActorObject{
 		ID:   self.GetActorID(),
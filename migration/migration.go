@@ -0,0 +1,229 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Runner hold the lock at most this long, a runner which died keep the other
+// waiting only until then
+const MIGRATION_LOCK_TIMEOUT = 30 * time.Minute
+
+const migrationLockID = "migration"
+
+// Mongo error code of creating a collection which already exist
+const namespaceExistsCode = 48
+
+var ErrLocked = errors.New("another migration is running")
+
+// One version of the schema. Collections are created, then indexes, then Run.
+// Every step must be safe to run again because the version is recorded only
+// after all of them succeed, creating an index which already exist with the
+// same keys and options does nothing.
+type Migration struct {
+	Version     int
+	Description string
+	Collections []string
+	Indexes     []Index
+	Run         func(ctx context.Context, db *mongo.Database) error
+}
+
+type Index struct {
+	Collection string
+	Model      mongo.IndexModel
+}
+
+type Status struct {
+	Version     int              `json:"version"`
+	Description string           `json:"description"`
+	Applied     bool             `json:"applied"`
+	AppliedTime domain.Timestamp `json:"applied_time"`
+}
+
+type Runner struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+// Runner of the given migrations, they are applied in version order
+func NewRunner(db *mongo.Database, migrations []Migration) (*Runner, error) {
+	sorted := append([]Migration{}, migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("migration %v has invalid version", migration.Description)
+		}
+
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("migration version %v declared twice", migration.Version)
+		}
+	}
+
+	return &Runner{db: db, migrations: sorted}, nil
+}
+
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Status
+	for _, migration := range r.migrations {
+		record, found := applied[migration.Version]
+		results = append(results, Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     found,
+			AppliedTime: record.AppliedTime,
+		})
+	}
+
+	return results, nil
+}
+
+// Apply every migration not applied yet up to target version, 0 apply all.
+// Return the versions applied, on error the ones before it stay applied.
+func (r *Runner) Up(ctx context.Context, target int) ([]int, error) {
+	err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.unlock()
+
+	err = r.ensureRecordIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for _, migration := range r.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+
+		if _, found := applied[migration.Version]; found {
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Apply migration %v %v", migration.Version, migration.Description))
+
+		err = r.apply(ctx, migration)
+		if err != nil {
+			return versions, fmt.Errorf("migration %v failed because %v", migration.Version, err)
+		}
+
+		versions = append(versions, migration.Version)
+	}
+
+	return versions, nil
+}
+
+func (r *Runner) apply(ctx context.Context, migration Migration) error {
+	for _, name := range migration.Collections {
+		err := r.db.CreateCollection(ctx, name)
+		if commandErr, ok := err.(mongo.CommandError); ok && commandErr.Code == namespaceExistsCode {
+			err = nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, index := range migration.Indexes {
+		_, err := r.db.Collection(index.Collection).Indexes().CreateOne(ctx, index.Model)
+		if err != nil {
+			return err
+		}
+	}
+
+	if migration.Run != nil {
+		err := migration.Run(ctx, r.db)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.Collection(domain.SCHEMA_MIGRATION_COLLECTION).InsertOne(ctx, domain.SchemaMigration{
+		Version:     migration.Version,
+		Description: migration.Description,
+		AppliedTime: domain.TimestampNow(),
+	})
+
+	return err
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int]domain.SchemaMigration, error) {
+	cursor, err := r.db.Collection(domain.SCHEMA_MIGRATION_COLLECTION).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []domain.SchemaMigration
+	err = cursor.All(ctx, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[int]domain.SchemaMigration{}
+	for _, record := range records {
+		results[record.Version] = record
+	}
+
+	return results, nil
+}
+
+// Version is recorded once even when two runners pass the lock
+func (r *Runner) ensureRecordIndex(ctx context.Context) error {
+	_, err := r.db.Collection(domain.SCHEMA_MIGRATION_COLLECTION).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+// Lock document is inserted when missing or expired, an existing one fail the
+// upsert with duplicate key
+func (r *Runner) lock(ctx context.Context) error {
+	now := time.Now()
+	_, err := r.db.Collection(domain.SCHEMA_MIGRATION_LOCK_COLLECTION).UpdateOne(ctx,
+		bson.M{"_id": migrationLockID, "locked_until": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{"locked_until": now.Add(MIGRATION_LOCK_TIMEOUT)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+// Released even when ctx is done so the next run does not wait for the timeout
+func (r *Runner) unlock() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.db.Collection(domain.SCHEMA_MIGRATION_LOCK_COLLECTION).DeleteOne(ctx, bson.M{"_id": migrationLockID})
+	if err != nil {
+		log.Error(fmt.Sprintf("Release migration lock failed because %v", err.Error()))
+	}
+}
@@ -0,0 +1,575 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Every schema version, append a new one instead of changing an applied one
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Device, biometric, OTP and request nonce indexes",
+		Collections: []string{
+			domain.DEVICE_COLLECTION,
+			domain.BIOMETRIC_CHALLENGE_COLLECTION,
+			domain.OTP_COLLECTION,
+			domain.REQUEST_NONCE_COLLECTION,
+		},
+		Indexes: []Index{
+			{
+				Collection: domain.DEVICE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "device_id", Value: 1}, {Key: "status", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.BIOMETRIC_CHALLENGE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.BIOMETRIC_CHALLENGE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "expired_at", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0),
+				},
+			},
+			{
+				Collection: domain.OTP_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+			{
+				Collection: domain.OTP_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "delete_at", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0),
+				},
+			},
+			{
+				Collection: domain.REQUEST_NONCE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "corporate_id", Value: 1}, {Key: "request_id", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+			{
+				Collection: domain.REQUEST_NONCE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "expired_at", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0),
+				},
+			},
+		},
+	},
+	{
+		Version:     2,
+		Description: "Transaction, statement, bulk transfer and balance lookup indexes",
+		Indexes: []Index{
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "gateway_reference", Value: 1}, {Key: "status", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "status", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "device_id", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "from_balance_id", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "to_balance_id", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.STATEMENT_COLLECTION_NAME,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "balance_id", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.BULK_TRANSFER_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "status", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.BALANCE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "owner._id", Value: 1}},
+				},
+			},
+		},
+	},
+	{
+		// Fail when existing documents already break the constraint, clean
+		// them up and run again
+		Version:     3,
+		Description: "Unique user phone number, transaction code, statement sequence and sub balance index",
+		Collections: []string{domain.SUB_BALANCE_COLLECTION},
+		Indexes: []Index{
+			{
+				Collection: domain.USER_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "phone_number", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("unique_phone_number").
+						SetPartialFilterExpression(bson.M{"phone_number": bson.M{"$exists": true}, "pending": false}),
+				},
+			},
+			{
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "transaction_code", Value: 1}},
+					Options: options.Index().SetUnique(true).
+						SetPartialFilterExpression(bson.M{"transaction_code": bson.M{"$exists": true}}),
+				},
+			},
+			{
+				Collection: domain.STATEMENT_COLLECTION_NAME,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "balance_id", Value: 1}, {Key: "sub_balance", Value: 1}, {Key: "sequence", Value: 1}},
+					Options: options.Index().SetUnique(true).
+						SetPartialFilterExpression(bson.M{"sequence": bson.M{"$gt": 0}}),
+				},
+			},
+			{
+				Collection: domain.SUB_BALANCE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "balance_id", Value: 1}, {Key: "index", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+		},
+	},
+	{
+		Version:     4,
+		Description: "Backfill currency of balance, transaction and statement",
+		Run:         MoneyCurrency,
+	},
+	{
+		// Fail when existing documents already break a unique constraint, clean
+		// them up and run again
+		Version:     5,
+		Description: "Session, role, limit, fraud, KYC, notification indexes and unique external ID",
+		Collections: []string{
+			domain.SESSION_COLLECTION,
+			domain.REVOKED_TOKEN_COLLECTION,
+			domain.KYC_CASE_COLLECTION,
+			domain.NOTIFICATION_LOG_COLLECTION,
+		},
+		Indexes: []Index{
+			{
+				Collection: domain.SESSION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "revoked", Value: 1}, {Key: "expired_time", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.REVOKED_TOKEN_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "token_id", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+			{
+				// Token is useless once expired, so is its revocation
+				Collection: domain.REVOKED_TOKEN_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "expired_time", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0),
+				},
+			},
+			{
+				Collection: domain.ROLE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "corporate_id", Value: 1}, {Key: "name", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+			{
+				Collection: domain.LIMIT_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "transaction_type", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.FRAUD_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "decision", Value: 1},
+						{Key: "review_status", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.FRAUD_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "transaction_code", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.KYC_CASE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+				},
+			},
+			{
+				Collection: domain.KYC_CASE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "status", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				Collection: domain.MESSAGE_TEMPLATE_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys:    bson.D{{Key: "corporate_id", Value: 1}, {Key: "key", Value: 1}, {Key: "language", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			},
+			{
+				Collection: domain.NOTIFICATION_LOG_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "time", Value: -1}},
+				},
+			},
+			{
+				// Transaction without external ID store an empty one
+				Collection: domain.TRANSACTION_COLLECTION,
+				Model: mongo.IndexModel{
+					Keys: bson.D{{Key: "corporate_id", Value: 1}, {Key: "external_id", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("unique_external_id").
+						SetPartialFilterExpression(bson.M{"external_id": bson.M{"$gt": ""}}),
+				},
+			},
+		},
+	},
+	{
+		// Written inside the commit transaction, collection must exist first
+		Version:     6,
+		Description: "Limit usage collection",
+		Collections: []string{
+			domain.LIMIT_USAGE_COLLECTION,
+		},
+	},
+	{
+		// Query on time only match dates once deployed, run it right after
+		Version:     7,
+		Description: "Convert TIME_FORMAT timestamp strings to dates",
+		Run:         timestampDates(timestampFields),
+	},
+}
+
+// Batch of documents updated in one bulk write
+const timestampBatchSize = 500
+
+// Field inside every element of an array is written with $[], for example
+// "approvals.$[].time"
+type timestampField struct {
+	collection string
+	field      string
+}
+
+// Fields which held a TIME_FORMAT string before domain.Timestamp
+var timestampFields = []timestampField{
+	{domain.TRANSACTION_COLLECTION, "time"},
+	{domain.STATEMENT_COLLECTION_NAME, "time"},
+	{domain.REQUEST_COLLECTION, "time"},
+	{domain.CALLBACK_HISTORY_COLLECTION, "time"},
+	{domain.BULK_TRANSFER_COLLECTION, "time"},
+	{domain.USER_COLLECTION, "audit.created_time"},
+	{domain.USER_COLLECTION, "audit.updated_time"},
+	{domain.LIMIT_COLLECTION, "audit.created_time"},
+	{domain.LIMIT_COLLECTION, "audit.updated_time"},
+	{domain.ROLE_COLLECTION, "audit.created_time"},
+	{domain.ROLE_COLLECTION, "audit.updated_time"},
+	{domain.SESSION_COLLECTION, "token_expired_time"},
+	{domain.SESSION_COLLECTION, "created_time"},
+	{domain.SESSION_COLLECTION, "last_refresh_time"},
+	{domain.SESSION_COLLECTION, "expired_time"},
+	{domain.SESSION_COLLECTION, "revoked_time"},
+	{domain.REVOKED_TOKEN_COLLECTION, "expired_time"},
+	{domain.REVOKED_TOKEN_COLLECTION, "time"},
+	{domain.BULK_INQUIRY_COLLECTION, "time"},
+	{domain.BULK_TRANSFER_COLLECTION, "approvals.$[].time"},
+	{domain.CORPORATE_COLLECTION, "created_time"},
+	{domain.CORPORATE_COLLECTION, "updated_time"},
+	{domain.CORPORATE_COLLECTION, "lockout.locked_until"},
+	{domain.USER_COLLECTION, "pin_updated_time"},
+	{domain.USER_COLLECTION, "device_moved_time"},
+	{domain.USER_COLLECTION, "lockout.locked_until"},
+	{domain.DEVICE_COLLECTION, "trusted_time"},
+	{domain.DEVICE_COLLECTION, "last_used_time"},
+	{domain.DEVICE_COLLECTION, "revoked_time"},
+	{domain.FRAUD_COLLECTION, "time"},
+	{domain.FRAUD_COLLECTION, "review_time"},
+	{domain.FRAUD_COLLECTION, "transaction.time"},
+	{domain.IP_ALLOWLIST_HISTORY_COLLECTION, "time"},
+	{domain.BULK_APPROVAL_HISTORY_COLLECTION, "time"},
+	{domain.KYC_CASE_COLLECTION, "time"},
+	{domain.KYC_CASE_COLLECTION, "updated_time"},
+	{domain.KYC_CASE_COLLECTION, "documents.$[].time"},
+	{domain.KYC_CASE_COLLECTION, "actions.$[].time"},
+	{domain.LOCKOUT_AUDIT_COLLECTION, "locked_until"},
+	{domain.LOCKOUT_AUDIT_COLLECTION, "time"},
+	{domain.MESSAGE_TEMPLATE_COLLECTION, "time"},
+	{domain.NOTIFICATION_LOG_COLLECTION, "time"},
+	{domain.RAB_COLLECTION_NAME, "time"},
+	{domain.REQUEST_NONCE_COLLECTION, "time"},
+	{domain.SCHEMA_MIGRATION_COLLECTION, "applied_time"},
+	{domain.TRANSACTION_COLLECTION, "review_histories.$[].time"},
+	{domain.TRANSACTION_COLLECTION, "gateway_histories.$[].time"},
+	{domain.TRANSACTION_COLLECTION, "reserved_statements.$[].time"},
+	{domain.TRANSACTION_COLLECTION, "held_statements.$[].time"},
+}
+
+// Backfill currency on documents created before money type introduced.
+// Corporate without currency run on idr, balance and transaction follow the
+// corporate currency, statement follow
+// its balance. Only empty currency is touched so it is safe to run again.
+func MoneyCurrency(ctx context.Context, db *mongo.Database) error {
+	cursor, err := db.Collection(domain.CORPORATE_COLLECTION).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	var corporates []domain.Corporate
+	err = cursor.All(ctx, &corporates)
+	if err != nil {
+		return err
+	}
+
+	for _, corporate := range corporates {
+		currency := corporate.GetCurrency()
+		if corporate.Currency == "" {
+			_, err = db.Collection(domain.CORPORATE_COLLECTION).UpdateOne(ctx, bson.M{"_id": corporate.ID},
+				setCurrency(currency))
+			if err != nil {
+				return err
+			}
+		}
+
+		filter := bson.M{"corporate_id": corporate.ID, "currency": emptyCurrency()}
+
+		_, err = db.Collection(domain.BALANCE_COLLECTION).UpdateMany(ctx, filter, setCurrency(currency))
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection(domain.TRANSACTION_COLLECTION).UpdateMany(ctx, filter, setCurrency(currency))
+		if err != nil {
+			return err
+		}
+	}
+
+	cursor, err = db.Collection(domain.BALANCE_COLLECTION).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	var balances []domain.Balance
+	err = cursor.All(ctx, &balances)
+	if err != nil {
+		return err
+	}
+
+	for _, balance := range balances {
+		if balance.Currency == "" {
+			continue
+		}
+
+		filter := bson.M{"balance_id": balance.ID, "currency": emptyCurrency()}
+		result, err := db.Collection(domain.STATEMENT_COLLECTION_NAME).UpdateMany(ctx, filter,
+			setCurrency(domain.NormalizeCurrency(balance.Currency)))
+		if err != nil {
+			return err
+		}
+
+		if result.ModifiedCount > 0 {
+			log.Info("Migrate statement currency balance ", balance.ID.Hex(), " : ", result.ModifiedCount)
+		}
+	}
+
+	return nil
+}
+
+func emptyCurrency() bson.M {
+	return bson.M{"$in": bson.A{nil, ""}}
+}
+
+func setCurrency(currency string) bson.D {
+	return bson.D{{Key: "$set", Value: bson.D{{Key: "currency", Value: currency}}}}
+}
+
+// Convert timestamp strings of the fields to dates, read with TIME_FORMAT in
+// TIME_ZONE as they were written. Only string values are touched so it is safe
+// to run again, a value which cannot be parsed is left and fail the migration.
+func timestampDates(fields []timestampField) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, timestamp := range fields {
+			err := convertTimestamps(ctx, db.Collection(timestamp.collection), timestamp.field)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func convertTimestamps(ctx context.Context, collection *mongo.Collection, field string) error {
+	array, element, isArray := strings.Cut(field, ".$[].")
+	path := field
+	if isArray {
+		path = array + "." + element
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{path: bson.M{"$type": "string"}},
+		options.Find().SetProjection(bson.M{strings.Split(path, ".")[0]: 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	var converted int
+	var invalid []string
+	for cursor.Next(ctx) {
+		var filter, update bson.M
+		var value string
+		if isArray {
+			filter, update, value, err = convertTimestampArray(cursor.Current, array, element)
+		} else {
+			filter, update, value, err = convertTimestamp(cursor.Current, field)
+		}
+
+		if err != nil {
+			invalid = append(invalid, value)
+			continue
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+
+		if len(models) == timestampBatchSize {
+			result, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+			if err != nil {
+				return err
+			}
+
+			converted += int(result.ModifiedCount)
+			models = nil
+		}
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return err
+	}
+
+	if len(models) > 0 {
+		result, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+
+		converted += int(result.ModifiedCount)
+	}
+
+	if converted > 0 {
+		log.Info("Migrate timestamp ", collection.Name(), ".", field, " : ", converted)
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("%v %v.%v cannot be parsed, first is %v", len(invalid), collection.Name(), field, invalid[0])
+	}
+
+	return nil
+}
+
+// Update of the string field, skipped when the document changed since it was read
+func convertTimestamp(document bson.Raw, field string) (bson.M, bson.M, string, error) {
+	value, _ := document.Lookup(strings.Split(field, ".")...).StringValueOK()
+	parsed, err := domain.ParseTimestamp(value)
+	if err != nil {
+		return nil, nil, value, err
+	}
+
+	filter := bson.M{"_id": document.Lookup("_id"), field: value}
+	if parsed.IsZero() {
+		return filter, bson.M{"$unset": bson.M{field: ""}}, value, nil
+	}
+
+	return filter, bson.M{"$set": bson.M{field: parsed}}, value, nil
+}
+
+// Update replacing the whole array with its string fields converted, skipped
+// when the array changed since it was read
+func convertTimestampArray(document bson.Raw, array string, field string) (bson.M, bson.M, string, error) {
+	current := document.Lookup(strings.Split(array, ".")...)
+	values, ok := current.ArrayOK()
+	if !ok {
+		return nil, nil, "", fmt.Errorf("%v is not an array", array)
+	}
+
+	elements, err := values.Values()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var results bson.A
+	for _, element := range elements {
+		var fields bson.D
+		if element.Type != bsontype.EmbeddedDocument || element.Unmarshal(&fields) != nil {
+			results = append(results, element)
+			continue
+		}
+
+		for i := 0; i < len(fields); i++ {
+			value, ok := fields[i].Value.(string)
+			if fields[i].Key != field || !ok {
+				continue
+			}
+
+			parsed, err := domain.ParseTimestamp(value)
+			if err != nil {
+				return nil, nil, value, err
+			}
+
+			if parsed.IsZero() {
+				fields = append(fields[:i], fields[i+1:]...)
+				break
+			}
+
+			fields[i].Value = parsed
+		}
+
+		results = append(results, fields)
+	}
+
+	filter := bson.M{"_id": document.Lookup("_id"), array: current}
+	return filter, bson.M{"$set": bson.M{array: results}}, "", nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Fields of balance written only by Adjust
+var balanceCounterKeys = []string{"amount", "version", "statement_sequence"}
+
+// Error adjusting the balance would get, nil when it can be adjusted
+func checkAdjust(balance domain.Balance, amount int, subAmount int, currency string) error {
+	if !domain.IsSameCurrency(balance.Currency, currency) {
+		return domain.ErrCurrencyMismatch
+	}
+
+	if amount < 0 && balance.Amount+subAmount+amount < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+func withoutKeys(document bson.D, keys ...string) bson.D {
+	var result bson.D
+	for _, element := range document {
+		excluded := false
+		for _, key := range keys {
+			if element.Key == key {
+				excluded = true
+				break
+			}
+		}
+
+		if !excluded {
+			result = append(result, element)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,530 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Memory transaction rerun fn at most this many time on write conflict
+const MEMORY_TRANSACTION_MAX_RETRY = 10
+
+// Document written in a transaction was changed by another writer before the
+// transaction commit, the transaction is rerun by the transactor
+var ErrWriteConflict = errors.New("write conflict")
+
+// Repositories kept in memory, meant for test. Document is stored as bson so
+// the mongo tags decide what is kept, same as the mongo implementation.
+// Transaction read from a snapshot taken when it start, its writes are only
+// visible to others after commit and conflict with any write committed in
+// between.
+func NewMemory() *Repositories {
+	store := &memoryStore{collections: map[string]map[primitive.ObjectID]memoryDocument{}}
+
+	return &Repositories{
+		Balance:            memoryBalance{store},
+		SubBalance:         memorySubBalance{store},
+		Transaction:        memoryTransaction{store},
+		Statement:          memoryStatement{store},
+		User:               memoryUser{store},
+		Corporate:          memoryCorporate{store},
+		Bulk:               memoryBulk{store},
+		Callback:           memoryCallback{store},
+		Device:             memoryDevice{store},
+		BiometricChallenge: memoryBiometricChallenge{store},
+		Fraud:              memoryFraud{store},
+		RequestAccess:      memoryRequestAccessBalance{store},
+		KYC:                memoryKYC{store},
+		Limit:              memoryLimit{store},
+		LockoutAudit:       memoryLockoutAudit{store},
+		Notification:       memoryNotification{store},
+		OTP:                memoryOTP{store},
+		Role:               memoryRole{store},
+		Session:            memorySessionRepository{store},
+		IPAllowlist:        memoryIPAllowlist{store},
+		RequestNonce:       memoryRequestNonce{store},
+		Transactor:         memoryTransactor{store},
+	}
+}
+
+type memoryDocument struct {
+	data    bson.Raw
+	seq     int64
+	version int64
+	deleted bool
+}
+
+type memoryStore struct {
+	mu          sync.RWMutex
+	seq         int64
+	collections map[string]map[primitive.ObjectID]memoryDocument
+}
+
+type memorySession struct {
+	mu       sync.Mutex
+	snapshot map[string]map[primitive.ObjectID]memoryDocument
+	writes   map[string]map[primitive.ObjectID]memoryDocument
+	done     bool
+}
+
+type memorySessionKey struct{}
+
+type memoryTransactor struct {
+	store *memoryStore
+}
+
+func (t memoryTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if memorySessionFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	var err error
+	for i := 0; i < MEMORY_TRANSACTION_MAX_RETRY; i++ {
+		session := t.store.begin()
+		err = fn(context.WithValue(ctx, memorySessionKey{}, session))
+		if err == nil {
+			err = t.store.commit(session)
+		} else {
+			session.close()
+		}
+
+		if err != ErrWriteConflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (t memoryTransactor) WithoutTransaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memorySessionKey{}, (*memorySession)(nil))
+}
+
+func memorySessionFrom(ctx context.Context) *memorySession {
+	session, _ := ctx.Value(memorySessionKey{}).(*memorySession)
+	return session
+}
+
+func (s *memoryStore) begin() *memorySession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := map[string]map[primitive.ObjectID]memoryDocument{}
+	for name, collection := range s.collections {
+		documents := make(map[primitive.ObjectID]memoryDocument, len(collection))
+		for ID, document := range collection {
+			documents[ID] = document
+		}
+		snapshot[name] = documents
+	}
+
+	return &memorySession{snapshot: snapshot, writes: map[string]map[primitive.ObjectID]memoryDocument{}}
+}
+
+// Apply the writes only when none of the documents changed since the snapshot
+func (s *memoryStore) commit(session *memorySession) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.done = true
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, writes := range session.writes {
+		for ID := range writes {
+			if s.collections[name][ID].version != session.snapshot[name][ID].version {
+				return ErrWriteConflict
+			}
+		}
+	}
+
+	for name, writes := range session.writes {
+		for ID, document := range writes {
+			s.put(name, ID, document)
+		}
+	}
+
+	return nil
+}
+
+func (session *memorySession) close() {
+	session.mu.Lock()
+	session.done = true
+	session.mu.Unlock()
+}
+
+// Run fn on the view of the context, inside a transaction the view is the
+// snapshot with the transaction writes, otherwise the committed documents
+func (s *memoryStore) view(ctx context.Context, write bool, fn func(view memoryView) error) error {
+	if session := memorySessionFrom(ctx); session != nil {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+
+		if session.done {
+			return errors.New("transaction already ended")
+		}
+
+		return fn(sessionView{s, session})
+	}
+
+	if write {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+
+	return fn(storeView{s})
+}
+
+func (s *memoryStore) put(name string, ID primitive.ObjectID, document memoryDocument) {
+	if s.collections[name] == nil {
+		s.collections[name] = map[primitive.ObjectID]memoryDocument{}
+	}
+
+	current, found := s.collections[name][ID]
+	document.version = current.version + 1
+	if found && document.seq == 0 {
+		document.seq = current.seq
+	}
+
+	if document.deleted {
+		// Keep the version so a transaction that read the document still conflict
+		document.data = nil
+	}
+
+	s.collections[name][ID] = document
+}
+
+type memoryView interface {
+	get(name string, ID primitive.ObjectID) (memoryDocument, bool)
+	put(name string, ID primitive.ObjectID, document memoryDocument)
+	list(name string) []memoryDocument
+	nextSeq() int64
+}
+
+type storeView struct {
+	store *memoryStore
+}
+
+func (v storeView) get(name string, ID primitive.ObjectID) (memoryDocument, bool) {
+	document, found := v.store.collections[name][ID]
+	return document, found && !document.deleted
+}
+
+func (v storeView) put(name string, ID primitive.ObjectID, document memoryDocument) {
+	v.store.put(name, ID, document)
+}
+
+func (v storeView) list(name string) []memoryDocument {
+	return sortDocuments(v.store.collections[name], nil)
+}
+
+func (v storeView) nextSeq() int64 {
+	v.store.seq += 1
+	return v.store.seq
+}
+
+type sessionView struct {
+	store   *memoryStore
+	session *memorySession
+}
+
+func (v sessionView) get(name string, ID primitive.ObjectID) (memoryDocument, bool) {
+	document, found := v.session.writes[name][ID]
+	if !found {
+		document, found = v.session.snapshot[name][ID]
+	}
+
+	return document, found && !document.deleted
+}
+
+func (v sessionView) put(name string, ID primitive.ObjectID, document memoryDocument) {
+	if v.session.writes[name] == nil {
+		v.session.writes[name] = map[primitive.ObjectID]memoryDocument{}
+	}
+
+	v.session.writes[name][ID] = document
+}
+
+func (v sessionView) list(name string) []memoryDocument {
+	return sortDocuments(v.session.snapshot[name], v.session.writes[name])
+}
+
+// Sequence is only for ordering, taking it from the store outside commit is fine
+func (v sessionView) nextSeq() int64 {
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+
+	v.store.seq += 1
+	return v.store.seq
+}
+
+// Documents in insertion order, writes replace the base document
+func sortDocuments(base map[primitive.ObjectID]memoryDocument,
+	writes map[primitive.ObjectID]memoryDocument) []memoryDocument {
+
+	var results []memoryDocument
+	for ID, document := range base {
+		if written, found := writes[ID]; found {
+			document = written
+		}
+
+		if !document.deleted {
+			results = append(results, document)
+		}
+	}
+
+	for ID, document := range writes {
+		if _, found := base[ID]; !found && !document.deleted {
+			results = append(results, document)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].seq < results[j].seq
+	})
+
+	return results
+}
+
+// Insert with a new ID when the model has none, like the mongo driver
+func (s *memoryStore) insert(ctx context.Context, name string, model domain.BaseModel) error {
+	return s.insertUnique(ctx, name, model, nil)
+}
+
+// Insert unless duplicate return true for a document already in the
+// collection, in place of a mongo unique index
+func (s *memoryStore) insertUnique(ctx context.Context, name string, model domain.BaseModel,
+	duplicate func(data bson.Raw) (bool, error)) error {
+
+	document, err := toDocument(model)
+	if err != nil {
+		return err
+	}
+
+	ID, _ := documentValue(document, "_id").(primitive.ObjectID)
+	if ID.IsZero() {
+		ID = primitive.NewObjectID()
+		document = setDocumentValue(document, []string{"_id"}, ID)
+	}
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	err = s.view(ctx, true, func(view memoryView) error {
+		if _, found := view.get(name, ID); found {
+			return ErrDuplicateKey
+		}
+
+		for _, document := range view.list(name) {
+			if duplicate == nil {
+				break
+			}
+
+			found, err := duplicate(document.data)
+			if err != nil || found {
+				return firstError(err, ErrDuplicateKey)
+			}
+		}
+
+		view.put(name, ID, memoryDocument{data: data, seq: view.nextSeq()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	model.SetDocumentID(ID)
+
+	return nil
+}
+
+// Same as mongo $set, key with dot set the field of the embedded document.
+// Nothing happen when the document does not exist.
+func (s *memoryStore) set(ctx context.Context, name string, ID primitive.ObjectID, changes bson.D) error {
+	return s.view(ctx, true, func(view memoryView) error {
+		return setView(view, name, ID, changes)
+	})
+}
+
+func setView(view memoryView, name string, ID primitive.ObjectID, changes bson.D) error {
+	current, found := view.get(name, ID)
+	if !found {
+		return nil
+	}
+
+	var document bson.D
+	err := bson.Unmarshal(current.data, &document)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		document = setDocumentValue(document, strings.Split(change.Key, "."), change.Value)
+	}
+
+	current.data, err = bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	view.put(name, ID, current)
+	return nil
+}
+
+// Set changes only when match accept the current document, same as a mongo
+// update with the condition in its filter. Return whether it was set.
+func (s *memoryStore) setIf(ctx context.Context, name string, ID primitive.ObjectID,
+	match func(data bson.Raw) (bool, error), changes bson.D) (bool, error) {
+
+	set := false
+	err := s.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(name, ID)
+		if !found {
+			return nil
+		}
+
+		matched, err := match(current.data)
+		if err != nil || !matched {
+			return err
+		}
+
+		set = true
+		return setView(view, name, ID, changes)
+	})
+
+	return set, err
+}
+
+// Set every field of the model, same as the mongo update
+func (s *memoryStore) update(ctx context.Context, name string, model domain.BaseModel) error {
+	document, err := toDocument(model)
+	if err != nil {
+		return err
+	}
+
+	return s.set(ctx, name, model.GetDocumentID(), document)
+}
+
+func (s *memoryStore) remove(ctx context.Context, name string, ID primitive.ObjectID,
+	match func(data bson.Raw) (bool, error)) error {
+
+	return s.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(name, ID)
+		if !found {
+			return nil
+		}
+
+		matched, err := match(current.data)
+		if err != nil || !matched {
+			return err
+		}
+
+		current.deleted = true
+		view.put(name, ID, current)
+		return nil
+	})
+}
+
+func (s *memoryStore) find(ctx context.Context, name string, ID primitive.ObjectID, result interface{}) error {
+	return s.view(ctx, false, func(view memoryView) error {
+		document, found := view.get(name, ID)
+		if !found {
+			return ErrNotFound
+		}
+
+		return bson.Unmarshal(document.data, result)
+	})
+}
+
+// Call fn with every document in insertion order until it return false
+func (s *memoryStore) each(ctx context.Context, name string, fn func(data bson.Raw) (bool, error)) error {
+	return s.view(ctx, false, func(view memoryView) error {
+		for _, document := range view.list(name) {
+			next, err := fn(document.data)
+			if err != nil || !next {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func documentValue(document bson.D, key string) interface{} {
+	for _, element := range document {
+		if element.Key == key {
+			return element.Value
+		}
+	}
+
+	return nil
+}
+
+func setDocumentValue(document bson.D, path []string, value interface{}) bson.D {
+	for i, element := range document {
+		if element.Key != path[0] {
+			continue
+		}
+
+		if len(path) == 1 {
+			document[i].Value = value
+		} else {
+			embedded, _ := element.Value.(bson.D)
+			document[i].Value = setDocumentValue(embedded, path[1:], value)
+		}
+
+		return document
+	}
+
+	if len(path) == 1 {
+		return append(document, bson.E{Key: path[0], Value: value})
+	}
+
+	return append(document, bson.E{Key: path[0], Value: setDocumentValue(bson.D{}, path[1:], value)})
+}
+
+// Reverse a slice in insertion order, newest first same as mongoFindOrderByID
+func newestFirst(results interface{}) {
+	swap := reflect.Swapper(results)
+	for i, j := 0, reflect.ValueOf(results).Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// Page start from 1 and empty page or limit return all, same as mongoFind
+func memoryPage(total int, page string, limit string) (int, int) {
+	if page == "" || limit == "" {
+		return 0, total
+	}
+
+	p, _ := strconv.Atoi(page)
+	l, _ := strconv.Atoi(limit)
+
+	start := (p - 1) * l
+	if start < 0 {
+		start = 0
+	}
+
+	if start > total {
+		start = total
+	}
+
+	if l <= 0 || start+l > total {
+		return start, total
+	}
+
+	return start, start + l
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryBalance struct {
+	store *memoryStore
+}
+
+func (r memoryBalance) Save(ctx context.Context, model *domain.Balance) error {
+	return r.store.insert(ctx, domain.BALANCE_COLLECTION, model)
+}
+
+func (r memoryBalance) Update(ctx context.Context, model *domain.Balance) error {
+	document, err := toDocument(model)
+	if err != nil {
+		return err
+	}
+
+	return r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.BALANCE_COLLECTION, model.ID)
+		if !found {
+			return nil
+		}
+
+		balance := domain.Balance{}
+		err := bson.Unmarshal(current.data, &balance)
+		if err != nil {
+			return err
+		}
+
+		changes := append(withoutKeys(document, balanceCounterKeys...), bson.E{Key: "version", Value: balance.Version + 1})
+		return setView(view, domain.BALANCE_COLLECTION, model.ID, changes)
+	})
+}
+
+func (r memoryBalance) Adjust(ctx context.Context, ID primitive.ObjectID, amount int, subAmount int,
+	currency string) (domain.Balance, error) {
+
+	model := domain.Balance{}
+	err := r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.BALANCE_COLLECTION, ID)
+		if !found {
+			return ErrNotFound
+		}
+
+		err := bson.Unmarshal(current.data, &model)
+		if err != nil {
+			return err
+		}
+
+		err = checkAdjust(model, amount, subAmount, currency)
+		if err != nil {
+			return err
+		}
+
+		model.Amount += amount
+		model.Version += 1
+		model.StatementSequence += 1
+
+		return setView(view, domain.BALANCE_COLLECTION, ID, bson.D{
+			{Key: "amount", Value: model.Amount},
+			{Key: "version", Value: model.Version},
+			{Key: "statement_sequence", Value: model.StatementSequence},
+		})
+	})
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	return model, nil
+}
+
+func (r memoryBalance) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Balance, error) {
+	model := domain.Balance{}
+	err := r.store.find(ctx, domain.BALANCE_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+// Newest first, same as the mongo order by ID
+func (r memoryBalance) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Balance, error) {
+	var results []domain.Balance
+	err := r.store.each(ctx, domain.BALANCE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Balance{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.Owner.ID == ownerID {
+			results = append([]domain.Balance{model}, results...)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+)
+
+func TestMemoryBalanceConcurrentAdjust(t *testing.T) {
+	repositories := NewMemory()
+	ctx := context.Background()
+
+	balance := domain.Balance{Name: "Main", Currency: domain.CURRENCY_IDR}
+	err := repositories.Balance.Save(ctx, &balance)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	_, err = repositories.Balance.Adjust(ctx, balance.ID, 100, 0, domain.CURRENCY_IDR)
+	if err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, insufficient := 0, 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := repositories.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+				_, err := repositories.Balance.Adjust(ctx, balance.ID, -10, 0, domain.CURRENCY_IDR)
+				return err
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch err {
+			case nil:
+				succeeded += 1
+			case ErrInsufficientBalance:
+				insufficient += 1
+			default:
+				t.Errorf("adjust: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if succeeded != 10 || insufficient != 40 {
+		t.Errorf("succeeded %v insufficient %v, want 10 and 40", succeeded, insufficient)
+	}
+
+	model, err := repositories.Balance.FindByID(ctx, balance.ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if model.Amount != 0 || model.StatementSequence != 11 {
+		t.Errorf("amount %v sequence %v, want 0 and 11", model.Amount, model.StatementSequence)
+	}
+}
+
+func TestMemoryTransactionRollback(t *testing.T) {
+	repositories := NewMemory()
+	ctx := context.Background()
+
+	balance := domain.Balance{Name: "Main", Currency: domain.CURRENCY_IDR}
+	err := repositories.Balance.Save(ctx, &balance)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	err = repositories.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := repositories.Balance.Adjust(ctx, balance.ID, 500, 0, domain.CURRENCY_IDR)
+		if err != nil {
+			return err
+		}
+
+		_, err = repositories.Balance.Adjust(ctx, balance.ID, -1000, 0, domain.CURRENCY_IDR)
+		return err
+	})
+	if err != ErrInsufficientBalance {
+		t.Fatalf("transaction error = %v, want insufficient balance", err)
+	}
+
+	model, err := repositories.Balance.FindByID(ctx, balance.ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if model.Amount != 0 || model.StatementSequence != 0 {
+		t.Errorf("amount %v sequence %v, want 0 and 0", model.Amount, model.StatementSequence)
+	}
+}
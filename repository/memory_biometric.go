@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type memoryBiometricChallenge struct {
+	store *memoryStore
+}
+
+func (r memoryBiometricChallenge) Save(ctx context.Context, model *domain.BiometricChallenge) error {
+	return r.store.insert(ctx, domain.BIOMETRIC_CHALLENGE_COLLECTION, model)
+}
+
+func (r memoryBiometricChallenge) Consume(ctx context.Context, model domain.BiometricChallenge,
+	now time.Time) (bool, error) {
+
+	return r.store.setIf(ctx, domain.BIOMETRIC_CHALLENGE_COLLECTION, model.ID, func(data bson.Raw) (bool, error) {
+		current := domain.BiometricChallenge{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.UserID == model.UserID && current.Purpose == model.Purpose &&
+			current.DeviceID == model.DeviceID && current.NonceHash == model.NonceHash && !current.Used &&
+			current.ExpiredAt.After(now), err
+	}, bson.D{{Key: "used", Value: true}})
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryBulk struct {
+	store *memoryStore
+}
+
+func (r memoryBulk) SaveInquiry(ctx context.Context, model *domain.BulkInquiry) error {
+	return r.store.insert(ctx, domain.BULK_INQUIRY_COLLECTION, model)
+}
+
+func (r memoryBulk) UpdateInquiry(ctx context.Context, model *domain.BulkInquiry) error {
+	return r.store.update(ctx, domain.BULK_INQUIRY_COLLECTION, model)
+}
+
+func (r memoryBulk) FindInquiryByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkInquiry, error) {
+	model := domain.BulkInquiry{}
+	err := r.store.find(ctx, domain.BULK_INQUIRY_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryBulk) SaveTransfer(ctx context.Context, model *domain.BulkTransfer) error {
+	return r.store.insert(ctx, domain.BULK_TRANSFER_COLLECTION, model)
+}
+
+func (r memoryBulk) UpdateTransfer(ctx context.Context, model *domain.BulkTransfer) error {
+	return r.store.update(ctx, domain.BULK_TRANSFER_COLLECTION, model)
+}
+
+func (r memoryBulk) FindTransferByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkTransfer, error) {
+	model := domain.BulkTransfer{}
+	err := r.store.find(ctx, domain.BULK_TRANSFER_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryBulk) FindTransfersByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+	page string, limit string) ([]domain.BulkTransfer, error) {
+
+	var results []domain.BulkTransfer
+	err := r.store.each(ctx, domain.BULK_TRANSFER_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.BulkTransfer{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID && model.Status == status {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
+
+func (r memoryBulk) AddApproval(ctx context.Context, ID primitive.ObjectID, approval domain.BulkApproval) (bool, error) {
+	added := false
+	err := r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.BULK_TRANSFER_COLLECTION, ID)
+		if !found {
+			return nil
+		}
+
+		model := domain.BulkTransfer{}
+		err := bson.Unmarshal(current.data, &model)
+		if err != nil {
+			return err
+		}
+
+		if model.Status != domain.BULK_PENDING_APPROVAL_STATUS || model.HasReviewed(approval.Approver.ID) {
+			return nil
+		}
+
+		added = true
+		return setView(view, domain.BULK_TRANSFER_COLLECTION, ID, bson.D{
+			{Key: "approvals", Value: append(model.Approvals, approval)},
+		})
+	})
+
+	return added, err
+}
+
+func (r memoryBulk) SetTransferStatus(ctx context.Context, ID primitive.ObjectID, from string, to string) (bool, error) {
+	return r.store.setIf(ctx, domain.BULK_TRANSFER_COLLECTION, ID, func(data bson.Raw) (bool, error) {
+		model := domain.BulkTransfer{}
+		err := bson.Unmarshal(data, &model)
+
+		return err == nil && model.Status == from, err
+	}, bson.D{{Key: "status", Value: to}})
+}
+
+func (r memoryBulk) SaveApprovalHistory(ctx context.Context, model *domain.BulkApprovalHistory) error {
+	return r.store.insert(ctx, domain.BULK_APPROVAL_HISTORY_COLLECTION, model)
+}
+
+func (r memoryBulk) FindApprovalHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.BulkApprovalHistory, error) {
+
+	var results []domain.BulkApprovalHistory
+	err := r.store.each(ctx, domain.BULK_APPROVAL_HISTORY_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.BulkApprovalHistory{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
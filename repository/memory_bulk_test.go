@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryBulkAddApproval(t *testing.T) {
+	repositories := NewMemory()
+	ctx := context.Background()
+
+	bulk := domain.BulkTransfer{Status: domain.BULK_PENDING_APPROVAL_STATUS, RequiredApprovals: 2}
+	err := repositories.Bulk.SaveTransfer(ctx, &bulk)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	approval := domain.BulkApproval{
+		Approver: domain.ActorObject{ID: primitive.NewObjectID(), Type: domain.ACTOR_TYPE_USER},
+		Action:   domain.BULK_APPROVAL_APPROVED,
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	added := 0
+
+	// Same approver sending twice at once count once
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ok, err := repositories.Bulk.AddApproval(ctx, bulk.ID, approval)
+			if err != nil {
+				t.Errorf("add approval: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ok {
+				added += 1
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	model, err := repositories.Bulk.FindTransferByID(ctx, bulk.ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if added != 1 || model.ApprovedCount() != 1 {
+		t.Errorf("added %v approved %v, want 1 and 1", added, model.ApprovedCount())
+	}
+
+	set, err := repositories.Bulk.SetTransferStatus(ctx, bulk.ID, domain.BULK_PENDING_APPROVAL_STATUS,
+		domain.BULK_REJECTED_STATUS)
+	if err != nil || !set {
+		t.Fatalf("reject = %v, %v", set, err)
+	}
+
+	set, err = repositories.Bulk.SetTransferStatus(ctx, bulk.ID, domain.BULK_PENDING_APPROVAL_STATUS,
+		domain.BULK_UNEXECUTED_STATUS)
+	if err != nil || set {
+		t.Errorf("approve after reject = %v, %v, want not set", set, err)
+	}
+
+	approval.Approver.ID = primitive.NewObjectID()
+	ok, err := repositories.Bulk.AddApproval(ctx, bulk.ID, approval)
+	if err != nil || ok {
+		t.Errorf("add approval after reject = %v, %v, want not added", ok, err)
+	}
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+)
+
+type memoryCallback struct {
+	store *memoryStore
+}
+
+func (r memoryCallback) Save(ctx context.Context, model *domain.CallbackHistory) error {
+	return r.store.insert(ctx, domain.CALLBACK_HISTORY_COLLECTION, model)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryCorporate struct {
+	store *memoryStore
+}
+
+func (r memoryCorporate) Save(ctx context.Context, model *domain.Corporate) error {
+	return r.store.insert(ctx, domain.CORPORATE_COLLECTION, model)
+}
+
+func (r memoryCorporate) Update(ctx context.Context, model *domain.Corporate) error {
+	return r.store.update(ctx, domain.CORPORATE_COLLECTION, model)
+}
+
+func (r memoryCorporate) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Corporate, error) {
+	model := domain.Corporate{}
+	err := r.store.find(ctx, domain.CORPORATE_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryCorporate) UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error {
+	return r.store.set(ctx, domain.CORPORATE_COLLECTION, ID, bson.D{{Key: "pin", Value: hash}})
+}
+
+func (r memoryCorporate) UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error {
+	return r.store.set(ctx, domain.CORPORATE_COLLECTION, ID, bson.D{{Key: "list_balance", Value: balances}})
+}
+
+func (r memoryCorporate) UpdateBulkApprovals(ctx context.Context, ID primitive.ObjectID,
+	thresholds []domain.BulkApprovalThreshold) error {
+
+	return r.store.set(ctx, domain.CORPORATE_COLLECTION, ID, bson.D{{Key: "bulk_approvals", Value: thresholds}})
+}
+
+func (r memoryCorporate) UpdateMessaging(ctx context.Context, ID primitive.ObjectID, setting domain.MessagingSetting) error {
+	return r.store.set(ctx, domain.CORPORATE_COLLECTION, ID, bson.D{{Key: "messaging", Value: setting}})
+}
+
+func (r memoryCorporate) UpdateIdentity(ctx context.Context, ID primitive.ObjectID, setting domain.IdentitySetting) error {
+	return r.store.set(ctx, domain.CORPORATE_COLLECTION, ID, bson.D{{Key: "identity", Value: setting}})
+}
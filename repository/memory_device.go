@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryDevice struct {
+	store *memoryStore
+}
+
+func (r memoryDevice) Save(ctx context.Context, model *domain.Device) error {
+	return r.store.insert(ctx, domain.DEVICE_COLLECTION, model)
+}
+
+func (r memoryDevice) Update(ctx context.Context, model *domain.Device) error {
+	return r.store.update(ctx, domain.DEVICE_COLLECTION, model)
+}
+
+func (r memoryDevice) FindTrusted(ctx context.Context, userID primitive.ObjectID, deviceID string) (domain.Device, error) {
+	results, err := r.filter(ctx, func(model domain.Device) bool {
+		return model.UserID == userID && model.DeviceID == deviceID && model.Status == domain.DEVICE_STATUS_TRUSTED
+	})
+	if err != nil || len(results) == 0 {
+		return domain.Device{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+// Newest first, same as the mongo order by ID
+func (r memoryDevice) FindTrustedByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.Device, error) {
+	results, err := r.filter(ctx, func(model domain.Device) bool {
+		return model.UserID == userID && model.Status == domain.DEVICE_STATUS_TRUSTED
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst(results)
+
+	return results, nil
+}
+
+func (r memoryDevice) CountTrusted(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	results, err := r.filter(ctx, func(model domain.Device) bool {
+		return model.UserID == userID && model.Status == domain.DEVICE_STATUS_TRUSTED
+	})
+
+	return int64(len(results)), err
+}
+
+func (r memoryDevice) Revoke(ctx context.Context, model *domain.Device) error {
+	return r.store.set(ctx, domain.DEVICE_COLLECTION, model.ID, bson.D{
+		{Key: "status", Value: model.Status},
+		{Key: "revoked_time", Value: model.RevokedTime},
+	})
+}
+
+func (r memoryDevice) filter(ctx context.Context, match func(model domain.Device) bool) ([]domain.Device, error) {
+	var results []domain.Device
+	err := r.store.each(ctx, domain.DEVICE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Device{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFraud struct {
+	store *memoryStore
+}
+
+func (r memoryFraud) Save(ctx context.Context, model *domain.Fraud) error {
+	return r.store.insert(ctx, domain.FRAUD_COLLECTION, model)
+}
+
+func (r memoryFraud) FindPendingReview(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.Fraud, error) {
+
+	results, err := r.filter(ctx, func(model domain.Fraud) bool {
+		return model.CorporateID == corporateID && model.Decision == domain.FRAUD_DECISION_REVIEW &&
+			model.ReviewStatus == domain.FRAUD_REVIEW_PENDING
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
+
+func (r memoryFraud) CloseReview(ctx context.Context, transactionCode string, model domain.Fraud) error {
+	results, err := r.filter(ctx, func(current domain.Fraud) bool {
+		return current.TransactionCode == transactionCode && current.Decision == domain.FRAUD_DECISION_REVIEW
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, current := range results {
+		err = r.store.set(ctx, domain.FRAUD_COLLECTION, current.ID, bson.D{
+			{Key: "review_status", Value: domain.FRAUD_REVIEW_CLOSED},
+			{Key: "review_result", Value: model.ReviewResult},
+			{Key: "reviewer", Value: model.Reviewer},
+			{Key: "review_time", Value: model.ReviewTime},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r memoryFraud) filter(ctx context.Context, match func(model domain.Fraud) bool) ([]domain.Fraud, error) {
+	var results []domain.Fraud
+	err := r.store.each(ctx, domain.FRAUD_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Fraud{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryIPAllowlist struct {
+	store *memoryStore
+}
+
+func (r memoryIPAllowlist) SaveHistory(ctx context.Context, model *domain.IPAllowlistHistory) error {
+	return r.store.insert(ctx, domain.IP_ALLOWLIST_HISTORY_COLLECTION, model)
+}
+
+func (r memoryIPAllowlist) FindHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.IPAllowlistHistory, error) {
+
+	var results []domain.IPAllowlistHistory
+	err := r.store.each(ctx, domain.IP_ALLOWLIST_HISTORY_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.IPAllowlistHistory{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryKYC struct {
+	store *memoryStore
+}
+
+func (r memoryKYC) Save(ctx context.Context, model *domain.KYCCase) error {
+	return r.store.insert(ctx, domain.KYC_CASE_COLLECTION, model)
+}
+
+func (r memoryKYC) Update(ctx context.Context, model *domain.KYCCase) error {
+	return r.store.update(ctx, domain.KYC_CASE_COLLECTION, model)
+}
+
+func (r memoryKYC) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.KYCCase, error) {
+	model := domain.KYCCase{}
+	err := r.store.find(ctx, domain.KYC_CASE_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryKYC) FindByUserStatus(ctx context.Context, userID primitive.ObjectID,
+	status []string) (domain.KYCCase, error) {
+
+	results, err := r.filter(ctx, func(model domain.KYCCase) bool {
+		if model.UserID != userID {
+			return false
+		}
+
+		for _, value := range status {
+			if model.Status == value {
+				return true
+			}
+		}
+
+		return false
+	})
+	if err != nil || len(results) == 0 {
+		return domain.KYCCase{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+func (r memoryKYC) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, status string, page string,
+	limit string) ([]domain.KYCCase, error) {
+
+	results, err := r.filter(ctx, func(model domain.KYCCase) bool {
+		return model.CorporateID == corporateID && (status == "" || model.Status == status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortKYCCases(results, page, limit), nil
+}
+
+func (r memoryKYC) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.KYCCase, error) {
+	results, err := r.filter(ctx, func(model domain.KYCCase) bool {
+		return model.UserID == userID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortKYCCases(results, "", ""), nil
+}
+
+func (r memoryKYC) filter(ctx context.Context, match func(model domain.KYCCase) bool) ([]domain.KYCCase, error) {
+	var results []domain.KYCCase
+	err := r.store.each(ctx, domain.KYC_CASE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.KYCCase{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
+
+// Newest first by time then paged, same as mongoFind
+func sortKYCCases(results []domain.KYCCase, page string, limit string) []domain.KYCCase {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end]
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryLimit struct {
+	store *memoryStore
+}
+
+func (r memoryLimit) Save(ctx context.Context, model *domain.Limit) error {
+	return r.store.insert(ctx, domain.LIMIT_COLLECTION, model)
+}
+
+func (r memoryLimit) Update(ctx context.Context, model *domain.Limit) error {
+	return r.store.update(ctx, domain.LIMIT_COLLECTION, model)
+}
+
+func (r memoryLimit) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Limit, error) {
+	model := domain.Limit{}
+	err := r.store.find(ctx, domain.LIMIT_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryLimit) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Limit, error) {
+	return r.filter(ctx, func(model domain.Limit) bool {
+		return model.CorporateID == corporateID
+	})
+}
+
+func (r memoryLimit) FindMatch(ctx context.Context, corporateID primitive.ObjectID, actorType string, tiers []string,
+	transactionType string) ([]domain.Limit, error) {
+
+	return r.filter(ctx, func(model domain.Limit) bool {
+		tierMatched := model.Tier == domain.LIMIT_ANY
+		for _, tier := range tiers {
+			tierMatched = tierMatched || model.Tier == tier
+		}
+
+		return model.CorporateID == corporateID && tierMatched &&
+			(model.ActorType == actorType || model.ActorType == domain.LIMIT_ANY) &&
+			(model.TransactionType == transactionType || model.TransactionType == domain.LIMIT_ANY)
+	})
+}
+
+// Newest first, same as the mongo order by ID
+func (r memoryLimit) filter(ctx context.Context, match func(model domain.Limit) bool) ([]domain.Limit, error) {
+	var results []domain.Limit
+	err := r.store.each(ctx, domain.LIMIT_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Limit{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst(results)
+
+	return results, nil
+}
+
+func (r memoryLimit) TouchUsage(ctx context.Context, ownerID primitive.ObjectID) error {
+	return r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.LIMIT_USAGE_COLLECTION, ownerID)
+		version := int64(0)
+		if found {
+			version, _ = current.data.Lookup("version").AsInt64OK()
+		} else {
+			current.seq = view.nextSeq()
+		}
+
+		data, err := bson.Marshal(bson.D{{Key: "_id", Value: ownerID}, {Key: "version", Value: version + 1}})
+		if err != nil {
+			return err
+		}
+
+		current.data = data
+		view.put(domain.LIMIT_USAGE_COLLECTION, ownerID, current)
+
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryLockoutAudit struct {
+	store *memoryStore
+}
+
+func (r memoryLockoutAudit) Save(ctx context.Context, model *domain.LockoutAudit) error {
+	return r.store.insert(ctx, domain.LOCKOUT_AUDIT_COLLECTION, model)
+}
+
+func (r memoryLockoutAudit) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.LockoutAudit, error) {
+
+	var results []domain.LockoutAudit
+	err := r.store.each(ctx, domain.LOCKOUT_AUDIT_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.LockoutAudit{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
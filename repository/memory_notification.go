@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryNotification struct {
+	store *memoryStore
+}
+
+func (r memoryNotification) FindTemplate(ctx context.Context, corporateID primitive.ObjectID, key string,
+	language string) (domain.MessageTemplate, error) {
+
+	results, err := r.templates(ctx, func(model domain.MessageTemplate) bool {
+		return model.CorporateID == corporateID && model.Key == key && model.Language == language
+	})
+	if err != nil || len(results) == 0 {
+		return domain.MessageTemplate{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+func (r memoryNotification) FindTemplates(ctx context.Context,
+	corporateID primitive.ObjectID) ([]domain.MessageTemplate, error) {
+
+	results, err := r.templates(ctx, func(model domain.MessageTemplate) bool {
+		return model.CorporateID == corporateID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst(results)
+
+	return results, nil
+}
+
+func (r memoryNotification) SaveTemplate(ctx context.Context, model *domain.MessageTemplate) error {
+	existing, err := r.FindTemplate(ctx, model.CorporateID, model.Key, model.Language)
+	if err == ErrNotFound {
+		return r.store.insert(ctx, domain.MESSAGE_TEMPLATE_COLLECTION, model)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	model.ID = existing.ID
+	return r.store.update(ctx, domain.MESSAGE_TEMPLATE_COLLECTION, model)
+}
+
+func (r memoryNotification) SaveLog(ctx context.Context, model *domain.NotificationLog) error {
+	return r.store.insert(ctx, domain.NOTIFICATION_LOG_COLLECTION, model)
+}
+
+func (r memoryNotification) FindLogs(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.NotificationLog, error) {
+
+	var results []domain.NotificationLog
+	err := r.store.each(ctx, domain.NOTIFICATION_LOG_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.NotificationLog{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
+
+func (r memoryNotification) templates(ctx context.Context,
+	match func(model domain.MessageTemplate) bool) ([]domain.MessageTemplate, error) {
+
+	var results []domain.MessageTemplate
+	err := r.store.each(ctx, domain.MESSAGE_TEMPLATE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.MessageTemplate{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryOTP struct {
+	store *memoryStore
+}
+
+// One code per user and purpose, same as the unique index
+func (r memoryOTP) Save(ctx context.Context, model *domain.OTP) error {
+	return r.store.insertUnique(ctx, domain.OTP_COLLECTION, model, func(data bson.Raw) (bool, error) {
+		current := domain.OTP{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.UserID == model.UserID && current.Purpose == model.Purpose, err
+	})
+}
+
+func (r memoryOTP) Update(ctx context.Context, model *domain.OTP) error {
+	return r.store.update(ctx, domain.OTP_COLLECTION, model)
+}
+
+func (r memoryOTP) FindByUser(ctx context.Context, userID primitive.ObjectID, purpose string) (domain.OTP, error) {
+	result := domain.OTP{}
+	found := false
+	err := r.store.each(ctx, domain.OTP_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.OTP{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.UserID == userID && model.Purpose == purpose {
+			result, found = model, true
+		}
+
+		return err == nil && !found, err
+	})
+	if err != nil || !found {
+		return domain.OTP{}, firstError(err, ErrNotFound)
+	}
+
+	return result, nil
+}
+
+func (r memoryOTP) Consume(ctx context.Context, model domain.OTP) (bool, error) {
+	return r.store.setIf(ctx, domain.OTP_COLLECTION, model.ID, func(data bson.Raw) (bool, error) {
+		current := domain.OTP{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.CodeHash == model.CodeHash && !current.Used, err
+	}, bson.D{{Key: "used", Value: true}})
+}
+
+func (r memoryOTP) ReduceAttempt(ctx context.Context, ID primitive.ObjectID) error {
+	return r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.OTP_COLLECTION, ID)
+		if !found {
+			return nil
+		}
+
+		model := domain.OTP{}
+		err := bson.Unmarshal(current.data, &model)
+		if err != nil || model.Attempt <= 0 {
+			return err
+		}
+
+		return setView(view, domain.OTP_COLLECTION, ID, bson.D{{Key: "attempt", Value: model.Attempt - 1}})
+	})
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRequestAccessBalance struct {
+	store *memoryStore
+}
+
+func (r memoryRequestAccessBalance) Save(ctx context.Context, model *domain.RequestAccessBalance) error {
+	return r.store.insert(ctx, domain.RAB_COLLECTION_NAME, model)
+}
+
+func (r memoryRequestAccessBalance) Update(ctx context.Context, model *domain.RequestAccessBalance) error {
+	return r.store.update(ctx, domain.RAB_COLLECTION_NAME, model)
+}
+
+func (r memoryRequestAccessBalance) FindByID(ctx context.Context,
+	ID primitive.ObjectID) (domain.RequestAccessBalance, error) {
+
+	model := domain.RequestAccessBalance{}
+	err := r.store.find(ctx, domain.RAB_COLLECTION_NAME, ID, &model)
+
+	return model, err
+}
+
+func (r memoryRequestAccessBalance) FindByRequester(ctx context.Context, requesterID primitive.ObjectID,
+	status string, page string, limit string) ([]domain.RequestAccessBalance, error) {
+
+	return r.filter(ctx, page, limit, func(model domain.RequestAccessBalance) bool {
+		return model.BalanceRequester.ID == requesterID && containsStatus(model.Status, status)
+	})
+}
+
+func (r memoryRequestAccessBalance) FindByOwner(ctx context.Context, ownerID primitive.ObjectID,
+	status string, page string, limit string) ([]domain.RequestAccessBalance, error) {
+
+	return r.filter(ctx, page, limit, func(model domain.RequestAccessBalance) bool {
+		return model.BalanceOwner.ID == ownerID && containsStatus(model.Status, status)
+	})
+}
+
+func (r memoryRequestAccessBalance) filter(ctx context.Context, page string, limit string,
+	match func(model domain.RequestAccessBalance) bool) ([]domain.RequestAccessBalance, error) {
+
+	var results []domain.RequestAccessBalance
+	err := r.store.each(ctx, domain.RAB_COLLECTION_NAME, func(data bson.Raw) (bool, error) {
+		model := domain.RequestAccessBalance{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
+
+// Same as the case insensitive regex the mongo query use for plain status
+func containsStatus(current string, status string) bool {
+	return strings.Contains(strings.ToLower(current), strings.ToLower(status))
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRequestNonce struct {
+	store *memoryStore
+}
+
+func (r memoryRequestNonce) Save(ctx context.Context, model *domain.RequestNonce) error {
+	return r.store.insertUnique(ctx, domain.REQUEST_NONCE_COLLECTION, model, func(data bson.Raw) (bool, error) {
+		current := domain.RequestNonce{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.CorporateID == model.CorporateID && current.RequestID == model.RequestID, err
+	})
+}
+
+func (r memoryRequestNonce) Count(ctx context.Context, corporateID primitive.ObjectID, requestID string) (int64, error) {
+	var count int64
+	err := r.store.each(ctx, domain.REQUEST_NONCE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.RequestNonce{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.CorporateID == corporateID && model.RequestID == requestID {
+			count += 1
+		}
+
+		return err == nil, err
+	})
+
+	return count, err
+}
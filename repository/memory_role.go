@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRole struct {
+	store *memoryStore
+}
+
+// Name is unique in the corporate, same as the unique index
+func (r memoryRole) Save(ctx context.Context, model *domain.Role) error {
+	return r.store.insertUnique(ctx, domain.ROLE_COLLECTION, model, func(data bson.Raw) (bool, error) {
+		current := domain.Role{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.CorporateID == model.CorporateID && current.Name == model.Name, err
+	})
+}
+
+func (r memoryRole) Update(ctx context.Context, model *domain.Role) error {
+	return r.store.update(ctx, domain.ROLE_COLLECTION, model)
+}
+
+func (r memoryRole) FindByName(ctx context.Context, corporateID primitive.ObjectID, name string) (domain.Role, error) {
+	results, err := r.filter(ctx, func(model domain.Role) bool {
+		return model.CorporateID == corporateID && model.Name == name
+	})
+	if err != nil || len(results) == 0 {
+		return domain.Role{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+func (r memoryRole) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Role, error) {
+	results, err := r.filter(ctx, func(model domain.Role) bool {
+		return model.CorporateID == corporateID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst(results)
+
+	return results, nil
+}
+
+func (r memoryRole) filter(ctx context.Context, match func(model domain.Role) bool) ([]domain.Role, error) {
+	var results []domain.Role
+	err := r.store.each(ctx, domain.ROLE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Role{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Named apart from memorySession, the transaction of the memory store
+type memorySessionRepository struct {
+	store *memoryStore
+}
+
+func (r memorySessionRepository) Save(ctx context.Context, model *domain.Session) error {
+	return r.store.insert(ctx, domain.SESSION_COLLECTION, model)
+}
+
+func (r memorySessionRepository) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Session, error) {
+	model := domain.Session{}
+	err := r.store.find(ctx, domain.SESSION_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memorySessionRepository) Rotate(ctx context.Context, model *domain.Session, expectedHash string) (bool, error) {
+	return r.store.setIf(ctx, domain.SESSION_COLLECTION, model.ID, func(data bson.Raw) (bool, error) {
+		current := domain.Session{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.RefreshTokenHash == expectedHash && !current.Revoked, err
+	}, bson.D{
+		{Key: "refresh_token_hash", Value: model.RefreshTokenHash},
+		{Key: "previous_refresh_token_hash", Value: model.PreviousRefreshTokenHash},
+		{Key: "token_id", Value: model.TokenID},
+		{Key: "token_expired_time", Value: model.TokenExpiredTime},
+		{Key: "last_refresh_time", Value: model.LastRefreshTime},
+	})
+}
+
+func (r memorySessionRepository) Revoke(ctx context.Context, model *domain.Session) error {
+	return r.store.set(ctx, domain.SESSION_COLLECTION, model.ID, bson.D{
+		{Key: "revoked", Value: true},
+		{Key: "revoked_time", Value: model.RevokedTime},
+	})
+}
+
+// Newest first, same as the mongo order by ID
+func (r memorySessionRepository) FindActiveByUser(ctx context.Context, userID primitive.ObjectID,
+	now time.Time) ([]domain.Session, error) {
+
+	var results []domain.Session
+	err := r.store.each(ctx, domain.SESSION_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Session{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.UserID == userID && !model.Revoked && model.ExpiredTime.After(now) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst(results)
+
+	return results, nil
+}
+
+func (r memorySessionRepository) SaveRevokedToken(ctx context.Context, model *domain.RevokedToken) error {
+	err := r.store.insertUnique(ctx, domain.REVOKED_TOKEN_COLLECTION, model, func(data bson.Raw) (bool, error) {
+		current := domain.RevokedToken{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && current.TokenID == model.TokenID, err
+	})
+	if err == ErrDuplicateKey {
+		return nil
+	}
+
+	return err
+}
+
+func (r memorySessionRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	found := false
+	err := r.store.each(ctx, domain.REVOKED_TOKEN_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.RevokedToken{}
+		err := bson.Unmarshal(data, &model)
+		found = err == nil && model.TokenID == tokenID
+
+		return err == nil && !found, err
+	})
+
+	return found, err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryStatement struct {
+	store *memoryStore
+}
+
+func (r memoryStatement) Save(ctx context.Context, model domain.Statement) error {
+	return r.store.insert(ctx, domain.STATEMENT_COLLECTION_NAME, model)
+}
+
+func (r memoryStatement) FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID,
+	page string, limit string) ([]domain.Statement, error) {
+
+	var results []domain.Statement
+	err := r.store.each(ctx, domain.STATEMENT_COLLECTION_NAME, func(data bson.Raw) (bool, error) {
+		model := domain.Statement{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.BalanceID == balanceID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end], nil
+}
+
+func (r memoryStatement) FindSequenced(ctx context.Context, balanceID primitive.ObjectID) ([]domain.Statement, error) {
+	var results []domain.Statement
+	err := r.store.each(ctx, domain.STATEMENT_COLLECTION_NAME, func(data bson.Raw) (bool, error) {
+		model := domain.Statement{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.BalanceID == balanceID && model.Sequence > 0 {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Sequence < results[j].Sequence
+	})
+
+	return results, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memorySubBalance struct {
+	store *memoryStore
+}
+
+func (r memorySubBalance) Deposit(ctx context.Context, balanceID primitive.ObjectID, index int, amount int,
+	currency string) (domain.SubBalance, error) {
+
+	model := domain.SubBalance{}
+	err := r.store.view(ctx, true, func(view memoryView) error {
+		for _, document := range view.list(domain.SUB_BALANCE_COLLECTION) {
+			current := domain.SubBalance{}
+			err := bson.Unmarshal(document.data, &current)
+			if err != nil {
+				return err
+			}
+
+			if current.BalanceID == balanceID && current.Index == index {
+				model = current
+				break
+			}
+		}
+
+		// Upsert, same as mongo the new sub balance take the currency given
+		document := memoryDocument{}
+		if model.ID.IsZero() {
+			model = domain.SubBalance{
+				ID:        primitive.NewObjectID(),
+				BalanceID: balanceID,
+				Index:     index,
+				Currency:  domain.NormalizeCurrency(currency),
+			}
+			document.seq = view.nextSeq()
+		} else {
+			document, _ = view.get(domain.SUB_BALANCE_COLLECTION, model.ID)
+		}
+
+		model.Amount += amount
+		model.Version += 1
+		model.StatementSequence += 1
+
+		data, err := bson.Marshal(model)
+		if err != nil {
+			return err
+		}
+
+		document.data = data
+		view.put(domain.SUB_BALANCE_COLLECTION, model.ID, document)
+		return nil
+	})
+	if err != nil {
+		return domain.SubBalance{}, err
+	}
+
+	return model, nil
+}
+
+func (r memorySubBalance) FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID) ([]domain.SubBalance, error) {
+	var results []domain.SubBalance
+	err := r.store.each(ctx, domain.SUB_BALANCE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.SubBalance{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.BalanceID == balanceID {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+
+	return results, nil
+}
+
+func (r memorySubBalance) SumByBalanceIDs(ctx context.Context,
+	balanceIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+
+	wanted := map[primitive.ObjectID]bool{}
+	for _, ID := range balanceIDs {
+		wanted[ID] = true
+	}
+
+	results := map[primitive.ObjectID]int{}
+	err := r.store.each(ctx, domain.SUB_BALANCE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.SubBalance{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && wanted[model.BalanceID] {
+			results[model.BalanceID] += model.Amount
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
+
+func (r memorySubBalance) FindWithAmount(ctx context.Context, limit int) ([]domain.SubBalance, error) {
+	var results []domain.SubBalance
+	err := r.store.each(ctx, domain.SUB_BALANCE_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.SubBalance{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && model.Amount > 0 {
+			results = append(results, model)
+		}
+
+		return err == nil && (limit <= 0 || len(results) < limit), err
+	})
+
+	return results, err
+}
+
+func (r memorySubBalance) Consolidate(ctx context.Context, model domain.SubBalance) error {
+	return r.store.view(ctx, true, func(view memoryView) error {
+		current, found := view.get(domain.SUB_BALANCE_COLLECTION, model.ID)
+		if !found {
+			return ErrNotFound
+		}
+
+		subBalance := domain.SubBalance{}
+		err := bson.Unmarshal(current.data, &subBalance)
+		if err != nil {
+			return err
+		}
+
+		if subBalance.Amount < model.Amount {
+			return ErrInsufficientBalance
+		}
+
+		balanceDocument, found := view.get(domain.BALANCE_COLLECTION, model.BalanceID)
+		if !found {
+			return ErrNotFound
+		}
+
+		balance := domain.Balance{}
+		err = bson.Unmarshal(balanceDocument.data, &balance)
+		if err != nil {
+			return err
+		}
+
+		err = setView(view, domain.SUB_BALANCE_COLLECTION, model.ID, bson.D{
+			{Key: "amount", Value: subBalance.Amount - model.Amount},
+			{Key: "version", Value: subBalance.Version + 1},
+		})
+		if err != nil {
+			return err
+		}
+
+		return setView(view, domain.BALANCE_COLLECTION, model.BalanceID, bson.D{
+			{Key: "amount", Value: balance.Amount + model.Amount},
+			{Key: "version", Value: balance.Version + 1},
+		})
+	})
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryTransaction struct {
+	store *memoryStore
+}
+
+func (r memoryTransaction) Save(ctx context.Context, model *domain.Transaction) error {
+	return r.store.insert(ctx, domain.TRANSACTION_COLLECTION, model)
+}
+
+func (r memoryTransaction) Update(ctx context.Context, model *domain.Transaction) error {
+	return r.store.update(ctx, domain.TRANSACTION_COLLECTION, model)
+}
+
+func (r memoryTransaction) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Transaction, error) {
+	model := domain.Transaction{}
+	err := r.store.find(ctx, domain.TRANSACTION_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryTransaction) FindByCode(ctx context.Context, code string, status string) (domain.Transaction, error) {
+	results, err := r.filter(ctx, func(model domain.Transaction) bool {
+		return matchString(model.TransactionCode, code) && (status == "" || model.Status == status)
+	})
+	if err != nil || len(results) == 0 {
+		return domain.Transaction{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+func (r memoryTransaction) FindByGatewayReference(ctx context.Context, reference string,
+	status string) (domain.Transaction, error) {
+
+	results, err := r.filter(ctx, func(model domain.Transaction) bool {
+		return model.GatewayReference == reference && (status == "" || model.Status == status)
+	})
+	if err != nil || len(results) == 0 {
+		return domain.Transaction{}, firstError(err, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+// Transaction has no balance_id, own balance never match same as the mongo query
+func (r memoryTransaction) FindByActor(ctx context.Context, accountNumber string, ownBalance []primitive.ObjectID,
+	page string, limit string) ([]domain.Transaction, error) {
+
+	results, err := r.filter(ctx, func(model domain.Transaction) bool {
+		return matchString(model.From.AccountNumber, accountNumber) || matchString(model.To.AccountNumber, accountNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortTransactions(results, page, limit), nil
+}
+
+func (r memoryTransaction) FindByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+	page string, limit string) ([]domain.Transaction, error) {
+
+	results, err := r.filter(ctx, func(model domain.Transaction) bool {
+		return model.CorporateID == corporateID && model.Status == status
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortTransactions(results, page, limit), nil
+}
+
+func (r memoryTransaction) CountByDeviceSince(ctx context.Context, deviceID string, since time.Time) (int64, error) {
+	return r.count(ctx, func(model domain.Transaction) bool {
+		return matchString(model.DeviceID, deviceID) && !model.Time.Before(since)
+	})
+}
+
+func (r memoryTransaction) CountByBalanceSince(ctx context.Context, balanceID primitive.ObjectID,
+	since time.Time) (int64, error) {
+
+	return r.count(ctx, func(model domain.Transaction) bool {
+		return model.FromBalanceID == balanceID && !model.Time.Before(since)
+	})
+}
+
+func (r memoryTransaction) CountRoundAmountSince(ctx context.Context, balanceID primitive.ObjectID, unit int,
+	since time.Time) (int64, error) {
+
+	return r.count(ctx, func(model domain.Transaction) bool {
+		return model.FromBalanceID == balanceID && !model.Time.Before(since) && unit != 0 && model.SubAmount%unit == 0
+	})
+}
+
+func (r memoryTransaction) CountBeneficiary(ctx context.Context, balanceID primitive.ObjectID,
+	transaction domain.Transaction) (int64, error) {
+
+	return r.count(ctx, func(model domain.Transaction) bool {
+		if model.FromBalanceID != balanceID || model.Status != domain.COMPLETED_STATUS {
+			return false
+		}
+
+		if !transaction.ToBalanceID.IsZero() {
+			return model.ToBalanceID == transaction.ToBalanceID
+		}
+
+		return matchString(model.To.InstitutionCode, transaction.To.InstitutionCode) &&
+			matchString(model.To.AccountNumber, transaction.To.AccountNumber)
+	})
+}
+
+func (r memoryTransaction) SumOutflowSince(ctx context.Context, balanceIDs []primitive.ObjectID, types []string,
+	since time.Time) (int, error) {
+
+	others, deducts := splitDeductType(types)
+	results, err := r.filter(ctx, func(model domain.Transaction) bool {
+		if model.Time.Before(since) || !containsString(outflowStatuses, model.Status) {
+			return false
+		}
+
+		return (containsString(others, model.Type) && containsObjectID(balanceIDs, model.FromBalanceID)) ||
+			(containsString(deducts, model.Type) && containsObjectID(balanceIDs, model.ToBalanceID))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, a := range results {
+		total += a.Amount
+	}
+
+	return total, nil
+}
+
+func (r memoryTransaction) filter(ctx context.Context, match func(model domain.Transaction) bool) ([]domain.Transaction, error) {
+	var results []domain.Transaction
+	err := r.store.each(ctx, domain.TRANSACTION_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.Transaction{}
+		err := bson.Unmarshal(data, &model)
+		if err == nil && match(model) {
+			results = append(results, model)
+		}
+
+		return err == nil, err
+	})
+
+	return results, err
+}
+
+func (r memoryTransaction) count(ctx context.Context, match func(model domain.Transaction) bool) (int64, error) {
+	results, err := r.filter(ctx, match)
+
+	return int64(len(results)), err
+}
+
+func sortTransactions(results []domain.Transaction, page string, limit string) []domain.Transaction {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time.Time)
+	})
+
+	start, end := memoryPage(len(results), page, limit)
+
+	return results[start:end]
+}
+
+func containsString(values []string, value string) bool {
+	for _, a := range values {
+		if a == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsObjectID(values []primitive.ObjectID, value primitive.ObjectID) bool {
+	for _, a := range values {
+		if a == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Empty omitempty field is not stored, mongo never match it with empty value
+func matchString(field string, value string) bool {
+	return value != "" && field == value
+}
+
+func firstError(err error, fallback error) error {
+	if err != nil {
+		return err
+	}
+
+	return fallback
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryUser struct {
+	store *memoryStore
+}
+
+func (r memoryUser) Save(ctx context.Context, model *domain.User) error {
+	return r.store.insert(ctx, domain.USER_COLLECTION, model)
+}
+
+func (r memoryUser) Update(ctx context.Context, model *domain.User) error {
+	return r.store.update(ctx, domain.USER_COLLECTION, model)
+}
+
+func (r memoryUser) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.User, error) {
+	model := domain.User{}
+	err := r.store.find(ctx, domain.USER_COLLECTION, ID, &model)
+
+	return model, err
+}
+
+func (r memoryUser) FindByPhoneNumber(ctx context.Context, corporateID primitive.ObjectID,
+	phoneNumber string) (domain.User, error) {
+
+	return r.findOne(ctx, func(model domain.User) bool {
+		return model.CorporateID == corporateID && matchString(model.PhoneNumber, phoneNumber) && !model.Pending
+	})
+}
+
+func (r memoryUser) FindByEmailOrPhoneNumber(ctx context.Context, corporateID primitive.ObjectID, email string,
+	phoneNumber string) (domain.User, error) {
+
+	return r.findOne(ctx, func(model domain.User) bool {
+		return model.CorporateID == corporateID && (matchString(model.Email, email) || matchString(model.PhoneNumber, phoneNumber))
+	})
+}
+
+func (r memoryUser) UpdateRole(ctx context.Context, model *domain.User) error {
+	return r.store.set(ctx, domain.USER_COLLECTION, model.ID, bson.D{
+		{Key: "role", Value: model.Role},
+		{Key: "audit.updated_time", Value: model.Audit.UpdatedTime},
+	})
+}
+
+func (r memoryUser) UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error {
+	return r.store.set(ctx, domain.USER_COLLECTION, ID, bson.D{{Key: "pin", Value: hash}})
+}
+
+func (r memoryUser) UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error {
+	return r.store.set(ctx, domain.USER_COLLECTION, ID, bson.D{{Key: "list_balance", Value: balances}})
+}
+
+func (r memoryUser) DeleteInactive(ctx context.Context, model *domain.User) error {
+	return r.store.remove(ctx, domain.USER_COLLECTION, model.ID, func(data bson.Raw) (bool, error) {
+		current := domain.User{}
+		err := bson.Unmarshal(data, &current)
+
+		return err == nil && !current.Active && !current.Pending, err
+	})
+}
+
+func (r memoryUser) findOne(ctx context.Context, match func(model domain.User) bool) (domain.User, error) {
+	result := domain.User{}
+	found := false
+	err := r.store.each(ctx, domain.USER_COLLECTION, func(data bson.Raw) (bool, error) {
+		model := domain.User{}
+		err := bson.Unmarshal(data, &model)
+		if err != nil {
+			return false, err
+		}
+
+		if match(model) {
+			result, found = model, true
+		}
+
+		return !found, nil
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if !found {
+		return domain.User{}, ErrNotFound
+	}
+
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Repositories backed by the given database, transaction need a replica set
+func NewMongo(db *mongo.Database) *Repositories {
+	return &Repositories{
+		Balance: mongoBalance{db.Collection(domain.BALANCE_COLLECTION)},
+		SubBalance: mongoSubBalance{
+			collection: db.Collection(domain.SUB_BALANCE_COLLECTION),
+			balance:    db.Collection(domain.BALANCE_COLLECTION),
+		},
+		Transaction: mongoTransaction{db.Collection(domain.TRANSACTION_COLLECTION)},
+		Statement:   mongoStatement{db.Collection(domain.STATEMENT_COLLECTION_NAME)},
+		User:        mongoUser{db.Collection(domain.USER_COLLECTION)},
+		Corporate:   mongoCorporate{db.Collection(domain.CORPORATE_COLLECTION)},
+		Bulk: mongoBulk{
+			inquiry:  db.Collection(domain.BULK_INQUIRY_COLLECTION),
+			transfer: db.Collection(domain.BULK_TRANSFER_COLLECTION),
+			history:  db.Collection(domain.BULK_APPROVAL_HISTORY_COLLECTION),
+		},
+		Callback:           mongoCallback{db.Collection(domain.CALLBACK_HISTORY_COLLECTION)},
+		Device:             mongoDevice{db.Collection(domain.DEVICE_COLLECTION)},
+		BiometricChallenge: mongoBiometricChallenge{db.Collection(domain.BIOMETRIC_CHALLENGE_COLLECTION)},
+		Fraud:              mongoFraud{db.Collection(domain.FRAUD_COLLECTION)},
+		RequestAccess:      mongoRequestAccessBalance{db.Collection(domain.RAB_COLLECTION_NAME)},
+		KYC:                mongoKYC{db.Collection(domain.KYC_CASE_COLLECTION)},
+		Limit:              mongoLimit{db.Collection(domain.LIMIT_COLLECTION), db.Collection(domain.LIMIT_USAGE_COLLECTION)},
+		LockoutAudit:       mongoLockoutAudit{db.Collection(domain.LOCKOUT_AUDIT_COLLECTION)},
+		Notification: mongoNotification{
+			template: db.Collection(domain.MESSAGE_TEMPLATE_COLLECTION),
+			log:      db.Collection(domain.NOTIFICATION_LOG_COLLECTION),
+		},
+		OTP:  mongoOTP{db.Collection(domain.OTP_COLLECTION)},
+		Role: mongoRole{db.Collection(domain.ROLE_COLLECTION)},
+		Session: mongoSession{
+			session:      db.Collection(domain.SESSION_COLLECTION),
+			revokedToken: db.Collection(domain.REVOKED_TOKEN_COLLECTION),
+		},
+		IPAllowlist:  mongoIPAllowlist{db.Collection(domain.IP_ALLOWLIST_HISTORY_COLLECTION)},
+		RequestNonce: mongoRequestNonce{db.Collection(domain.REQUEST_NONCE_COLLECTION)},
+		Transactor:   mongoTransactor{db.Client()},
+	}
+}
+
+type mongoTransactor struct {
+	client *mongo.Client
+}
+
+func (t mongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Already inside a transaction, join it instead of starting a nested one
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return t.client.UseSessionWithOptions(
+		ctx, options.Session().SetDefaultReadPreference(readpref.Primary()),
+		func(sctx mongo.SessionContext) error {
+			return database.RunTransactionWithRetry(sctx, func(session mongo.SessionContext) error {
+				err := session.StartTransaction(options.Transaction().
+					SetReadConcern(readconcern.Snapshot()).
+					SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
+				)
+				if err != nil {
+					return err
+				}
+
+				err = fn(session)
+				if err != nil {
+					session.AbortTransaction(session)
+					return err
+				}
+
+				return database.CommitWithRetry(session)
+			})
+		},
+	)
+}
+
+func (t mongoTransactor) WithoutTransaction(ctx context.Context) context.Context {
+	return database.WithoutSession(ctx)
+}
+
+func mongoInsert(ctx context.Context, collection *mongo.Collection, model domain.BaseModel) error {
+	result, err := collection.InsertOne(ctx, model)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateKey
+	}
+
+	if err != nil {
+		return err
+	}
+
+	model.SetDocumentID(result.InsertedID.(primitive.ObjectID))
+
+	return nil
+}
+
+// Set every field of the document, same as database.SessionUpdateOne
+func mongoUpdate(ctx context.Context, collection *mongo.Collection, model domain.BaseModel) error {
+	document, err := toDocument(model)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": model.GetDocumentID()}}, bson.M{"$set": document})
+
+	return err
+}
+
+func mongoSet(ctx context.Context, collection *mongo.Collection, ID primitive.ObjectID, changes bson.M) error {
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": ID}, bson.M{"$set": changes})
+
+	return err
+}
+
+func mongoFindOne(ctx context.Context, collection *mongo.Collection, query bson.M, result interface{}) error {
+	return collection.FindOne(ctx, query).Decode(result)
+}
+
+// Newest first by time, page start from 1 and empty page or limit return all
+func mongoFind(ctx context.Context, collection *mongo.Collection, query bson.M, page string, limit string,
+	results interface{}) error {
+
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "time", Value: -1}})
+
+	if page != "" && limit != "" {
+		p, _ := strconv.Atoi(page)
+		l, _ := strconv.Atoi(limit)
+		opts.SetSkip(int64((p - 1) * l))
+		opts.SetLimit(int64(l))
+	}
+
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+// Newest first by ID, same as database.FindOrderByID without paging
+func mongoFindOrderByID(ctx context.Context, collection *mongo.Collection, query bson.M, results interface{}) error {
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}))
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, results)
+}
+
+func toDocument(v interface{}) (doc bson.D, err error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	err = bson.Unmarshal(data, &doc)
+	return
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoBalance struct {
+	collection *mongo.Collection
+}
+
+func (r mongoBalance) Save(ctx context.Context, model *domain.Balance) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoBalance) Update(ctx context.Context, model *domain.Balance) error {
+	document, err := toDocument(model)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": model.ID}, bson.M{
+		"$set": withoutKeys(document, balanceCounterKeys...),
+		"$inc": bson.M{"version": 1},
+	})
+
+	return err
+}
+
+// The guard is part of the update filter, concurrent adjust of the same
+// balance never read a stale amount and the document is never read first
+func (r mongoBalance) Adjust(ctx context.Context, ID primitive.ObjectID, amount int, subAmount int,
+	currency string) (domain.Balance, error) {
+
+	filter := bson.M{"_id": ID}
+	if amount < 0 {
+		filter["amount"] = bson.M{"$gte": -amount - subAmount}
+	}
+
+	// Balance without currency is legacy and take any currency
+	if currency != "" {
+		filter["currency"] = bson.M{"$in": bson.A{domain.NormalizeCurrency(currency), nil}}
+	}
+
+	model := domain.Balance{}
+	err := r.collection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"amount": amount, "version": 1, "statement_sequence": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&model)
+	if err != mongo.ErrNoDocuments {
+		return model, err
+	}
+
+	// Nothing matched, find out which condition failed
+	current, err := r.FindByID(ctx, ID)
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	err = checkAdjust(current, amount, subAmount, currency)
+	if err == nil {
+		// Amount changed between the update and the read, at the update it was not enough
+		err = ErrInsufficientBalance
+	}
+
+	return domain.Balance{}, err
+}
+
+func (r mongoBalance) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Balance, error) {
+	model := domain.Balance{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoBalance) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Balance, error) {
+	var results []domain.Balance
+	cursor, err := r.collection.Find(ctx, bson.M{"owner._id": ownerID},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &results)
+
+	return results, err
+}
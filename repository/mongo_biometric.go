@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoBiometricChallenge struct {
+	collection *mongo.Collection
+}
+
+func (r mongoBiometricChallenge) Save(ctx context.Context, model *domain.BiometricChallenge) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoBiometricChallenge) Consume(ctx context.Context, model domain.BiometricChallenge,
+	now time.Time) (bool, error) {
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":        model.ID,
+			"user_id":    model.UserID,
+			"purpose":    model.Purpose,
+			"device_id":  model.DeviceID,
+			"nonce_hash": model.NonceHash,
+			"used":       false,
+			"expired_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"used": true}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoBulk struct {
+	inquiry  *mongo.Collection
+	transfer *mongo.Collection
+	history  *mongo.Collection
+}
+
+func (r mongoBulk) SaveInquiry(ctx context.Context, model *domain.BulkInquiry) error {
+	return mongoInsert(ctx, r.inquiry, model)
+}
+
+func (r mongoBulk) UpdateInquiry(ctx context.Context, model *domain.BulkInquiry) error {
+	return mongoUpdate(ctx, r.inquiry, model)
+}
+
+func (r mongoBulk) FindInquiryByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkInquiry, error) {
+	model := domain.BulkInquiry{}
+	err := mongoFindOne(ctx, r.inquiry, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoBulk) SaveTransfer(ctx context.Context, model *domain.BulkTransfer) error {
+	return mongoInsert(ctx, r.transfer, model)
+}
+
+func (r mongoBulk) UpdateTransfer(ctx context.Context, model *domain.BulkTransfer) error {
+	return mongoUpdate(ctx, r.transfer, model)
+}
+
+func (r mongoBulk) FindTransferByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkTransfer, error) {
+	model := domain.BulkTransfer{}
+	err := mongoFindOne(ctx, r.transfer, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoBulk) FindTransfersByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+	page string, limit string) ([]domain.BulkTransfer, error) {
+
+	var results []domain.BulkTransfer
+	err := mongoFind(ctx, r.transfer, bson.M{"corporate_id": corporateID, "status": status}, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoBulk) AddApproval(ctx context.Context, ID primitive.ObjectID, approval domain.BulkApproval) (bool, error) {
+	result, err := r.transfer.UpdateOne(ctx,
+		bson.M{
+			"_id":                    ID,
+			"status":                 domain.BULK_PENDING_APPROVAL_STATUS,
+			"approvals.approver._id": bson.M{"$ne": approval.Approver.ID},
+		},
+		bson.M{"$push": bson.M{"approvals": approval}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r mongoBulk) SetTransferStatus(ctx context.Context, ID primitive.ObjectID, from string, to string) (bool, error) {
+	result, err := r.transfer.UpdateOne(ctx,
+		bson.M{"_id": ID, "status": from},
+		bson.M{"$set": bson.M{"status": to}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r mongoBulk) SaveApprovalHistory(ctx context.Context, model *domain.BulkApprovalHistory) error {
+	return mongoInsert(ctx, r.history, model)
+}
+
+func (r mongoBulk) FindApprovalHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.BulkApprovalHistory, error) {
+
+	var results []domain.BulkApprovalHistory
+	err := mongoFind(ctx, r.history, bson.M{"corporate_id": corporateID}, page, limit, &results)
+
+	return results, err
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoCallback struct {
+	collection *mongo.Collection
+}
+
+func (r mongoCallback) Save(ctx context.Context, model *domain.CallbackHistory) error {
+	return mongoInsert(ctx, r.collection, model)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoCorporate struct {
+	collection *mongo.Collection
+}
+
+func (r mongoCorporate) Save(ctx context.Context, model *domain.Corporate) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoCorporate) Update(ctx context.Context, model *domain.Corporate) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoCorporate) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Corporate, error) {
+	model := domain.Corporate{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoCorporate) UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"pin": hash})
+}
+
+func (r mongoCorporate) UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"list_balance": balances})
+}
+
+func (r mongoCorporate) UpdateBulkApprovals(ctx context.Context, ID primitive.ObjectID,
+	thresholds []domain.BulkApprovalThreshold) error {
+
+	return mongoSet(ctx, r.collection, ID, bson.M{"bulk_approvals": thresholds})
+}
+
+func (r mongoCorporate) UpdateMessaging(ctx context.Context, ID primitive.ObjectID, setting domain.MessagingSetting) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"messaging": setting})
+}
+
+func (r mongoCorporate) UpdateIdentity(ctx context.Context, ID primitive.ObjectID, setting domain.IdentitySetting) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"identity": setting})
+}
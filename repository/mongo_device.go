@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoDevice struct {
+	collection *mongo.Collection
+}
+
+func (r mongoDevice) Save(ctx context.Context, model *domain.Device) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoDevice) Update(ctx context.Context, model *domain.Device) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoDevice) FindTrusted(ctx context.Context, userID primitive.ObjectID, deviceID string) (domain.Device, error) {
+	model := domain.Device{}
+	query := bson.M{"user_id": userID, "device_id": deviceID, "status": domain.DEVICE_STATUS_TRUSTED}
+	err := mongoFindOne(ctx, r.collection, query, &model)
+
+	return model, err
+}
+
+func (r mongoDevice) FindTrustedByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.Device, error) {
+	var results []domain.Device
+	query := bson.M{"user_id": userID, "status": domain.DEVICE_STATUS_TRUSTED}
+	err := mongoFindOrderByID(ctx, r.collection, query, &results)
+
+	return results, err
+}
+
+func (r mongoDevice) CountTrusted(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "status": domain.DEVICE_STATUS_TRUSTED})
+}
+
+func (r mongoDevice) Revoke(ctx context.Context, model *domain.Device) error {
+	return mongoSet(ctx, r.collection, model.ID, bson.M{"status": model.Status, "revoked_time": model.RevokedTime})
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoFraud struct {
+	collection *mongo.Collection
+}
+
+func (r mongoFraud) Save(ctx context.Context, model *domain.Fraud) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoFraud) FindPendingReview(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.Fraud, error) {
+
+	var results []domain.Fraud
+	query := bson.M{
+		"corporate_id":  corporateID,
+		"decision":      domain.FRAUD_DECISION_REVIEW,
+		"review_status": domain.FRAUD_REVIEW_PENDING,
+	}
+	err := mongoFind(ctx, r.collection, query, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoFraud) CloseReview(ctx context.Context, transactionCode string, model domain.Fraud) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"transaction_code": transactionCode, "decision": domain.FRAUD_DECISION_REVIEW},
+		bson.M{"$set": bson.M{
+			"review_status": domain.FRAUD_REVIEW_CLOSED,
+			"review_result": model.ReviewResult,
+			"reviewer":      model.Reviewer,
+			"review_time":   model.ReviewTime,
+		}})
+
+	return err
+}
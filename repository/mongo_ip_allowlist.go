@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoIPAllowlist struct {
+	collection *mongo.Collection
+}
+
+func (r mongoIPAllowlist) SaveHistory(ctx context.Context, model *domain.IPAllowlistHistory) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoIPAllowlist) FindHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.IPAllowlistHistory, error) {
+
+	var results []domain.IPAllowlistHistory
+	err := mongoFind(ctx, r.collection, bson.M{"corporate_id": corporateID}, page, limit, &results)
+
+	return results, err
+}
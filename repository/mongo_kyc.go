@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoKYC struct {
+	collection *mongo.Collection
+}
+
+func (r mongoKYC) Save(ctx context.Context, model *domain.KYCCase) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoKYC) Update(ctx context.Context, model *domain.KYCCase) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoKYC) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.KYCCase, error) {
+	model := domain.KYCCase{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoKYC) FindByUserStatus(ctx context.Context, userID primitive.ObjectID,
+	status []string) (domain.KYCCase, error) {
+
+	model := domain.KYCCase{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"user_id": userID, "status": bson.M{"$in": status}}, &model)
+
+	return model, err
+}
+
+func (r mongoKYC) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, status string, page string,
+	limit string) ([]domain.KYCCase, error) {
+
+	query := bson.M{"corporate_id": corporateID}
+	if status != "" {
+		query["status"] = status
+	}
+
+	var results []domain.KYCCase
+	err := mongoFind(ctx, r.collection, query, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoKYC) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.KYCCase, error) {
+	var results []domain.KYCCase
+	err := mongoFind(ctx, r.collection, bson.M{"user_id": userID}, "", "", &results)
+
+	return results, err
+}
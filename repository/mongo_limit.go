@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLimit struct {
+	collection *mongo.Collection
+	usage      *mongo.Collection
+}
+
+func (r mongoLimit) Save(ctx context.Context, model *domain.Limit) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoLimit) Update(ctx context.Context, model *domain.Limit) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoLimit) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Limit, error) {
+	model := domain.Limit{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoLimit) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Limit, error) {
+	var results []domain.Limit
+	err := mongoFindOrderByID(ctx, r.collection, bson.M{"corporate_id": corporateID}, &results)
+
+	return results, err
+}
+
+func (r mongoLimit) FindMatch(ctx context.Context, corporateID primitive.ObjectID, actorType string, tiers []string,
+	transactionType string) ([]domain.Limit, error) {
+
+	tierFilter := bson.A{domain.LIMIT_ANY}
+	for _, tier := range tiers {
+		tierFilter = append(tierFilter, tier)
+	}
+
+	query := bson.M{
+		"corporate_id":     corporateID,
+		"actor_type":       bson.M{"$in": bson.A{actorType, domain.LIMIT_ANY}},
+		"tier":             bson.M{"$in": tierFilter},
+		"transaction_type": bson.M{"$in": bson.A{transactionType, domain.LIMIT_ANY}},
+	}
+
+	var results []domain.Limit
+	err := mongoFindOrderByID(ctx, r.collection, query, &results)
+
+	return results, err
+}
+
+func (r mongoLimit) TouchUsage(ctx context.Context, ownerID primitive.ObjectID) error {
+	_, err := r.usage.UpdateOne(ctx, bson.M{"_id": ownerID}, bson.M{"$inc": bson.M{"version": 1}},
+		options.Update().SetUpsert(true))
+
+	return err
+}
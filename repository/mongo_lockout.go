@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoLockoutAudit struct {
+	collection *mongo.Collection
+}
+
+func (r mongoLockoutAudit) Save(ctx context.Context, model *domain.LockoutAudit) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoLockoutAudit) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.LockoutAudit, error) {
+
+	var results []domain.LockoutAudit
+	err := mongoFind(ctx, r.collection, bson.M{"corporate_id": corporateID}, page, limit, &results)
+
+	return results, err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoNotification struct {
+	template *mongo.Collection
+	log      *mongo.Collection
+}
+
+func (r mongoNotification) FindTemplate(ctx context.Context, corporateID primitive.ObjectID, key string,
+	language string) (domain.MessageTemplate, error) {
+
+	model := domain.MessageTemplate{}
+	query := bson.M{"corporate_id": corporateID, "key": key, "language": language}
+	err := mongoFindOne(ctx, r.template, query, &model)
+
+	return model, err
+}
+
+func (r mongoNotification) FindTemplates(ctx context.Context,
+	corporateID primitive.ObjectID) ([]domain.MessageTemplate, error) {
+
+	var results []domain.MessageTemplate
+	err := mongoFindOrderByID(ctx, r.template, bson.M{"corporate_id": corporateID}, &results)
+
+	return results, err
+}
+
+func (r mongoNotification) SaveTemplate(ctx context.Context, model *domain.MessageTemplate) error {
+	existing, err := r.FindTemplate(ctx, model.CorporateID, model.Key, model.Language)
+	if err == ErrNotFound {
+		return mongoInsert(ctx, r.template, model)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	model.ID = existing.ID
+	return mongoUpdate(ctx, r.template, model)
+}
+
+func (r mongoNotification) SaveLog(ctx context.Context, model *domain.NotificationLog) error {
+	return mongoInsert(ctx, r.log, model)
+}
+
+func (r mongoNotification) FindLogs(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.NotificationLog, error) {
+
+	var results []domain.NotificationLog
+	err := mongoFind(ctx, r.log, bson.M{"corporate_id": corporateID}, page, limit, &results)
+
+	return results, err
+}
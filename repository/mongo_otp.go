@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoOTP struct {
+	collection *mongo.Collection
+}
+
+func (r mongoOTP) Save(ctx context.Context, model *domain.OTP) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoOTP) Update(ctx context.Context, model *domain.OTP) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoOTP) FindByUser(ctx context.Context, userID primitive.ObjectID, purpose string) (domain.OTP, error) {
+	model := domain.OTP{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"user_id": userID, "purpose": purpose}, &model)
+
+	return model, err
+}
+
+func (r mongoOTP) Consume(ctx context.Context, model domain.OTP) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": model.ID, "code_hash": model.CodeHash, "used": false},
+		bson.M{"$set": bson.M{"used": true}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r mongoOTP) ReduceAttempt(ctx context.Context, ID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": ID, "attempt": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"attempt": -1}})
+
+	return err
+}
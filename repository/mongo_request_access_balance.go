@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRequestAccessBalance struct {
+	collection *mongo.Collection
+}
+
+func (r mongoRequestAccessBalance) Save(ctx context.Context, model *domain.RequestAccessBalance) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoRequestAccessBalance) Update(ctx context.Context, model *domain.RequestAccessBalance) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoRequestAccessBalance) FindByID(ctx context.Context,
+	ID primitive.ObjectID) (domain.RequestAccessBalance, error) {
+
+	model := domain.RequestAccessBalance{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoRequestAccessBalance) FindByRequester(ctx context.Context, requesterID primitive.ObjectID,
+	status string, page string, limit string) ([]domain.RequestAccessBalance, error) {
+
+	var results []domain.RequestAccessBalance
+	query := bson.M{"balance_requester._id": requesterID, "status": bson.M{"$regex": status, "$options": "i"}}
+	err := mongoFind(ctx, r.collection, query, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoRequestAccessBalance) FindByOwner(ctx context.Context, ownerID primitive.ObjectID,
+	status string, page string, limit string) ([]domain.RequestAccessBalance, error) {
+
+	var results []domain.RequestAccessBalance
+	query := bson.M{"balance_owner._id": ownerID, "status": bson.M{"$regex": status, "$options": "i"}}
+	err := mongoFind(ctx, r.collection, query, page, limit, &results)
+
+	return results, err
+}
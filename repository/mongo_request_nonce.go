@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRequestNonce struct {
+	collection *mongo.Collection
+}
+
+func (r mongoRequestNonce) Save(ctx context.Context, model *domain.RequestNonce) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoRequestNonce) Count(ctx context.Context, corporateID primitive.ObjectID, requestID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"corporate_id": corporateID, "request_id": requestID})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRole struct {
+	collection *mongo.Collection
+}
+
+func (r mongoRole) Save(ctx context.Context, model *domain.Role) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoRole) Update(ctx context.Context, model *domain.Role) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoRole) FindByName(ctx context.Context, corporateID primitive.ObjectID, name string) (domain.Role, error) {
+	model := domain.Role{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"corporate_id": corporateID, "name": name}, &model)
+
+	return model, err
+}
+
+func (r mongoRole) FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Role, error) {
+	var results []domain.Role
+	err := mongoFindOrderByID(ctx, r.collection, bson.M{"corporate_id": corporateID}, &results)
+
+	return results, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSession struct {
+	session      *mongo.Collection
+	revokedToken *mongo.Collection
+}
+
+func (r mongoSession) Save(ctx context.Context, model *domain.Session) error {
+	return mongoInsert(ctx, r.session, model)
+}
+
+func (r mongoSession) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Session, error) {
+	model := domain.Session{}
+	err := mongoFindOne(ctx, r.session, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoSession) Rotate(ctx context.Context, model *domain.Session, expectedHash string) (bool, error) {
+	result, err := r.session.UpdateOne(ctx,
+		bson.M{"_id": model.ID, "refresh_token_hash": expectedHash, "revoked": false},
+		bson.M{"$set": bson.M{
+			"refresh_token_hash":          model.RefreshTokenHash,
+			"previous_refresh_token_hash": model.PreviousRefreshTokenHash,
+			"token_id":                    model.TokenID,
+			"token_expired_time":          model.TokenExpiredTime,
+			"last_refresh_time":           model.LastRefreshTime,
+		}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r mongoSession) Revoke(ctx context.Context, model *domain.Session) error {
+	return mongoSet(ctx, r.session, model.ID, bson.M{"revoked": true, "revoked_time": model.RevokedTime})
+}
+
+func (r mongoSession) FindActiveByUser(ctx context.Context, userID primitive.ObjectID,
+	now time.Time) ([]domain.Session, error) {
+
+	var results []domain.Session
+	query := bson.M{"user_id": userID, "revoked": false, "expired_time": bson.M{"$gt": now}}
+	err := mongoFindOrderByID(ctx, r.session, query, &results)
+
+	return results, err
+}
+
+// Upsert on the unique token ID, revoking the same token twice is not an error
+func (r mongoSession) SaveRevokedToken(ctx context.Context, model *domain.RevokedToken) error {
+	_, err := r.revokedToken.UpdateOne(ctx, bson.M{"token_id": model.TokenID},
+		bson.M{"$setOnInsert": bson.M{"token_id": model.TokenID, "expired_time": model.ExpiredTime, "time": model.Time}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (r mongoSession) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := r.revokedToken.CountDocuments(ctx, bson.M{"token_id": tokenID})
+
+	return count > 0, err
+}
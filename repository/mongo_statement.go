@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoStatement struct {
+	collection *mongo.Collection
+}
+
+func (r mongoStatement) Save(ctx context.Context, model domain.Statement) error {
+	_, err := r.collection.InsertOne(ctx, model)
+
+	return err
+}
+
+func (r mongoStatement) FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID,
+	page string, limit string) ([]domain.Statement, error) {
+
+	var results []domain.Statement
+	err := mongoFind(ctx, r.collection, bson.M{"balance_id": balanceID}, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoStatement) FindSequenced(ctx context.Context, balanceID primitive.ObjectID) ([]domain.Statement, error) {
+	var results []domain.Statement
+	cursor, err := r.collection.Find(ctx, bson.M{"balance_id": balanceID, "sequence": bson.M{"$gt": 0}},
+		options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &results)
+
+	return results, err
+}
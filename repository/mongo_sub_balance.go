@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSubBalance struct {
+	collection *mongo.Collection
+	balance    *mongo.Collection
+}
+
+func (r mongoSubBalance) Deposit(ctx context.Context, balanceID primitive.ObjectID, index int, amount int,
+	currency string) (domain.SubBalance, error) {
+
+	update := bson.M{"$inc": bson.M{"amount": amount, "version": 1, "statement_sequence": 1}}
+	if currency != "" {
+		update["$setOnInsert"] = bson.M{"currency": domain.NormalizeCurrency(currency)}
+	}
+
+	model := domain.SubBalance{}
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"balance_id": balanceID, "index": index}, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&model)
+
+	return model, err
+}
+
+func (r mongoSubBalance) FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID) ([]domain.SubBalance, error) {
+	var results []domain.SubBalance
+	cursor, err := r.collection.Find(ctx, bson.M{"balance_id": balanceID},
+		options.Find().SetSort(bson.D{{Key: "index", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &results)
+
+	return results, err
+}
+
+func (r mongoSubBalance) SumByBalanceIDs(ctx context.Context,
+	balanceIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+
+	cursor, err := r.collection.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"balance_id": bson.M{"$in": balanceIDs}}},
+		{"$group": bson.M{"_id": "$balance_id", "amount": bson.M{"$sum": "$amount"}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sums []struct {
+		BalanceID primitive.ObjectID `bson:"_id"`
+		Amount    int                `bson:"amount"`
+	}
+
+	err = cursor.All(ctx, &sums)
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[primitive.ObjectID]int{}
+	for _, sum := range sums {
+		results[sum.BalanceID] = sum.Amount
+	}
+
+	return results, nil
+}
+
+func (r mongoSubBalance) FindWithAmount(ctx context.Context, limit int) ([]domain.SubBalance, error) {
+	var results []domain.SubBalance
+	cursor, err := r.collection.Find(ctx, bson.M{"amount": bson.M{"$gt": 0}},
+		options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &results)
+
+	return results, err
+}
+
+func (r mongoSubBalance) Consolidate(ctx context.Context, model domain.SubBalance) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": model.ID, "amount": bson.M{"$gte": model.Amount}},
+		bson.M{"$inc": bson.M{"amount": -model.Amount, "version": 1}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrInsufficientBalance
+	}
+
+	result, err = r.balance.UpdateOne(ctx, bson.M{"_id": model.BalanceID},
+		bson.M{"$inc": bson.M{"amount": model.Amount, "version": 1}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoTransaction struct {
+	collection *mongo.Collection
+}
+
+func (r mongoTransaction) Save(ctx context.Context, model *domain.Transaction) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoTransaction) Update(ctx context.Context, model *domain.Transaction) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoTransaction) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Transaction, error) {
+	model := domain.Transaction{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoTransaction) FindByCode(ctx context.Context, code string, status string) (domain.Transaction, error) {
+	query := bson.M{"transaction_code": code}
+	if status != "" {
+		query["status"] = status
+	}
+
+	model := domain.Transaction{}
+	err := mongoFindOne(ctx, r.collection, query, &model)
+
+	return model, err
+}
+
+func (r mongoTransaction) FindByGatewayReference(ctx context.Context, reference string,
+	status string) (domain.Transaction, error) {
+
+	query := bson.M{"gateway_reference": reference}
+	if status != "" {
+		query["status"] = status
+	}
+
+	model := domain.Transaction{}
+	err := mongoFindOne(ctx, r.collection, query, &model)
+
+	return model, err
+}
+
+func (r mongoTransaction) FindByActor(ctx context.Context, accountNumber string, ownBalance []primitive.ObjectID,
+	page string, limit string) ([]domain.Transaction, error) {
+
+	orQuery := []bson.M{{"from.account_number": accountNumber}, {"to.account_number": accountNumber}}
+	for _, a := range ownBalance {
+		orQuery = append(orQuery, bson.M{"balance_id": a})
+	}
+
+	var results []domain.Transaction
+	err := mongoFind(ctx, r.collection, bson.M{"$or": orQuery}, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoTransaction) FindByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+	page string, limit string) ([]domain.Transaction, error) {
+
+	var results []domain.Transaction
+	err := mongoFind(ctx, r.collection, bson.M{"corporate_id": corporateID, "status": status}, page, limit, &results)
+
+	return results, err
+}
+
+func (r mongoTransaction) CountByDeviceSince(ctx context.Context, deviceID string, since time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"device_id": deviceID, "time": bson.M{"$gte": since}})
+}
+
+func (r mongoTransaction) CountByBalanceSince(ctx context.Context, balanceID primitive.ObjectID,
+	since time.Time) (int64, error) {
+
+	return r.collection.CountDocuments(ctx, bson.M{"from_balance_id": balanceID, "time": bson.M{"$gte": since}})
+}
+
+func (r mongoTransaction) CountRoundAmountSince(ctx context.Context, balanceID primitive.ObjectID, unit int,
+	since time.Time) (int64, error) {
+
+	return r.collection.CountDocuments(ctx, bson.M{
+		"from_balance_id": balanceID,
+		"time":            bson.M{"$gte": since},
+		"sub_amount":      bson.M{"$mod": bson.A{unit, 0}},
+	})
+}
+
+func (r mongoTransaction) CountBeneficiary(ctx context.Context, balanceID primitive.ObjectID,
+	transaction domain.Transaction) (int64, error) {
+
+	query := bson.M{
+		"from_balance_id": balanceID,
+		"status":          domain.COMPLETED_STATUS,
+	}
+
+	if !transaction.ToBalanceID.IsZero() {
+		query["to_balance_id"] = transaction.ToBalanceID
+	} else {
+		query["to.institution_code"] = transaction.To.InstitutionCode
+		query["to.account_number"] = transaction.To.AccountNumber
+	}
+
+	return r.collection.CountDocuments(ctx, query)
+}
+
+func (r mongoTransaction) SumOutflowSince(ctx context.Context, balanceIDs []primitive.ObjectID, types []string,
+	since time.Time) (int, error) {
+
+	if len(balanceIDs) == 0 {
+		return 0, nil
+	}
+
+	others, deducts := splitDeductType(types)
+	query := []bson.M{
+		{
+			"$match": bson.M{
+				"time":   bson.M{"$gte": since},
+				"status": bson.M{"$in": outflowStatuses},
+				"$or": bson.A{
+					bson.M{"type": bson.M{"$in": others}, "from_balance_id": bson.M{"$in": balanceIDs}},
+					bson.M{"type": bson.M{"$in": deducts}, "to_balance_id": bson.M{"$in": balanceIDs}},
+				},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":   nil,
+				"total": bson.M{"$sum": "$amount"},
+			},
+		},
+	}
+
+	var results []struct {
+		Total int `bson:"total"`
+	}
+	cursor, err := r.collection.Aggregate(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = cursor.All(ctx, &results)
+	if err != nil || len(results) == 0 {
+		return 0, err
+	}
+
+	return results[0].Total, nil
+}
+
+// Held transaction already reserved its fund so it count to the limit
+var outflowStatuses = []string{domain.COMPLETED_STATUS, domain.PENDING_STATUS, domain.HELD_STATUS}
+
+func splitDeductType(types []string) ([]string, []string) {
+	others := []string{}
+	deducts := []string{}
+	for _, a := range types {
+		if a == domain.DEDUCT {
+			deducts = []string{domain.DEDUCT}
+		} else {
+			others = append(others, a)
+		}
+	}
+
+	return others, deducts
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoUser struct {
+	collection *mongo.Collection
+}
+
+func (r mongoUser) Save(ctx context.Context, model *domain.User) error {
+	return mongoInsert(ctx, r.collection, model)
+}
+
+func (r mongoUser) Update(ctx context.Context, model *domain.User) error {
+	return mongoUpdate(ctx, r.collection, model)
+}
+
+func (r mongoUser) FindByID(ctx context.Context, ID primitive.ObjectID) (domain.User, error) {
+	model := domain.User{}
+	err := mongoFindOne(ctx, r.collection, bson.M{"_id": ID}, &model)
+
+	return model, err
+}
+
+func (r mongoUser) FindByPhoneNumber(ctx context.Context, corporateID primitive.ObjectID,
+	phoneNumber string) (domain.User, error) {
+
+	model := domain.User{}
+	query := bson.M{"phone_number": phoneNumber, "corporate_id": corporateID, "pending": false}
+	err := mongoFindOne(ctx, r.collection, query, &model)
+
+	return model, err
+}
+
+func (r mongoUser) FindByEmailOrPhoneNumber(ctx context.Context, corporateID primitive.ObjectID, email string,
+	phoneNumber string) (domain.User, error) {
+
+	model := domain.User{}
+	query := bson.M{
+		"corporate_id": corporateID,
+		"$or":          bson.A{bson.M{"email": email}, bson.M{"phone_number": phoneNumber}},
+	}
+	err := mongoFindOne(ctx, r.collection, query, &model)
+
+	return model, err
+}
+
+func (r mongoUser) UpdateRole(ctx context.Context, model *domain.User) error {
+	return mongoSet(ctx, r.collection, model.ID, bson.M{"role": model.Role, "audit.updated_time": model.Audit.UpdatedTime})
+}
+
+func (r mongoUser) UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"pin": hash})
+}
+
+func (r mongoUser) UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error {
+	return mongoSet(ctx, r.collection, ID, bson.M{"list_balance": balances})
+}
+
+func (r mongoUser) DeleteInactive(ctx context.Context, model *domain.User) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": model.ID, "active": false, "pending": false})
+
+	return err
+}
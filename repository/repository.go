@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Returned by every FindBy when nothing match, same value as mongo so caller
+// checking mongo.ErrNoDocuments keep working
+var ErrNotFound = mongo.ErrNoDocuments
+
+// Returned by save when a unique key is already used
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// Returned by adjust when the amount taken out is more than the balance
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// Every method take the context of the caller, a context returned by
+// Transactor run the method inside that transaction.
+type BalanceRepository interface {
+	Save(ctx context.Context, model *domain.Balance) error
+
+	// Amount and the counters are never written by update, only by Adjust
+	Update(ctx context.Context, model *domain.Balance) error
+
+	// Add amount (negative take out) in one conditional write and take the next
+	// statement sequence, return the balance after. Balance may go below zero
+	// by at most subAmount, the amount still held by its sub balances, further
+	// return ErrInsufficientBalance. Another currency return
+	// domain.ErrCurrencyMismatch and nothing is written.
+	Adjust(ctx context.Context, ID primitive.ObjectID, amount int, subAmount int,
+		currency string) (domain.Balance, error)
+
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Balance, error)
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Balance, error)
+}
+
+// Sub balances of a fee collecting balance, see domain.SubBalance
+type SubBalanceRepository interface {
+	// Add a positive amount to the sub balance, created on its first deposit,
+	// and take its next statement sequence
+	Deposit(ctx context.Context, balanceID primitive.ObjectID, index int, amount int,
+		currency string) (domain.SubBalance, error)
+
+	FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID) ([]domain.SubBalance, error)
+
+	// Amount of the sub balances added per balance, balance without any is absent
+	SumByBalanceIDs(ctx context.Context, balanceIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error)
+
+	// Sub balances holding an amount, at most limit of them
+	FindWithAmount(ctx context.Context, limit int) ([]domain.SubBalance, error)
+
+	// Move model.Amount from the sub balance into its balance without taking a
+	// statement sequence, ctx must be a transaction. Sub balance holding less
+	// return ErrInsufficientBalance.
+	Consolidate(ctx context.Context, model domain.SubBalance) error
+}
+
+type TransactionRepository interface {
+	Save(ctx context.Context, model *domain.Transaction) error
+	Update(ctx context.Context, model *domain.Transaction) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Transaction, error)
+
+	// Empty status match any status
+	FindByCode(ctx context.Context, code string, status string) (domain.Transaction, error)
+	FindByGatewayReference(ctx context.Context, reference string, status string) (domain.Transaction, error)
+
+	FindByActor(ctx context.Context, accountNumber string, ownBalance []primitive.ObjectID,
+		page string, limit string) ([]domain.Transaction, error)
+	FindByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+		page string, limit string) ([]domain.Transaction, error)
+
+	CountByDeviceSince(ctx context.Context, deviceID string, since time.Time) (int64, error)
+	CountByBalanceSince(ctx context.Context, balanceID primitive.ObjectID, since time.Time) (int64, error)
+	CountRoundAmountSince(ctx context.Context, balanceID primitive.ObjectID, unit int, since time.Time) (int64, error)
+
+	// Completed transaction from the balance to the same beneficiary
+	CountBeneficiary(ctx context.Context, balanceID primitive.ObjectID, transaction domain.Transaction) (int64, error)
+
+	// Sum completed and pending amount leaving the balances, deduct record the
+	// debited balance on to_balance_id
+	SumOutflowSince(ctx context.Context, balanceIDs []primitive.ObjectID, types []string, since time.Time) (int, error)
+}
+
+type StatementRepository interface {
+	Save(ctx context.Context, model domain.Statement) error
+	FindByBalanceID(ctx context.Context, balanceID primitive.ObjectID, page string, limit string) ([]domain.Statement, error)
+
+	// Statement with sequence, ordered by sequence
+	FindSequenced(ctx context.Context, balanceID primitive.ObjectID) ([]domain.Statement, error)
+}
+
+type UserRepository interface {
+	Save(ctx context.Context, model *domain.User) error
+	Update(ctx context.Context, model *domain.User) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.User, error)
+
+	// Pending user is not returned
+	FindByPhoneNumber(ctx context.Context, corporateID primitive.ObjectID, phoneNumber string) (domain.User, error)
+
+	// Pending user included
+	FindByEmailOrPhoneNumber(ctx context.Context, corporateID primitive.ObjectID, email string,
+		phoneNumber string) (domain.User, error)
+
+	UpdateRole(ctx context.Context, model *domain.User) error
+	UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error
+	UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error
+
+	// Only delete user neither active nor pending
+	DeleteInactive(ctx context.Context, model *domain.User) error
+}
+
+type CorporateRepository interface {
+	Save(ctx context.Context, model *domain.Corporate) error
+	Update(ctx context.Context, model *domain.Corporate) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Corporate, error)
+
+	UpdatePIN(ctx context.Context, ID primitive.ObjectID, hash string) error
+	UpdateBalances(ctx context.Context, ID primitive.ObjectID, balances []domain.AccessBalance) error
+	UpdateBulkApprovals(ctx context.Context, ID primitive.ObjectID, thresholds []domain.BulkApprovalThreshold) error
+	UpdateMessaging(ctx context.Context, ID primitive.ObjectID, setting domain.MessagingSetting) error
+	UpdateIdentity(ctx context.Context, ID primitive.ObjectID, setting domain.IdentitySetting) error
+}
+
+type BulkRepository interface {
+	SaveInquiry(ctx context.Context, model *domain.BulkInquiry) error
+	UpdateInquiry(ctx context.Context, model *domain.BulkInquiry) error
+	FindInquiryByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkInquiry, error)
+
+	SaveTransfer(ctx context.Context, model *domain.BulkTransfer) error
+	UpdateTransfer(ctx context.Context, model *domain.BulkTransfer) error
+	FindTransferByID(ctx context.Context, ID primitive.ObjectID) (domain.BulkTransfer, error)
+	FindTransfersByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+		page string, limit string) ([]domain.BulkTransfer, error)
+	// Pushed only while the bulk wait for approval and approver has not reviewed it
+	AddApproval(ctx context.Context, ID primitive.ObjectID, approval domain.BulkApproval) (bool, error)
+	// Set only when the bulk is still in status from
+	SetTransferStatus(ctx context.Context, ID primitive.ObjectID, from string, to string) (bool, error)
+
+	SaveApprovalHistory(ctx context.Context, model *domain.BulkApprovalHistory) error
+	FindApprovalHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+		limit string) ([]domain.BulkApprovalHistory, error)
+}
+
+type CallbackRepository interface {
+	Save(ctx context.Context, model *domain.CallbackHistory) error
+}
+
+type DeviceRepository interface {
+	Save(ctx context.Context, model *domain.Device) error
+	Update(ctx context.Context, model *domain.Device) error
+
+	// Trusted device of the user with the device ID
+	FindTrusted(ctx context.Context, userID primitive.ObjectID, deviceID string) (domain.Device, error)
+
+	// Newest first
+	FindTrustedByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.Device, error)
+	CountTrusted(ctx context.Context, userID primitive.ObjectID) (int64, error)
+
+	Revoke(ctx context.Context, model *domain.Device) error
+}
+
+type BiometricChallengeRepository interface {
+	Save(ctx context.Context, model *domain.BiometricChallenge) error
+
+	// Mark used the challenge matching the ID, user, purpose, device and nonce
+	// hash of model, only when it is unused and not expired at now. Return
+	// false when nothing matched.
+	Consume(ctx context.Context, model domain.BiometricChallenge, now time.Time) (bool, error)
+}
+
+type FraudRepository interface {
+	Save(ctx context.Context, model *domain.Fraud) error
+
+	// Fraud decided as review still waiting for reviewer, newest first
+	FindPendingReview(ctx context.Context, corporateID primitive.ObjectID, page string,
+		limit string) ([]domain.Fraud, error)
+
+	// Close every review of the transaction with the reviewer, result and
+	// model.ReviewTime
+	CloseReview(ctx context.Context, transactionCode string, model domain.Fraud) error
+}
+
+type RequestAccessBalanceRepository interface {
+	Save(ctx context.Context, model *domain.RequestAccessBalance) error
+	Update(ctx context.Context, model *domain.RequestAccessBalance) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.RequestAccessBalance, error)
+
+	// Status match case insensitive on part of the status, empty match every request
+	FindByRequester(ctx context.Context, requesterID primitive.ObjectID, status string, page string,
+		limit string) ([]domain.RequestAccessBalance, error)
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID, status string, page string,
+		limit string) ([]domain.RequestAccessBalance, error)
+}
+
+type KYCRepository interface {
+	Save(ctx context.Context, model *domain.KYCCase) error
+	Update(ctx context.Context, model *domain.KYCCase) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.KYCCase, error)
+
+	// Case with one of the status, user has at most one open case
+	FindByUserStatus(ctx context.Context, userID primitive.ObjectID, status []string) (domain.KYCCase, error)
+
+	// Empty status match any status, newest first
+	FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, status string, page string,
+		limit string) ([]domain.KYCCase, error)
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.KYCCase, error)
+}
+
+type LimitRepository interface {
+	Save(ctx context.Context, model *domain.Limit) error
+	Update(ctx context.Context, model *domain.Limit) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Limit, error)
+
+	// Newest first
+	FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Limit, error)
+
+	// Rule of the actor type, any of the tiers and the transaction type, rule
+	// with domain.LIMIT_ANY on a field match any value. Newest first.
+	FindMatch(ctx context.Context, corporateID primitive.ObjectID, actorType string, tiers []string,
+		transactionType string) ([]domain.Limit, error)
+
+	// Write the usage document of the owner, inside a transaction it make
+	// concurrent transactions of the same owner conflict
+	TouchUsage(ctx context.Context, ownerID primitive.ObjectID) error
+}
+
+type LockoutAuditRepository interface {
+	Save(ctx context.Context, model *domain.LockoutAudit) error
+	FindByCorporate(ctx context.Context, corporateID primitive.ObjectID, page string,
+		limit string) ([]domain.LockoutAudit, error)
+}
+
+type NotificationRepository interface {
+	FindTemplate(ctx context.Context, corporateID primitive.ObjectID, key string,
+		language string) (domain.MessageTemplate, error)
+
+	// Newest first
+	FindTemplates(ctx context.Context, corporateID primitive.ObjectID) ([]domain.MessageTemplate, error)
+
+	// Replace the template of the same corporate, key and language
+	SaveTemplate(ctx context.Context, model *domain.MessageTemplate) error
+
+	SaveLog(ctx context.Context, model *domain.NotificationLog) error
+	FindLogs(ctx context.Context, corporateID primitive.ObjectID, page string,
+		limit string) ([]domain.NotificationLog, error)
+}
+
+type OTPRepository interface {
+	Save(ctx context.Context, model *domain.OTP) error
+	Update(ctx context.Context, model *domain.OTP) error
+	FindByUser(ctx context.Context, userID primitive.ObjectID, purpose string) (domain.OTP, error)
+
+	// Mark used only when the code is still unused and the same, return false
+	// when another request used or replaced it first
+	Consume(ctx context.Context, model domain.OTP) (bool, error)
+
+	// Take one attempt while any is left
+	ReduceAttempt(ctx context.Context, ID primitive.ObjectID) error
+}
+
+type RoleRepository interface {
+	Save(ctx context.Context, model *domain.Role) error
+	Update(ctx context.Context, model *domain.Role) error
+	FindByName(ctx context.Context, corporateID primitive.ObjectID, name string) (domain.Role, error)
+
+	// Newest first
+	FindByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Role, error)
+}
+
+type SessionRepository interface {
+	Save(ctx context.Context, model *domain.Session) error
+	FindByID(ctx context.Context, ID primitive.ObjectID) (domain.Session, error)
+
+	// Write the rotated tokens only when the session is not revoked and its
+	// refresh token hash is still expectedHash, return false otherwise
+	Rotate(ctx context.Context, model *domain.Session, expectedHash string) (bool, error)
+
+	// Mark revoked at model.RevokedTime
+	Revoke(ctx context.Context, model *domain.Session) error
+
+	// Session neither revoked nor expired at now, newest first
+	FindActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error)
+
+	// Token already revoked is kept as it is
+	SaveRevokedToken(ctx context.Context, model *domain.RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+type IPAllowlistRepository interface {
+	SaveHistory(ctx context.Context, model *domain.IPAllowlistHistory) error
+	FindHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+		limit string) ([]domain.IPAllowlistHistory, error)
+}
+
+type RequestNonceRepository interface {
+	// RequestID already saved by the corporate return ErrDuplicateKey
+	Save(ctx context.Context, model *domain.RequestNonce) error
+	Count(ctx context.Context, corporateID primitive.ObjectID, requestID string) (int64, error)
+}
+
+// Run fn in a transaction, every repository call made with the given context
+// is committed together or not at all. Transient conflict rerun fn, so fn must
+// be safe to run more than once. Calling it again inside fn join the running
+// transaction.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// Context of ctx outside its transaction, write made with it is kept even
+	// when the transaction abort
+	WithoutTransaction(ctx context.Context) context.Context
+}
+
+type Repositories struct {
+	Balance            BalanceRepository
+	SubBalance         SubBalanceRepository
+	Transaction        TransactionRepository
+	Statement          StatementRepository
+	User               UserRepository
+	Corporate          CorporateRepository
+	Bulk               BulkRepository
+	Callback           CallbackRepository
+	Device             DeviceRepository
+	BiometricChallenge BiometricChallengeRepository
+	Fraud              FraudRepository
+	RequestAccess      RequestAccessBalanceRepository
+	KYC                KYCRepository
+	Limit              LimitRepository
+	LockoutAudit       LockoutAuditRepository
+	Notification       NotificationRepository
+	OTP                OTPRepository
+	Role               RoleRepository
+	Session            SessionRepository
+	IPAllowlist        IPAllowlistRepository
+	RequestNonce       RequestNonceRepository
+	Transactor         Transactor
+}
@@ -1,14 +1,17 @@
 package service
 
 import (
+	"context"
+
 	"github.com/takeme-id/core/domain"
-	"github.com/takeme-id/core/utils/database"
+	"github.com/takeme-id/core/domain/dto"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func BalanceInitialization(id primitive.ObjectID, corporateID primitive.ObjectID, owner domain.ActorObject,
-	name string, currency string, session mongo.SessionContext) (domain.Balance, error) {
+func BalanceInitialization(ctx context.Context, id primitive.ObjectID, corporateID primitive.ObjectID, owner domain.ActorObject,
+	name string, currency string) (domain.Balance, error) {
 
 	model := domain.Balance{
 		ID:          id,
@@ -16,10 +19,10 @@ func BalanceInitialization(id primitive.ObjectID, corporateID primitive.ObjectID
 		Owner:       owner,
 		Name:        name,
 		Amount:      0,
-		Currency:    currency,
+		Currency:    domain.NormalizeCurrency(currency),
 	}
 
-	err := BalanceSaveOne(&model, session)
+	err := BalanceSaveOne(ctx, &model)
 	if err != nil {
 		return model, err
 	}
@@ -27,18 +30,18 @@ func BalanceInitialization(id primitive.ObjectID, corporateID primitive.ObjectID
 	return model, nil
 }
 
-func BalanceCreate(corporateID primitive.ObjectID, owner domain.ActorObject,
-	name string, currency string, session mongo.SessionContext) (domain.Balance, error) {
+func BalanceCreate(ctx context.Context, corporateID primitive.ObjectID, owner domain.ActorObject,
+	name string, currency string) (domain.Balance, error) {
 
 	model := domain.Balance{
 		CorporateID: corporateID,
 		Owner:       owner,
 		Name:        name,
 		Amount:      0,
-		Currency:    currency,
+		Currency:    domain.NormalizeCurrency(currency),
 	}
 
-	err := BalanceSaveOne(&model, session)
+	err := BalanceSaveOne(ctx, &model)
 	if err != nil {
 		return model, err
 	}
@@ -46,8 +49,8 @@ func BalanceCreate(corporateID primitive.ObjectID, owner domain.ActorObject,
 	return model, nil
 }
 
-func BalanceSaveOne(model *domain.Balance, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(model, session)
+func BalanceSaveOne(ctx context.Context, model *domain.Balance) error {
+	err := Repositories().Balance.Save(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -55,8 +58,23 @@ func BalanceSaveOne(model *domain.Balance, session mongo.SessionContext) error {
 	return nil
 }
 
-func BalanceSaveOneNoSession(model *domain.Balance) error {
-	err := database.SaveOne(domain.BALANCE_COLLECTION, model)
+func BalanceByID(ctx context.Context, ID string) (domain.Balance, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Balance.FindByID(ctx, objectID)
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	err = balancesWithSubBalances(ctx, &model)
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	return model, nil
+}
+
+func BalanceUpdate(ctx context.Context, model domain.Balance) error {
+	err := Repositories().Balance.Update(ctx, &model)
 	if err != nil {
 		return err
 	}
@@ -64,10 +82,38 @@ func BalanceSaveOneNoSession(model *domain.Balance) error {
 	return nil
 }
 
-func BalanceByID(ID string, session mongo.SessionContext) (domain.Balance, error) {
-	model := domain.Balance{}
-	cursor := database.SessionFindOneByID(domain.BALANCE_COLLECTION, ID, session)
-	err := cursor.Decode(&model)
+// Add amount to the balance (negative take out) and return the balance after
+// with its sub balances added. The amount guard is checked by the write itself
+// and count collected fee still in the sub balances, they are only read so the
+// money transaction does not contend with fee deposits. Sub balance is read in
+// the same transaction as the write, a concurrent consolidate write the
+// balance too and conflict.
+func BalanceAdjust(ctx context.Context, ID primitive.ObjectID, amount int, currency string) (domain.Balance, error) {
+	var model domain.Balance
+	err := Repositories().Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		sums, err := Repositories().SubBalance.SumByBalanceIDs(ctx, []primitive.ObjectID{ID})
+		if err != nil {
+			return err
+		}
+
+		model, err = Repositories().Balance.Adjust(ctx, ID, amount, sums[ID], currency)
+		if err != nil {
+			return err
+		}
+
+		model.Amount += sums[ID]
+
+		return nil
+	})
+
+	if err == repository.ErrInsufficientBalance {
+		return domain.Balance{}, utils.ErrorBadRequest(utils.InsufficientBalance, "Insufficient balance")
+	}
+
+	if err == domain.ErrCurrencyMismatch {
+		return domain.Balance{}, utils.ErrorBadRequest(utils.CurrencyError, "Statement currency not match with balance")
+	}
+
 	if err != nil {
 		return domain.Balance{}, err
 	}
@@ -75,22 +121,31 @@ func BalanceByID(ID string, session mongo.SessionContext) (domain.Balance, error
 	return model, nil
 }
 
-func BalanceByIDNoSession(ID string) (domain.Balance, error) {
-	model := domain.Balance{}
-	cursor := database.FindOneByID(domain.BALANCE_COLLECTION, ID)
-	err := cursor.Decode(&model)
+// Balances of the DTO with their sub balances, in the order of the access list
+func dtoBalances(ctx context.Context, mainBalanceID primitive.ObjectID,
+	accessBalances []domain.AccessBalance) (domain.Balance, []dto.AccessBalance, error) {
+
+	mainBalance, err := Repositories().Balance.FindByID(ctx, mainBalanceID)
 	if err != nil {
-		return domain.Balance{}, err
+		return domain.Balance{}, nil, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
 
-	return model, nil
-}
+	balances := []*domain.Balance{&mainBalance}
+	listBalance := make([]dto.AccessBalance, len(accessBalances))
+	for i, access := range accessBalances {
+		detail, err := Repositories().Balance.FindByID(ctx, access.BalanceID)
+		if err != nil {
+			return domain.Balance{}, nil, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
+		}
+
+		listBalance[i] = dto.AccessBalance{BalanceID: access.BalanceID, Access: access.Access, Detail: detail}
+		balances = append(balances, &listBalance[i].Detail)
+	}
 
-func BalanceUpdate(model domain.Balance, session mongo.SessionContext) error {
-	err := database.SessionUpdateOne(&model, session)
+	err = balancesWithSubBalances(ctx, balances...)
 	if err != nil {
-		return err
+		return domain.Balance{}, nil, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
 
-	return nil
+	return mainBalance, listBalance, nil
 }
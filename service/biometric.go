@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const BIOMETRIC_CHALLENGE_DEFAULT_EXPIRED = 120 * time.Second
+
+// Issue challenge for the device bound to user, the plain nonce is only
+// available on the returned challenge
+func BiometricChallengeIssue(ctx context.Context, user domain.User, purpose string,
+	deviceID string) (domain.BiometricChallenge, error) {
+	err := ValidateUserDevice(ctx, user, deviceID)
+	if err != nil {
+		return domain.BiometricChallenge{}, err
+	}
+
+	nonce, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return domain.BiometricChallenge{}, err
+	}
+
+	challenge := domain.BiometricChallenge{
+		CorporateID: user.CorporateID,
+		UserID:      user.ID,
+		Purpose:     purpose,
+		DeviceID:    deviceID,
+		NonceHash:   utils.HashToken(nonce),
+		ExpiredAt: time.Now().Add(otpEnvDuration("BIOMETRIC_CHALLENGE_SECOND", time.Second,
+			BIOMETRIC_CHALLENGE_DEFAULT_EXPIRED)),
+	}
+
+	err = Repositories().BiometricChallenge.Save(ctx, &challenge)
+	if err != nil {
+		return domain.BiometricChallenge{}, utils.ErrorInternalServer(utils.InsertFailed, "Save biometric challenge failed")
+	}
+
+	challenge.Nonce = nonce
+
+	return challenge, nil
+}
+
+// Consume challenge before the face is checked, so every face attempt burn
+// its challenge even when the attempt fail
+func BiometricChallengeConsume(ctx context.Context, user domain.User, purpose string, challengeID string,
+	nonce string, deviceID string) error {
+
+	err := ValidateUserDevice(ctx, user, deviceID)
+	if err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(challengeID)
+	if err != nil || nonce == "" {
+		return utils.ErrorBadRequest(utils.InvalidBiometricChallenge, "Invalid challenge")
+	}
+
+	consumed, err := Repositories().BiometricChallenge.Consume(ctx, domain.BiometricChallenge{
+		ID:        objectID,
+		UserID:    user.ID,
+		Purpose:   purpose,
+		DeviceID:  deviceID,
+		NonceHash: utils.HashToken(nonce),
+	}, time.Now())
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update biometric challenge failed")
+	}
+
+	if !consumed {
+		return utils.ErrorBadRequest(utils.InvalidBiometricChallenge, "Challenge invalid, expired or already used")
+	}
+
+	return nil
+}
+
+// Face can only be used from a trusted device. User without any registered
+// device yet can still use the device of the face enrollment.
+func ValidateUserDevice(ctx context.Context, user domain.User, deviceID string) error {
+	if user.DigitalID == "" {
+		return utils.ErrorBadRequest(utils.UpgradeAccountFirst, "Face not enrolled")
+	}
+
+	if deviceID == "" {
+		return utils.ErrorBadRequest(utils.DeviceNotBound, "Device not bound to user")
+	}
+
+	_, found, err := DeviceTrusted(ctx, user.ID, deviceID)
+	if err != nil || found {
+		return err
+	}
+
+	count, err := DeviceTrustedCount(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 || deviceID != user.DeviceID {
+		return utils.ErrorBadRequest(utils.DeviceNotBound, "Device not bound to user")
+	}
+
+	return nil
+}
@@ -1,12 +1,11 @@
 package service
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func CreateBulkInquiry(corporate domain.Corporate, totalBulk int, reference string, banks []domain.Bank,
@@ -16,7 +15,7 @@ func CreateBulkInquiry(corporate domain.Corporate, totalBulk int, reference stri
 		CorporateID: corporate.ID,
 		Reference:   reference,
 		Owner:       actor,
-		Time:        time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:        domain.TimestampNow(),
 		Status:      domain.BULK_PROGRESS_STATUS,
 		TotalList:   totalBulk,
 	}
@@ -44,7 +43,7 @@ func CreateBulkTransfer(corporate domain.Corporate, totalBulk int, reference str
 		BalanceID:   balance.ID,
 		Reference:   reference,
 		Owner:       actor,
-		Time:        time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:        domain.TimestampNow(),
 		Status:      domain.BULK_UNEXECUTED_STATUS,
 		TotalList:   totalBulk,
 	}
@@ -71,13 +70,31 @@ func CreateBulkTransfer(corporate domain.Corporate, totalBulk int, reference str
 		bulk.Amount = subAmount + (corporate.FeeCorporate.TransferBank * totalBulk)
 	}
 
+	bulk.RequiredApprovals = BulkRequiredApprovals(corporate, bulk.Amount)
+	if bulk.RequiredApprovals > 0 {
+		bulk.Status = domain.BULK_PENDING_APPROVAL_STATUS
+	}
+
 	return bulk, nil
 }
 
-func BulkInquiryByID(ID string) (domain.BulkInquiry, error) {
-	model := domain.BulkInquiry{}
-	cursor := database.FindOneByID(domain.BULK_INQUIRY_COLLECTION, ID)
-	err := cursor.Decode(&model)
+// Highest threshold reached by amount decide how many approver needed
+func BulkRequiredApprovals(corporate domain.Corporate, amount int) int {
+	required := 0
+	minimum := -1
+	for _, threshold := range corporate.BulkApprovals {
+		if amount >= threshold.MinimumAmount && threshold.MinimumAmount > minimum {
+			minimum = threshold.MinimumAmount
+			required = threshold.RequiredApprovals
+		}
+	}
+
+	return required
+}
+
+func BulkInquiryByID(ctx context.Context, ID string) (domain.BulkInquiry, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Bulk.FindInquiryByID(ctx, objectID)
 	if err != nil {
 		return domain.BulkInquiry{}, err
 	}
@@ -85,10 +102,9 @@ func BulkInquiryByID(ID string) (domain.BulkInquiry, error) {
 	return model, nil
 }
 
-func BulkTransferByID(ID string) (domain.BulkTransfer, error) {
-	model := domain.BulkTransfer{}
-	cursor := database.FindOneByID(domain.BULK_TRANSFER_COLLECTION, ID)
-	err := cursor.Decode(&model)
+func BulkTransferByID(ctx context.Context, ID string) (domain.BulkTransfer, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Bulk.FindTransferByID(ctx, objectID)
 	if err != nil {
 		return domain.BulkTransfer{}, err
 	}
@@ -96,37 +112,94 @@ func BulkTransferByID(ID string) (domain.BulkTransfer, error) {
 	return model, nil
 }
 
-func SaveBulkInquiry(bulk *domain.BulkInquiry) error {
-	err := database.SaveOne(domain.BULK_INQUIRY_COLLECTION, bulk)
+func SaveBulkInquiry(ctx context.Context, bulk *domain.BulkInquiry) error {
+	err := Repositories().Bulk.SaveInquiry(ctx, bulk)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, err.Error())
+	}
+
+	return nil
+}
+
+func SaveBulkTransfer(ctx context.Context, bulk *domain.BulkTransfer) error {
+	err := Repositories().Bulk.SaveTransfer(ctx, bulk)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.InsertFailed, err.Error())
 	}
 
 	return nil
 }
 
-func SaveBulkTransfer(bulk *domain.BulkTransfer) error {
-	err := database.SaveOne(domain.BULK_TRANSFER_COLLECTION, bulk)
+func BulkInquiryUpdateOne(ctx context.Context, model *domain.BulkInquiry) error {
+	err := Repositories().Bulk.UpdateInquiry(ctx, model)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
 	}
 
 	return nil
 }
 
-func BulkInquiryUpdateOne(model *domain.BulkInquiry) error {
-	err := database.UpdateOne(domain.BULK_INQUIRY_COLLECTION, model)
+func BulkTransferUpdateOne(ctx context.Context, model *domain.BulkTransfer) error {
+	err := Repositories().Bulk.UpdateTransfer(ctx, model)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
 	}
 
 	return nil
 }
 
-func BulkTransferUpdateOne(model *domain.BulkTransfer) error {
-	err := database.UpdateOne(domain.BULK_TRANSFER_COLLECTION, model)
+func BulkTransferAddApproval(ctx context.Context, ID primitive.ObjectID, approval domain.BulkApproval) (bool, error) {
+	added, err := Repositories().Bulk.AddApproval(ctx, ID, approval)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
+	}
+
+	return added, nil
+}
+
+func BulkTransferSetStatus(ctx context.Context, ID primitive.ObjectID, from string, to string) (bool, error) {
+	set, err := Repositories().Bulk.SetTransferStatus(ctx, ID, from, to)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
+	}
+
+	return set, nil
+}
+
+func BulkTransfersByStatus(ctx context.Context, corporateID primitive.ObjectID, status string, page string,
+	limit string) ([]domain.BulkTransfer, error) {
+	bulks, err := Repositories().Bulk.FindTransfersByStatus(ctx, corporateID, status, page, limit)
+	if err != nil {
+		return []domain.BulkTransfer{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return bulks, nil
+}
+
+func BulkApprovalHistorySave(ctx context.Context, model *domain.BulkApprovalHistory) error {
+	err := Repositories().Bulk.SaveApprovalHistory(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save bulk approval history failed")
+	}
+
+	return nil
+}
+
+func BulkApprovalHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.BulkApprovalHistory, error) {
+	results, err := Repositories().Bulk.FindApprovalHistories(ctx, corporateID, page, limit)
+	if err != nil {
+		return []domain.BulkApprovalHistory{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func CorporateUpdateBulkApprovals(ctx context.Context, corporateID primitive.ObjectID,
+	thresholds []domain.BulkApprovalThreshold) error {
+	err := Repositories().Corporate.UpdateBulkApprovals(ctx, corporateID, thresholds)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update bulk approvals failed")
 	}
 
 	return nil
@@ -1,16 +1,16 @@
 package service
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
-	"github.com/takeme-id/core/utils/database"
+	"github.com/takeme-id/core/utils"
 )
 
-func CreateCallbackHistoryRefused(transactionCode string, url string, requestBody string) (domain.CallbackHistory, error) {
+func CreateCallbackHistoryRefused(ctx context.Context, transactionCode string, url string,
+	requestBody string) (domain.CallbackHistory, error) {
 	model := domain.CallbackHistory{
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		URL:             url,
 		RequestBody:     requestBody,
 		TransactionCode: transactionCode,
@@ -18,7 +18,7 @@ func CreateCallbackHistoryRefused(transactionCode string, url string, requestBod
 		ResponseStatus:  "CONNECTION REFUSED",
 	}
 
-	err := CallbackHistorySaveOne(&model)
+	err := CallbackHistorySaveOne(ctx, &model)
 	if err != nil {
 		return domain.CallbackHistory{}, err
 	}
@@ -26,9 +26,10 @@ func CreateCallbackHistoryRefused(transactionCode string, url string, requestBod
 	return domain.CallbackHistory{}, nil
 }
 
-func CreateCallbackHistory(transactionCode string, url string, requestBody string, responseBody string, responseStatus string) (domain.CallbackHistory, error) {
+func CreateCallbackHistory(ctx context.Context, transactionCode string, url string, requestBody string,
+	responseBody string, responseStatus string) (domain.CallbackHistory, error) {
 	model := domain.CallbackHistory{
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		URL:             url,
 		RequestBody:     requestBody,
 		TransactionCode: transactionCode,
@@ -36,7 +37,7 @@ func CreateCallbackHistory(transactionCode string, url string, requestBody strin
 		ResponseStatus:  responseStatus,
 	}
 
-	err := CallbackHistorySaveOne(&model)
+	err := CallbackHistorySaveOne(ctx, &model)
 	if err != nil {
 		return domain.CallbackHistory{}, err
 	}
@@ -44,10 +45,10 @@ func CreateCallbackHistory(transactionCode string, url string, requestBody strin
 	return domain.CallbackHistory{}, nil
 }
 
-func CallbackHistorySaveOne(model *domain.CallbackHistory) error {
-	err := database.SaveOne(domain.CALLBACK_HISTORY_COLLECTION, model)
+func CallbackHistorySaveOne(ctx context.Context, model *domain.CallbackHistory) error {
+	err := Repositories().Callback.Save(ctx, model)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.InsertFailed, err.Error())
 	}
 
 	return nil
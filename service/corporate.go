@@ -7,14 +7,11 @@ import (
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/domain/dto"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func CorporateSave(corporate domain.Corporate, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(&corporate, session)
+func CorporateSave(ctx context.Context, corporate domain.Corporate) error {
+	err := Repositories().Corporate.Save(ctx, &corporate)
 	if err != nil {
 		return err
 	}
@@ -22,21 +19,9 @@ func CorporateSave(corporate domain.Corporate, session mongo.SessionContext) err
 	return nil
 }
 
-func CorporateByIDNoSession(ID string) (domain.Corporate, error) {
-	model := domain.Corporate{}
-	cursor := database.FindOneByID(domain.CORPORATE_COLLECTION, ID)
-	err := cursor.Decode(&model)
-	if err != nil {
-		return domain.Corporate{}, err
-	}
-
-	return model, nil
-}
-
-func CorporateByID(ID string, session mongo.SessionContext) (domain.Corporate, error) {
-	model := domain.Corporate{}
-	cursor := database.SessionFindOneByID(domain.CORPORATE_COLLECTION, ID, session)
-	err := cursor.Decode(&model)
+func CorporateByID(ctx context.Context, ID string) (domain.Corporate, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Corporate.FindByID(ctx, objectID)
 	if err != nil {
 		return domain.Corporate{}, err
 	}
@@ -47,9 +32,7 @@ func CorporateByID(ID string, session mongo.SessionContext) (domain.Corporate, e
 func CorporateByRequest(r *http.Request) (domain.Corporate, error) {
 	corporateID := r.Header.Get("corporate")
 
-	model := domain.Corporate{}
-	cursor := database.FindOneByID(domain.CORPORATE_COLLECTION, corporateID)
-	err := cursor.Decode(&model)
+	model, err := CorporateByID(r.Context(), corporateID)
 	if err != nil {
 		return domain.Corporate{}, utils.ErrorBadRequest(utils.InvalidCorporateKey, "Corporate not found")
 	}
@@ -67,8 +50,8 @@ func CorporateByRequest(r *http.Request) (domain.Corporate, error) {
 	return model, nil
 }
 
-func CorporateUpdateOne(model *domain.Corporate, session mongo.SessionContext) error {
-	err := database.SessionUpdateOne(model, session)
+func CorporateUpdateOne(ctx context.Context, model *domain.Corporate) error {
+	err := Repositories().Corporate.Update(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -76,27 +59,49 @@ func CorporateUpdateOne(model *domain.Corporate, session mongo.SessionContext) e
 	return nil
 }
 
-func CorporateReduceAccessAttempt(corporateID string, session mongo.SessionContext) error {
+// Reduce access attempt and lock corporate for a cooldown when the attempt run
+// out, return true when the corporate get locked
+func CorporateReduceAccessAttempt(ctx context.Context, corporateID string) (bool, error) {
 
-	corporate, err := CorporateByID(corporateID, session)
+	corporate, err := CorporateByID(ctx, corporateID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	err = ValidateCorporateLocked(corporate)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	var locked bool
+	corporate.AccessAttempt, locked = LockoutRegisterFailure(&corporate.Lockout, corporate.AccessAttempt)
+
+	err = CorporateUpdateOne(ctx, &corporate)
+	if err != nil {
+		return false, err
+	}
+
+	if locked {
+		err = LockoutAuditSave(ctx, domain.CreateLockoutAudit(corporate.ID, corporate.ToActorObject(), corporate.ToActorObject(),
+			domain.LOCKOUT_ACTION_LOCK, domain.LOCKOUT_REASON_ACCESS_ATTEMPT, corporate.Lockout.LockedUntil))
+		if err != nil {
+			return false, err
+		}
 	}
 
-	remaining := corporate.AccessAttempt
-	if remaining <= 0 {
-		corporate.AccessAttempt = 0
-		corporate.Active = false
-	} else {
-		corporate.AccessAttempt -= 1
+	return locked, nil
+}
+
+// Clear lock, corporate locked before Lockout exist is activated back
+func CorporateUnlock(ctx context.Context, corporate *domain.Corporate) error {
+	if corporate.IsLegacyLocked() {
+		corporate.Active = true
 	}
 
-	err = CorporateUpdateOne(&corporate, session)
+	corporate.AccessAttempt = LockoutThreshold()
+	LockoutClear(&corporate.Lockout, false)
+
+	err := CorporateUpdateOne(ctx, corporate)
 	if err != nil {
 		return err
 	}
@@ -104,16 +109,19 @@ func CorporateReduceAccessAttempt(corporateID string, session mongo.SessionConte
 	return nil
 }
 
-func CorporateSavePIN(corporate *domain.Corporate, pin string, session mongo.SessionContext) error {
+func CorporateSavePIN(ctx context.Context, corporate *domain.Corporate, pin string) error {
 
 	pin, err := utils.RSADecrypt(pin)
 	if err != nil {
 		return utils.ErrorInternalServer(utils.DecryptError, err.Error())
 	}
 
-	corporate.PIN = pin
+	corporate.PIN, err = utils.HashPIN(pin)
+	if err != nil {
+		return err
+	}
 
-	err = CorporateUpdateOne(corporate, session)
+	err = CorporateUpdateOne(ctx, corporate)
 	if err != nil {
 		return err
 	}
@@ -121,10 +129,15 @@ func CorporateSavePIN(corporate *domain.Corporate, pin string, session mongo.Ses
 	return nil
 }
 
-func CorporateChangeNewPIN(corporate *domain.Corporate, newPIN string, session mongo.SessionContext) error {
-	corporate.PIN = newPIN
+func CorporateChangeNewPIN(ctx context.Context, corporate *domain.Corporate, newPIN string) error {
+	hash, err := utils.HashPIN(newPIN)
+	if err != nil {
+		return err
+	}
+
+	corporate.PIN = hash
 
-	err := CorporateUpdateOne(corporate, session)
+	err = CorporateUpdateOne(ctx, corporate)
 	if err != nil {
 		return err
 	}
@@ -132,11 +145,30 @@ func CorporateChangeNewPIN(corporate *domain.Corporate, newPIN string, session m
 	return nil
 }
 
+// Replace stored PIN with the current hash scheme, used after successful PIN entry
+func CorporateUpdatePINHash(ctx context.Context, corporateID primitive.ObjectID, pin string) error {
+	hash, err := utils.HashPIN(pin)
+	if err != nil {
+		return err
+	}
+
+	err = Repositories().Corporate.UpdatePIN(ctx, corporateID, hash)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
+	}
+
+	return nil
+}
+
 func ValidateCorporateLocked(corporate domain.Corporate) error {
-	if corporate.Active == false {
+	if corporate.Lockout.IsActive() || corporate.IsLegacyLocked() {
 		return utils.ErrorBadRequest(utils.CorporateLocked, "Corporate Locked")
 	}
 
+	if corporate.Active == false {
+		return utils.ErrorBadRequest(utils.AccountInactive, "Corporate not active")
+	}
+
 	return nil
 }
 
@@ -148,66 +180,24 @@ func ValidateCorporateExist(corporate domain.Corporate) error {
 	return nil
 }
 
-func CorporateDTOByID(corporateID string) (dto.Corporate, error) {
-
-	objectID, err := primitive.ObjectIDFromHex(corporateID)
-
-	query := []bson.M{
-		{"$match": bson.M{"_id": objectID}},
-		{"$unwind": "$list_balance"},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "list_balance.balance_id",
-				"foreignField": "_id",
-				"as":           "list_balance.detail",
-			},
-		},
-		{"$unwind": "$list_balance.detail"},
-		{
-			"$group": bson.M{
-				"_id": "$_id",
-				"list_balance": bson.M{
-					"$push": "$list_balance",
-				},
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "corporate",
-				"localField":   "_id",
-				"foreignField": "_id",
-				"as":           "corporate_detail",
-			},
-		},
-		{"$unwind": "$corporate_detail"},
-		{
-			"$addFields": bson.M{
-				"corporate_detail.list_balance": "$list_balance",
-			},
-		},
-		{
-			"$replaceRoot": bson.M{
-				"newRoot": "$corporate_detail",
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "main_balance",
-				"foreignField": "_id",
-				"as":           "main_balance",
-			},
-		},
-		{"$unwind": "$main_balance"},
-	}
-
-	var result []dto.Corporate
-	cursor, err := database.Aggregate(domain.CORPORATE_COLLECTION, query)
-	cursor.All(context.TODO(), &result)
+func CorporateDTOByID(ctx context.Context, corporateID string) (dto.Corporate, error) {
+	objectID, _ := primitive.ObjectIDFromHex(corporateID)
+
+	corporate, err := Repositories().Corporate.FindByID(ctx, objectID)
 	if err != nil {
 		return dto.Corporate{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
 
-	return result[0], nil
+	mainBalance, listBalance, err := dtoBalances(ctx, corporate.MainBalance, corporate.ListBalance)
+	if err != nil {
+		return dto.Corporate{}, err
+	}
+
+	return dto.Corporate{
+		ID:          corporate.ID,
+		Name:        corporate.Name,
+		PhoneNumber: corporate.PhoneNumber,
+		MainBalance: mainBalance,
+		ListBalance: listBalance,
+	}, nil
 }
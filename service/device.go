@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trusted device of user with the device ID
+func DeviceTrusted(ctx context.Context, userID primitive.ObjectID, deviceID string) (domain.Device, bool, error) {
+	model, err := Repositories().Device.FindTrusted(ctx, userID, deviceID)
+	if err == repository.ErrNotFound {
+		return domain.Device{}, false, nil
+	}
+
+	if err != nil {
+		return domain.Device{}, false, utils.ErrorInternalServer(utils.QueryFailed, "Query device failed")
+	}
+
+	return model, true, nil
+}
+
+func DeviceTrustedCount(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	count, err := Repositories().Device.CountTrusted(ctx, userID)
+	if err != nil {
+		return 0, utils.ErrorInternalServer(utils.QueryFailed, "Query device failed")
+	}
+
+	return count, nil
+}
+
+// Trust device with its key, device already known with another key is replaced
+func DeviceTrust(ctx context.Context, user domain.User, deviceID string, name string, publicKey string) (domain.Device, error) {
+
+	now := domain.TimestampNow()
+	current, found, err := DeviceTrusted(ctx, user.ID, deviceID)
+	if err != nil {
+		return domain.Device{}, err
+	}
+
+	if found {
+		current.Status = domain.DEVICE_STATUS_REVOKED
+		current.RevokedTime = now
+		err = Repositories().Device.Update(ctx, &current)
+		if err != nil {
+			return domain.Device{}, utils.ErrorInternalServer(utils.UpdateFailed, "Replace device failed")
+		}
+	}
+
+	device := domain.Device{
+		CorporateID:  user.CorporateID,
+		UserID:       user.ID,
+		DeviceID:     deviceID,
+		Name:         name,
+		PublicKey:    publicKey,
+		Status:       domain.DEVICE_STATUS_TRUSTED,
+		TrustedTime:  now,
+		LastUsedTime: now,
+	}
+
+	err = Repositories().Device.Save(ctx, &device)
+	if err != nil {
+		return domain.Device{}, utils.ErrorInternalServer(utils.InsertFailed, "Save device failed")
+	}
+
+	return device, nil
+}
+
+func DeviceTouch(ctx context.Context, device *domain.Device) error {
+	device.LastUsedTime = domain.TimestampNow()
+	err := Repositories().Device.Update(ctx, device)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update device failed")
+	}
+
+	return nil
+}
+
+func DevicesByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.Device, error) {
+	results, err := Repositories().Device.FindTrustedByUser(ctx, userID)
+	if err != nil {
+		return []domain.Device{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func DeviceRevoke(ctx context.Context, device *domain.Device) error {
+	device.Status = domain.DEVICE_STATUS_REVOKED
+	device.RevokedTime = domain.TimestampNow()
+
+	err := Repositories().Device.Revoke(ctx, device)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Revoke device failed")
+	}
+
+	return nil
+}
@@ -1,16 +1,42 @@
 package service
 
 import (
+	"context"
+
 	"github.com/takeme-id/core/domain"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func FraudSave(fraud domain.Fraud, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(&fraud, session)
+func FraudSave(ctx context.Context, fraud *domain.Fraud) error {
+	err := Repositories().Fraud.Save(ctx, fraud)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func FraudReviewByCorporate(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.Fraud, error) {
+	results, err := Repositories().Fraud.FindPendingReview(ctx, corporateID, page, limit)
+	if err != nil {
+		return []domain.Fraud{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func FraudCloseReview(ctx context.Context, transactionCode string, reviewer domain.ActorObject,
+	result string) error {
+	err := Repositories().Fraud.CloseReview(ctx, transactionCode, domain.Fraud{
+		Reviewer:     &reviewer,
+		ReviewResult: result,
+		ReviewTime:   domain.TimestampNow(),
+	})
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Close fraud review failed")
+	}
+
+	return nil
+}
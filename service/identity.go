@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func CorporateIdentityVerifier(corporate domain.Corporate) utils.IdentityVerifier {
+	return utils.IdentityVerifierByName(corporate.Identity.Provider)
+}
+
+// Corporate threshold, default threshold for the one not set
+func CorporateIdentityThreshold(corporate domain.Corporate) utils.IdentityThreshold {
+	threshold := utils.DefaultIdentityThreshold()
+	if corporate.Identity.MatchThreshold > 0 {
+		threshold.Match = corporate.Identity.MatchThreshold
+	}
+
+	if corporate.Identity.LivenessThreshold > 0 {
+		threshold.Liveness = corporate.Identity.LivenessThreshold
+	}
+
+	return threshold
+}
+
+// Enroll face of the NIK with the corporate provider
+func IdentityEnroll(corporate domain.Corporate, nik string, faceImage string,
+	deviceID string) (utils.IdentityResult, error) {
+
+	verifier := CorporateIdentityVerifier(corporate)
+	result, err := verifier.Enroll(utils.IdentityRequest{
+		NIK:       nik,
+		DeviceID:  deviceID,
+		FaceImage: faceImage,
+		Component: CorporateBrandName(corporate),
+		Threshold: CorporateIdentityThreshold(corporate),
+	})
+
+	logIdentityResult("Enroll", nik, verifier, result, err)
+
+	return result, err
+}
+
+// Match face against the face enrolled when user upgraded
+func IdentityVerifyUser(corporate domain.Corporate, user domain.User, faceImage string) (utils.IdentityResult, error) {
+	verifier := CorporateIdentityVerifier(corporate)
+	result, err := verifier.Verify(utils.IdentityRequest{
+		NIK:       user.NIK,
+		DigitalID: user.DigitalID,
+		DeviceID:  user.DeviceID,
+		FaceImage: faceImage,
+		Component: CorporateBrandName(corporate),
+		Threshold: CorporateIdentityThreshold(corporate),
+	})
+
+	logIdentityResult("Verify", user.NIK, verifier, result, err)
+
+	return result, err
+}
+
+func CorporateUpdateIdentity(ctx context.Context, corporateID primitive.ObjectID,
+	setting domain.IdentitySetting) error {
+	err := Repositories().Corporate.UpdateIdentity(ctx, corporateID, setting)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update identity setting failed")
+	}
+
+	return nil
+}
+
+func logIdentityResult(action string, nik string, verifier utils.IdentityVerifier, result utils.IdentityResult, err error) {
+	if err != nil {
+		log.Warn(fmt.Sprintf("%v identity of %v via %v failed because %v", action, nik, verifier.Provider(), err.Error()))
+		return
+	}
+
+	log.Info(fmt.Sprintf("%v identity of %v via %v transaction %v match score %v liveness score %v",
+		action, nik, verifier.Provider(), result.TransactionID, result.MatchScore, result.LivenessScore))
+}
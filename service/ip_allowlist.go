@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func IPAllowlistHistorySave(ctx context.Context, model *domain.IPAllowlistHistory) error {
+	err := Repositories().IPAllowlist.SaveHistory(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save allowlist history failed")
+	}
+
+	return nil
+}
+
+func IPAllowlistHistories(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.IPAllowlistHistory, error) {
+	results, err := Repositories().IPAllowlist.FindHistories(ctx, corporateID, page, limit)
+	if err != nil {
+		return []domain.IPAllowlistHistory{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
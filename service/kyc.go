@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func KYCCaseSave(ctx context.Context, model *domain.KYCCase) error {
+	err := Repositories().KYC.Save(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save KYC case failed")
+	}
+
+	return nil
+}
+
+func KYCCaseUpdateOne(ctx context.Context, model *domain.KYCCase) error {
+	err := Repositories().KYC.Update(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update KYC case failed")
+	}
+
+	return nil
+}
+
+func KYCCaseByID(ctx context.Context, ID string) (domain.KYCCase, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().KYC.FindByID(ctx, objectID)
+	if err != nil {
+		return domain.KYCCase{}, utils.ErrorBadRequest(utils.KYCCaseNotFound, "KYC case not found")
+	}
+
+	return model, nil
+}
+
+// Case of the user still waiting for reviewer or user, user has at most one
+func KYCCaseOpenByUser(ctx context.Context, userID primitive.ObjectID) (domain.KYCCase, bool, error) {
+	model, err := Repositories().KYC.FindByUserStatus(ctx, userID, []string{domain.KYC_STATUS_SUBMITTED,
+		domain.KYC_STATUS_IN_REVIEW, domain.KYC_STATUS_MORE_INFO_NEEDED})
+	if err == repository.ErrNotFound {
+		return domain.KYCCase{}, false, nil
+	}
+
+	if err != nil {
+		return domain.KYCCase{}, false, utils.ErrorInternalServer(utils.QueryFailed, "Query KYC case failed")
+	}
+
+	return model, true, nil
+}
+
+func KYCCases(ctx context.Context, corporateID primitive.ObjectID, status string, page string,
+	limit string) ([]domain.KYCCase, error) {
+	results, err := Repositories().KYC.FindByCorporate(ctx, corporateID, status, page, limit)
+	if err != nil {
+		return []domain.KYCCase{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func KYCCasesByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.KYCCase, error) {
+	results, err := Repositories().KYC.FindByUser(ctx, userID)
+	if err != nil {
+		return []domain.KYCCase{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+// Grant tier of an approved case, identity data of the case become user data
+func UserGrantKYCTier(ctx context.Context, user *domain.User, kycCase domain.KYCCase) error {
+	user.Verified = true
+	user.KYCTier = kycCase.Tier
+
+	if kycCase.NIK != "" {
+		user.NIK = kycCase.NIK
+	}
+
+	if kycCase.DigitalID != "" {
+		user.DigitalID = kycCase.DigitalID
+		user.DeviceID = kycCase.DeviceID
+	}
+
+	if kycCase.Tier == domain.KYC_TIER_ORGANIZATION {
+		user.VerifyData.Type = domain.VERIFY_ORGANIZATION_TYPE
+		user.VerifyData.LegalName = kycCase.LegalName
+		user.VerifyData.LegalAddress = kycCase.LegalAddress
+	} else {
+		user.VerifyData.Type = domain.VERIFY_PERSONAL_TYPE
+	}
+
+	user.VerifyData.NIK = kycCase.NIK
+	for _, document := range kycCase.Documents {
+		switch document.Type {
+		case domain.KYC_DOCUMENT_IDENTITY:
+			user.VerifyData.IdentityImage = document.File
+		case domain.KYC_DOCUMENT_AKTA:
+			user.VerifyData.AktaImage = document.File
+		case domain.KYC_DOCUMENT_NPWP:
+			user.VerifyData.NPWPImage = document.File
+		case domain.KYC_DOCUMENT_NIB:
+			user.VerifyData.NIBImage = document.File
+		}
+	}
+
+	err := UserUpdateOne(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func LimitSaveOne(ctx context.Context, model *domain.Limit) error {
+	err := Repositories().Limit.Save(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save limit failed")
+	}
+
+	return nil
+}
+
+func LimitUpdateOne(ctx context.Context, model *domain.Limit) error {
+	err := Repositories().Limit.Update(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update limit failed")
+	}
+
+	return nil
+}
+
+func LimitByID(ctx context.Context, ID string) (domain.Limit, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Limit.FindByID(ctx, objectID)
+	if err != nil {
+		return domain.Limit{}, utils.ErrorBadRequest(utils.LimitNotFound, "Limit not found")
+	}
+
+	return model, nil
+}
+
+func LimitsByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Limit, error) {
+	results, err := Repositories().Limit.FindByCorporate(ctx, corporateID)
+	if err != nil {
+		return []domain.Limit{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+// All limit rule that can apply, wildcard included
+func LimitsMatch(ctx context.Context, corporateID primitive.ObjectID, actorType string, tiers []string,
+	transactionType string) ([]domain.Limit, error) {
+	results, err := Repositories().Limit.FindMatch(ctx, corporateID, actorType, tiers, transactionType)
+	if err != nil {
+		return []domain.Limit{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+// Inside a transaction, concurrent transactions touching the same owner conflict
+func LimitTouchUsage(ctx context.Context, ownerID primitive.ObjectID) error {
+	err := Repositories().Limit.TouchUsage(ctx, ownerID)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update limit usage failed")
+	}
+
+	return nil
+}
+
+func BalanceIDsByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	balances, err := Repositories().Balance.FindByOwner(ctx, ownerID)
+	if err != nil {
+		return []primitive.ObjectID{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	var result []primitive.ObjectID
+	for _, balance := range balances {
+		result = append(result, balance.ID)
+	}
+
+	return result, nil
+}
+
+// Sum amount of outgoing transaction from the balances since given time.
+// Deduct record the debited balance on to_balance_id.
+func TransactionOutflowSince(ctx context.Context, balanceIDs []primitive.ObjectID, types []string,
+	since time.Time) (int, error) {
+	total, err := Repositories().Transaction.SumOutflowSince(ctx, balanceIDs, types, since)
+	if err != nil {
+		return 0, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
+	}
+
+	return total, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lock duration is LOCKOUT_BASE_MINUTE doubled for every previous lock and
+// capped on LOCKOUT_MAX_MINUTE, threshold is SECURITY_ATTEMPT failed attempt
+const (
+	LOCKOUT_DEFAULT_THRESHOLD   = 3
+	LOCKOUT_DEFAULT_BASE_MINUTE = 5
+	LOCKOUT_DEFAULT_MAX_MINUTE  = 24 * 60
+)
+
+func LockoutThreshold() int {
+	attempt, err := strconv.Atoi(os.Getenv("SECURITY_ATTEMPT"))
+	if err != nil || attempt <= 0 {
+		attempt = LOCKOUT_DEFAULT_THRESHOLD
+	}
+
+	return attempt
+}
+
+func LockoutDuration(lockCount int) time.Duration {
+	base := lockoutEnvMinute("LOCKOUT_BASE_MINUTE", LOCKOUT_DEFAULT_BASE_MINUTE)
+	maximum := lockoutEnvMinute("LOCKOUT_MAX_MINUTE", LOCKOUT_DEFAULT_MAX_MINUTE)
+
+	duration := base
+	for i := 1; i < lockCount && duration < maximum; i++ {
+		duration *= 2
+	}
+
+	if duration > maximum {
+		duration = maximum
+	}
+
+	return duration
+}
+
+// Register failed attempt on remaining access attempt, return the new remaining
+// attempt and true when it lock the account
+func LockoutRegisterFailure(lockout *domain.Lockout, remaining int) (int, bool) {
+	// Previous lock already expired, start a new round
+	if lockout.Locked && !lockout.IsActive() {
+		lockout.Locked = false
+		lockout.LockedUntil = domain.Timestamp{}
+		remaining = LockoutThreshold()
+	}
+
+	remaining -= 1
+	if remaining > 0 {
+		return remaining, false
+	}
+
+	LockoutLock(lockout)
+	return 0, true
+}
+
+func LockoutLock(lockout *domain.Lockout) {
+	lockout.LockCount += 1
+	lockout.Locked = true
+	lockout.LockedUntil = domain.NewTimestamp(time.Now().Add(LockoutDuration(lockout.LockCount)))
+}
+
+// Clear lock after unlock, lock count is kept so the next lock is longer
+// unless reset after a successful authentication
+func LockoutClear(lockout *domain.Lockout, resetCount bool) {
+	lockout.Locked = false
+	lockout.LockedUntil = domain.Timestamp{}
+	if resetCount {
+		lockout.LockCount = 0
+	}
+}
+
+func LockoutAuditSave(ctx context.Context, model *domain.LockoutAudit) error {
+	err := Repositories().LockoutAudit.Save(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save lockout audit failed")
+	}
+
+	return nil
+}
+
+func LockoutAudits(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.LockoutAudit, error) {
+	results, err := Repositories().LockoutAudit.FindByCorporate(ctx, corporateID, page, limit)
+	if err != nil {
+		return []domain.LockoutAudit{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func lockoutEnvMinute(key string, fallback int) time.Duration {
+	minute, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || minute <= 0 {
+		minute = fallback
+	}
+
+	return time.Duration(minute) * time.Minute
+}
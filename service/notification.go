@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Render template of the corporate and send it through the corporate providers,
+// every provider attempt is logged. Preferred channel is tried first.
+func Notify(ctx context.Context, corporate domain.Corporate, userID primitive.ObjectID, to string, channel string,
+	key string, params map[string]string) error {
+
+	language := CorporateLanguage(corporate)
+	text, err := MessageTemplateRender(ctx, corporate, key, language, params)
+	if err != nil {
+		return err
+	}
+
+	notification := utils.Notification{
+		To:   to,
+		Code: params[domain.TEMPLATE_CODE_PLACEHOLDER],
+		Text: text,
+	}
+
+	notifiers := utils.Notifiers(channel, corporate.Messaging.Providers)
+	if len(notifiers) == 0 {
+		return utils.ErrorInternalServer(utils.NotificationFailed, "No messaging provider configured")
+	}
+
+	for _, notifier := range notifiers {
+		err = notifier.Notify(notification)
+
+		entry := domain.NotificationLog{
+			CorporateID: corporate.ID,
+			UserID:      userID,
+			Key:         key,
+			Language:    language,
+			Provider:    notifier.Channel(),
+			Destination: to,
+			Status:      domain.NOTIFICATION_STATUS_SENT,
+			Time:        domain.TimestampNow(),
+		}
+
+		if err != nil {
+			entry.Status = domain.NOTIFICATION_STATUS_FAILED
+			entry.Error = err.Error()
+		}
+
+		logErr := NotificationLogSave(ctx, &entry)
+		if logErr != nil {
+			log.Error(fmt.Sprintf("Save notification log failed because %v", logErr.Error()))
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		log.Warn(fmt.Sprintf("Notify %v via %v failed because %v", to, notifier.Channel(), err.Error()))
+	}
+
+	return utils.ErrorInternalServer(utils.NotificationFailed, "Every messaging provider failed")
+}
+
+// Corporate template for the key and language, default template otherwise
+func MessageTemplateRender(ctx context.Context, corporate domain.Corporate, key string, language string,
+	params map[string]string) (string, error) {
+
+	text := ""
+	template, err := MessageTemplateByKey(ctx, corporate.ID, key, language)
+	if err == nil {
+		text = template.Text
+	} else if defaultText, ok := DefaultMessageTemplate(key, language); ok {
+		text = defaultText
+	} else {
+		return "", utils.ErrorBadRequest(utils.InvalidMessageTemplate, "Message template not found")
+	}
+
+	replacements := []string{domain.TEMPLATE_BRAND_PLACEHOLDER, CorporateBrandName(corporate)}
+	for placeholder, value := range params {
+		replacements = append(replacements, placeholder, value)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(text), nil
+}
+
+func DefaultMessageTemplate(key string, language string) (string, bool) {
+	text, ok := domain.DEFAULT_MESSAGE_TEMPLATES[language][key]
+	if !ok {
+		text, ok = domain.DEFAULT_MESSAGE_TEMPLATES[domain.DEFAULT_LANGUAGE][key]
+	}
+
+	return text, ok
+}
+
+func CorporateBrandName(corporate domain.Corporate) string {
+	if corporate.Messaging.BrandName != "" {
+		return corporate.Messaging.BrandName
+	}
+
+	return corporate.Name
+}
+
+func CorporateLanguage(corporate domain.Corporate) string {
+	if domain.IsLanguage(corporate.Messaging.Language) {
+		return corporate.Messaging.Language
+	}
+
+	return domain.DEFAULT_LANGUAGE
+}
+
+func CorporateUpdateMessaging(ctx context.Context, corporateID primitive.ObjectID,
+	setting domain.MessagingSetting) error {
+	err := Repositories().Corporate.UpdateMessaging(ctx, corporateID, setting)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update messaging setting failed")
+	}
+
+	return nil
+}
+
+func MessageTemplateByKey(ctx context.Context, corporateID primitive.ObjectID, key string,
+	language string) (domain.MessageTemplate, error) {
+	return Repositories().Notification.FindTemplate(ctx, corporateID, key, language)
+}
+
+func MessageTemplates(ctx context.Context, corporateID primitive.ObjectID) ([]domain.MessageTemplate, error) {
+	results, err := Repositories().Notification.FindTemplates(ctx, corporateID)
+	if err != nil {
+		return []domain.MessageTemplate{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+// Replace template of the same key and language
+func MessageTemplateSave(ctx context.Context, model *domain.MessageTemplate) error {
+	err := Repositories().Notification.SaveTemplate(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save message template failed")
+	}
+
+	return nil
+}
+
+func NotificationLogSave(ctx context.Context, model *domain.NotificationLog) error {
+	return Repositories().Notification.SaveLog(ctx, model)
+}
+
+func NotificationLogs(ctx context.Context, corporateID primitive.ObjectID, page string,
+	limit string) ([]domain.NotificationLog, error) {
+	results, err := Repositories().Notification.FindLogs(ctx, corporateID, page, limit)
+	if err != nil {
+		return []domain.NotificationLog{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
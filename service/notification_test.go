@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func useFakeNotifier(t *testing.T) {
+	t.Setenv("NOTIFIER_FAKE", "true")
+	UseRepositories(repository.NewMemory())
+	utils.ResetFakeNotifications()
+	t.Cleanup(func() {
+		UseRepositories(nil)
+		utils.ResetFakeNotifications()
+	})
+}
+
+func otpParams(code string) map[string]string {
+	return map[string]string{
+		domain.TEMPLATE_CODE_PLACEHOLDER:   code,
+		domain.TEMPLATE_MINUTE_PLACEHOLDER: "5",
+	}
+}
+
+func TestNotifyDefaultTemplate(t *testing.T) {
+	useFakeNotifier(t)
+	ctx := context.Background()
+	corporate := domain.Corporate{
+		ID:        primitive.NewObjectID(),
+		Name:      "Takeme",
+		Messaging: domain.MessagingSetting{BrandName: "Acme", Language: domain.LANGUAGE_INDONESIAN},
+	}
+	userID := primitive.NewObjectID()
+
+	err := Notify(ctx, corporate, userID, "628123", utils.NOTIFIER_WA, domain.OTP_PURPOSE_LOGIN, otpParams("123456"))
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	sent := utils.FakeNotifications()
+	want := "Acme: kode masuk Anda 123456, berlaku 5 menit. Jangan berikan kode ini kepada siapa pun."
+	if len(sent) != 1 || sent[0].To != "628123" || sent[0].Code != "123456" || sent[0].Text != want {
+		t.Fatalf("sent = %+v, want one message %q", sent, want)
+	}
+
+	logs, err := NotificationLogs(ctx, corporate.ID, "1", "10")
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("logs = %+v, %v, want one log", logs, err)
+	}
+
+	entry := logs[0]
+	if entry.Provider != utils.NOTIFIER_FAKE || entry.Status != domain.NOTIFICATION_STATUS_SENT ||
+		entry.UserID != userID || entry.Key != domain.OTP_PURPOSE_LOGIN || entry.Language != domain.LANGUAGE_INDONESIAN {
+		t.Errorf("log = %+v", entry)
+	}
+}
+
+func TestNotifyCorporateTemplate(t *testing.T) {
+	useFakeNotifier(t)
+	ctx := context.Background()
+	corporate := domain.Corporate{ID: primitive.NewObjectID(), Name: "Takeme"}
+
+	err := MessageTemplateSave(ctx, &domain.MessageTemplate{
+		CorporateID: corporate.ID,
+		Key:         domain.OTP_PURPOSE_ACTIVATION,
+		Language:    domain.LANGUAGE_ENGLISH,
+		Text:        "Welcome to {brand}, code {code}",
+		Time:        domain.TimestampNow(),
+	})
+	if err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+
+	err = Notify(ctx, corporate, primitive.NewObjectID(), "628123", "", domain.OTP_PURPOSE_ACTIVATION,
+		otpParams("654321"))
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	// Brand fall back to corporate name
+	sent := utils.FakeNotifications()
+	if len(sent) != 1 || sent[0].Text != "Welcome to Takeme, code 654321" {
+		t.Errorf("sent = %+v", sent)
+	}
+
+	// Other corporate still receive the default template
+	other := domain.Corporate{ID: primitive.NewObjectID(), Name: "Other"}
+	text, err := MessageTemplateRender(ctx, other, domain.OTP_PURPOSE_ACTIVATION, domain.LANGUAGE_ENGLISH,
+		otpParams("111111"))
+	want := "Other: your signup code is 111111, valid for 5 minutes. Never share this code."
+	if err != nil || text != want {
+		t.Errorf("render = %q, %v, want %q", text, err, want)
+	}
+}
+
+func TestNotifyUnknownTemplate(t *testing.T) {
+	useFakeNotifier(t)
+	corporate := domain.Corporate{ID: primitive.NewObjectID(), Name: "Takeme"}
+
+	err := Notify(context.Background(), corporate, primitive.NewObjectID(), "628123", "", "unknown", otpParams("1"))
+	customError, ok := err.(utils.CustomError)
+	if !ok || customError.Code != utils.InvalidMessageTemplate {
+		t.Errorf("notify error = %v, want invalid template", err)
+	}
+
+	if sent := utils.FakeNotifications(); len(sent) != 0 {
+		t.Errorf("sent = %+v, want nothing", sent)
+	}
+}
+
+type failingNotifier struct{}
+
+func (self failingNotifier) Channel() string {
+	return utils.NOTIFIER_WA
+}
+
+func (self failingNotifier) Notify(notification utils.Notification) error {
+	return utils.ErrorInternalServer(utils.QontakAPICallFailed, "Provider down")
+}
+
+func TestNotifyFallback(t *testing.T) {
+	t.Setenv("NOTIFIER_FAKE", "")
+	UseRepositories(repository.NewMemory())
+	utils.UseNotifiers(map[string]utils.Notifier{
+		utils.NOTIFIER_WA:   failingNotifier{},
+		utils.NOTIFIER_FAKE: utils.FakeNotifier{},
+	})
+	utils.ResetFakeNotifications()
+	t.Cleanup(func() {
+		UseRepositories(nil)
+		utils.UseNotifiers(nil)
+		utils.ResetFakeNotifications()
+	})
+
+	ctx := context.Background()
+	corporate := domain.Corporate{
+		ID:        primitive.NewObjectID(),
+		Name:      "Takeme",
+		Messaging: domain.MessagingSetting{Providers: []string{utils.NOTIFIER_WA, utils.NOTIFIER_FAKE}},
+	}
+
+	err := Notify(ctx, corporate, primitive.NewObjectID(), "628123", "", domain.OTP_PURPOSE_LOGIN, otpParams("123456"))
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	if sent := utils.FakeNotifications(); len(sent) != 1 || sent[0].To != "628123" {
+		t.Errorf("sent = %+v, want one message", sent)
+	}
+
+	logs, err := NotificationLogs(ctx, corporate.ID, "1", "10")
+	if err != nil || len(logs) != 2 {
+		t.Fatalf("logs = %+v, %v, want two logs", logs, err)
+	}
+
+	statuses := map[string]string{}
+	for _, entry := range logs {
+		statuses[entry.Provider] = entry.Status
+	}
+
+	if statuses[utils.NOTIFIER_WA] != domain.NOTIFICATION_STATUS_FAILED ||
+		statuses[utils.NOTIFIER_FAKE] != domain.NOTIFICATION_STATUS_SENT {
+		t.Errorf("log statuses = %v, want wa failed and fake sent", statuses)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+)
+
+// Default policy, every value can be overridden from env
+const (
+	OTP_DIGITS                = 6
+	OTP_DEFAULT_EXPIRED       = 120 * time.Second
+	OTP_DEFAULT_MAX_ATTEMPT   = 3
+	OTP_DEFAULT_RESEND        = 60 * time.Second
+	OTP_DEFAULT_MAX_SEND      = 5
+	OTP_DEFAULT_SEND_WINDOW   = time.Hour
+	TEMPORARY_PIN_EXPIRED     = 600 * time.Second
+	OTP_ERROR_EXPIRED_MESSAGE = "Code expired, request a new code"
+)
+
+// Generate a new code for user and purpose, the previous code is replaced.
+// Return the plain code to be sent, it is never stored.
+func OTPIssue(ctx context.Context, user domain.User, purpose string) (string, error) {
+	now := time.Now()
+	window := otpEnvDuration("OTP_SEND_WINDOW_MINUTE", time.Minute, OTP_DEFAULT_SEND_WINDOW)
+
+	model, err := Repositories().OTP.FindByUser(ctx, user.ID, purpose)
+	isNew := err != nil
+
+	if isNew {
+		model = domain.OTP{
+			CorporateID: user.CorporateID,
+			UserID:      user.ID,
+			Purpose:     purpose,
+			FirstSentAt: now,
+		}
+	} else {
+		if now.Sub(model.LastSentAt) < otpEnvDuration("OTP_RESEND_SECOND", time.Second, OTP_DEFAULT_RESEND) {
+			return "", utils.ErrorBadRequest(utils.OTPResendThrottled, "Code already sent, wait before requesting a new code")
+		}
+
+		if now.Sub(model.FirstSentAt) > window {
+			model.SentCount = 0
+			model.FirstSentAt = now
+		}
+
+		if model.SentCount >= otpEnvInt("OTP_MAX_SEND", OTP_DEFAULT_MAX_SEND) {
+			return "", utils.ErrorBadRequest(utils.OTPResendThrottled, "Too many code requested, try again later")
+		}
+	}
+
+	code, err := utils.GenerateSecureCode(OTP_DIGITS)
+	if err != nil {
+		return "", utils.ErrorInternalServer(utils.OTPGenerateFailed, "Generate code failed")
+	}
+
+	model.CodeHash, err = utils.HashPIN(code)
+	if err != nil {
+		return "", err
+	}
+
+	model.Destination = user.PhoneNumber
+	model.Attempt = otpEnvInt("OTP_MAX_ATTEMPT", OTP_DEFAULT_MAX_ATTEMPT)
+	model.SentCount += 1
+	model.Used = false
+	model.LastSentAt = now
+	model.ExpiredAt = now.Add(otpExpired(purpose))
+	model.DeleteAt = model.FirstSentAt.Add(window)
+	if model.DeleteAt.Before(model.ExpiredAt) {
+		model.DeleteAt = model.ExpiredAt
+	}
+
+	if isNew {
+		err = Repositories().OTP.Save(ctx, &model)
+	} else {
+		err = Repositories().OTP.Update(ctx, &model)
+	}
+
+	if err != nil {
+		return "", utils.ErrorInternalServer(utils.InsertFailed, "Save OTP failed")
+	}
+
+	return code, nil
+}
+
+// Verify and consume the code. Wrong code is counted outside the transaction
+// so the attempt is kept even when the caller abort.
+func OTPVerify(ctx context.Context, user domain.User, purpose string, code string) error {
+	model, err := Repositories().OTP.FindByUser(ctx, user.ID, purpose)
+	if err != nil || model.IsExpired() || model.Attempt <= 0 {
+		return otpError(purpose, OTP_ERROR_EXPIRED_MESSAGE)
+	}
+
+	valid, _ := utils.VerifyPIN(model.CodeHash, code)
+	if !valid {
+		otpReduceAttempt(Repositories().Transactor.WithoutTransaction(ctx), model)
+		return otpError(purpose, "Invalid code")
+	}
+
+	model.Used = true
+	err = Repositories().OTP.Update(ctx, &model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update OTP failed")
+	}
+
+	return nil
+}
+
+// Verify and consume the code for caller without session. Code is marked used
+// with a conditional update so concurrent request cannot use it twice.
+func OTPConsume(ctx context.Context, user domain.User, purpose string, code string) error {
+	model, err := Repositories().OTP.FindByUser(ctx, user.ID, purpose)
+	if err != nil || model.IsExpired() || model.Attempt <= 0 {
+		return otpError(purpose, OTP_ERROR_EXPIRED_MESSAGE)
+	}
+
+	valid, _ := utils.VerifyPIN(model.CodeHash, code)
+	if !valid {
+		otpReduceAttempt(ctx, model)
+		return otpError(purpose, "Invalid code")
+	}
+
+	consumed, err := Repositories().OTP.Consume(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update OTP failed")
+	}
+
+	if !consumed {
+		return otpError(purpose, OTP_ERROR_EXPIRED_MESSAGE)
+	}
+
+	return nil
+}
+
+// Deliver code with the corporate template, channel is the preferred one and
+// the other corporate providers are used as fallback
+func OTPSend(ctx context.Context, corporate domain.Corporate, user domain.User, channel string,
+	purpose string, code string) {
+	expired := otpExpired(purpose)
+	params := map[string]string{
+		domain.TEMPLATE_CODE_PLACEHOLDER:   code,
+		domain.TEMPLATE_MINUTE_PLACEHOLDER: strconv.Itoa(int((expired + time.Minute - 1) / time.Minute)),
+	}
+
+	err := Notify(ctx, corporate, user.ID, user.PhoneNumber, channel, purpose, params)
+	if err != nil {
+		log.Error(fmt.Sprintf("Send %v code to %v failed because %v", purpose, user.PhoneNumber, err.Error()))
+	}
+}
+
+// Attempt is reduced outside the transaction so it is kept when caller abort
+func otpReduceAttempt(ctx context.Context, model domain.OTP) {
+	err := Repositories().OTP.ReduceAttempt(ctx, model.ID)
+	if err != nil {
+		log.Error(fmt.Sprintf("Reduce OTP attempt failed because %v", err.Error()))
+	}
+}
+
+func otpExpired(purpose string) time.Duration {
+	if purpose == domain.OTP_PURPOSE_TEMPORARY_PIN {
+		return otpEnvDuration("TEMPORARY_PIN_EXPIRED_SECOND", time.Second, TEMPORARY_PIN_EXPIRED)
+	}
+
+	return otpEnvDuration("OTP_EXPIRED_SECOND", time.Second, OTP_DEFAULT_EXPIRED)
+}
+
+// Keep the error code client already handle for every purpose
+func otpError(purpose string, message string) error {
+	switch purpose {
+	case domain.OTP_PURPOSE_ACTIVATION:
+		return utils.ErrorBadRequest(utils.InvalidActivationCode, message)
+	case domain.OTP_PURPOSE_LOGIN:
+		return utils.ErrorBadRequest(utils.InvalidLoginCode, message)
+	case domain.OTP_PURPOSE_UNLOCK:
+		return utils.ErrorBadRequest(utils.InvalidUnlockCode, message)
+	default:
+		return utils.ErrorBadRequest(utils.InvalidCode, message)
+	}
+}
+
+func otpEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func otpEnvDuration(key string, unit time.Duration, fallback time.Duration) time.Duration {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return time.Duration(value) * unit
+}
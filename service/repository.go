@@ -0,0 +1,34 @@
+package service
+
+import (
+	"os"
+	"sync"
+
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils/database"
+)
+
+var (
+	repositoriesMutex sync.Mutex
+	repositories      *repository.Repositories
+)
+
+// Replace the repositories used by service, test use repository.NewMemory
+func UseRepositories(r *repository.Repositories) {
+	repositoriesMutex.Lock()
+	defer repositoriesMutex.Unlock()
+
+	repositories = r
+}
+
+// Repositories in use, mongo on the connected database when none given
+func Repositories() *repository.Repositories {
+	repositoriesMutex.Lock()
+	defer repositoriesMutex.Unlock()
+
+	if repositories == nil {
+		repositories = repository.NewMongo(database.DBClient.Database(os.Getenv("MONGO_DB_NAME")))
+	}
+
+	return repositories
+}
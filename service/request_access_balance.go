@@ -2,29 +2,25 @@ package service
 
 import (
 	"context"
-	"os"
-	"time"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func CreateRAB(corporate domain.Corporate, balance domain.Balance, requester domain.ActorObject, owner domain.ActorObject,
-	access string) (domain.RequestAccessBalance, error) {
+func CreateRAB(ctx context.Context, corporate domain.Corporate, balance domain.Balance,
+	requester domain.ActorObject, owner domain.ActorObject, access string) (domain.RequestAccessBalance, error) {
 	model := domain.RequestAccessBalance{
 		CorporateID:      corporate.ID,
 		BalanceID:        balance.ID,
-		Time:             time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:             domain.TimestampNow(),
 		BalanceRequester: requester,
 		BalanceOwner:     owner,
 		Access:           access,
 		Status:           domain.REQUEST_ACCESS_BALANCE_STATUS_PENDING,
 	}
 
-	err := RABSaveOne(&model)
+	err := RABSaveOne(ctx, &model)
 	if err != nil {
 		return domain.RequestAccessBalance{}, err
 	}
@@ -32,8 +28,8 @@ func CreateRAB(corporate domain.Corporate, balance domain.Balance, requester dom
 	return model, nil
 }
 
-func RABSaveOne(model *domain.RequestAccessBalance) error {
-	err := database.SaveOne(domain.RAB_COLLECTION_NAME, model)
+func RABSaveOne(ctx context.Context, model *domain.RequestAccessBalance) error {
+	err := Repositories().RequestAccess.Save(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -41,10 +37,9 @@ func RABSaveOne(model *domain.RequestAccessBalance) error {
 	return nil
 }
 
-func RABByID(ID string) (domain.RequestAccessBalance, error) {
-	model := domain.RequestAccessBalance{}
-	cursor := database.FindOneByID(domain.RAB_COLLECTION_NAME, ID)
-	err := cursor.Decode(&model)
+func RABByID(ctx context.Context, ID string) (domain.RequestAccessBalance, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().RequestAccess.FindByID(ctx, objectID)
 	if err != nil {
 		return domain.RequestAccessBalance{},
 			utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
@@ -53,8 +48,8 @@ func RABByID(ID string) (domain.RequestAccessBalance, error) {
 	return model, nil
 }
 
-func RABUpdateOne(model *domain.RequestAccessBalance) error {
-	err := database.UpdateOne(domain.RAB_COLLECTION_NAME, model)
+func RABUpdateOne(ctx context.Context, model *domain.RequestAccessBalance) error {
+	err := Repositories().RequestAccess.Update(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -62,14 +57,9 @@ func RABUpdateOne(model *domain.RequestAccessBalance) error {
 	return nil
 }
 
-func RABByRequsterID(ID string, status string) ([]domain.RequestAccessBalance, error) {
+func RABByRequsterID(ctx context.Context, ID string, status string) ([]domain.RequestAccessBalance, error) {
 	objectID, _ := primitive.ObjectIDFromHex(ID)
-	query := bson.M{"balance_requester._id": objectID, "status": bson.M{"$regex": status, "$options": "i"}}
-
-	var models []domain.RequestAccessBalance
-	cursor, err := database.Find(domain.RAB_COLLECTION_NAME, query, "1", "1000")
-	err = cursor.All(context.TODO(), &models)
-
+	models, err := Repositories().RequestAccess.FindByRequester(ctx, objectID, status, "1", "1000")
 	if err != nil {
 		return []domain.RequestAccessBalance{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
 	}
@@ -77,14 +67,9 @@ func RABByRequsterID(ID string, status string) ([]domain.RequestAccessBalance, e
 	return models, nil
 }
 
-func RABByOwnerID(ID string, status string) ([]domain.RequestAccessBalance, error) {
+func RABByOwnerID(ctx context.Context, ID string, status string) ([]domain.RequestAccessBalance, error) {
 	objectID, _ := primitive.ObjectIDFromHex(ID)
-	query := bson.M{"balance_owner._id": objectID, "status": bson.M{"$regex": status, "$options": "i"}}
-
-	var models []domain.RequestAccessBalance
-	cursor, err := database.Find(domain.RAB_COLLECTION_NAME, query, "1", "1000")
-	err = cursor.All(context.TODO(), &models)
-
+	models, err := Repositories().RequestAccess.FindByOwner(ctx, objectID, status, "1", "1000")
 	if err != nil {
 		return []domain.RequestAccessBalance{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
 	}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
+)
+
+// Save requestID, return false when corporate already used it inside the window.
+// The unique index come from migration, when it is missing the requestID is
+// still counted after insert so a replay is rejected instead of accepted.
+func RequestNonceSave(ctx context.Context, model *domain.RequestNonce) (bool, error) {
+	err := Repositories().RequestNonce.Save(ctx, model)
+	if err == repository.ErrDuplicateKey {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.InsertFailed, "Save request nonce failed")
+	}
+
+	count, err := Repositories().RequestNonce.Count(ctx, model.CorporateID, model.RequestID)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.QueryFailed, "Query request nonce failed")
+	}
+
+	if count > 1 {
+		log.Error(fmt.Sprintf("RequestID %v saved %v times, request nonce unique index is missing", model.RequestID, count))
+		return false, nil
+	}
+
+	return true, nil
+}
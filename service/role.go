@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RoleSaveOne(ctx context.Context, model *domain.Role) error {
+	err := Repositories().Role.Save(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save role failed")
+	}
+
+	return nil
+}
+
+func RoleUpdateOne(ctx context.Context, model *domain.Role) error {
+	err := Repositories().Role.Update(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Update role failed")
+	}
+
+	return nil
+}
+
+// Return empty role when corporate does not define the role
+func RoleByName(ctx context.Context, corporateID primitive.ObjectID, name string) (domain.Role, error) {
+	model, err := Repositories().Role.FindByName(ctx, corporateID, name)
+	if err == repository.ErrNotFound {
+		return domain.Role{}, nil
+	}
+
+	if err != nil {
+		return domain.Role{}, utils.ErrorInternalServer(utils.QueryFailed, "Query role failed")
+	}
+
+	return model, nil
+}
+
+// Permission of the corporate role, default role permission when corporate
+// does not define it
+func RolePermissions(ctx context.Context, corporateID primitive.ObjectID, roleName string) ([]string, error) {
+	if roleName == "" {
+		return []string{}, nil
+	}
+
+	role, err := RoleByName(ctx, corporateID, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !role.ID.IsZero() {
+		return role.Permissions, nil
+	}
+
+	permissions, ok := domain.DEFAULT_ROLE_PERMISSIONS[roleName]
+	if !ok {
+		return []string{}, nil
+	}
+
+	return permissions, nil
+}
+
+func RolesByCorporate(ctx context.Context, corporateID primitive.ObjectID) ([]domain.Role, error) {
+	results, err := Repositories().Role.FindByCorporate(ctx, corporateID)
+	if err != nil {
+		return []domain.Role{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func SessionSaveOne(ctx context.Context, model *domain.Session) error {
+	err := Repositories().Session.Save(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save session failed")
+	}
+
+	return nil
+}
+
+func SessionByID(ctx context.Context, ID string) (domain.Session, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Session.FindByID(ctx, objectID)
+	if err != nil {
+		return domain.Session{}, utils.ErrorBadRequest(utils.SessionNotFound, "Session not found")
+	}
+
+	return model, nil
+}
+
+// Rotate only when refresh token hash is still the expected one, so two
+// concurrent refresh with the same token cannot both succeed
+func SessionRotate(ctx context.Context, model *domain.Session, expectedHash string) (bool, error) {
+	rotated, err := Repositories().Session.Rotate(ctx, model, expectedHash)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.UpdateFailed, "Rotate session failed")
+	}
+
+	return rotated, nil
+}
+
+func SessionRevoke(ctx context.Context, model *domain.Session) error {
+	err := Repositories().Session.Revoke(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, "Revoke session failed")
+	}
+
+	return nil
+}
+
+func SessionsActiveByUser(ctx context.Context, userID primitive.ObjectID) ([]domain.Session, error) {
+	results, err := Repositories().Session.FindActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return []domain.Session{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func RevokedTokenSave(ctx context.Context, model *domain.RevokedToken) error {
+	err := Repositories().Session.SaveRevokedToken(ctx, model)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.InsertFailed, "Save revoked token failed")
+	}
+
+	return nil
+}
+
+func IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	revoked, err := Repositories().Session.IsTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.QueryFailed, "Query revoked token failed")
+	}
+
+	return revoked, nil
+}
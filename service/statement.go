@@ -5,71 +5,77 @@ import (
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func WithdrawFeeStatement(balanceID primitive.ObjectID, time string, transactionCode string,
-	amount int) domain.Statement {
+func WithdrawFeeStatement(balanceID primitive.ObjectID, time domain.Timestamp, transactionCode string,
+	amount domain.Money) domain.Statement {
 	return domain.Statement{
 		BalanceID:   balanceID,
 		Time:        time,
 		Description: "Withdraw for fee from " + transactionCode,
 		Reference:   transactionCode,
-		Withdraw:    amount,
+		Withdraw:    amount.Amount,
 		Deposit:     0,
+		Currency:    amount.Currency,
 		Type:        domain.STATEMENT_TYPE_FEE,
 	}
 }
 
-func DepositFeeStatement(balanceID primitive.ObjectID, time string, transactionCode string,
-	amount int) domain.Statement {
+func DepositFeeStatement(balanceID primitive.ObjectID, time domain.Timestamp, transactionCode string,
+	amount domain.Money) domain.Statement {
 	return domain.Statement{
 		BalanceID:   balanceID,
 		Time:        time,
 		Description: "Deposit for fee from " + transactionCode,
 		Reference:   transactionCode,
 		Withdraw:    0,
-		Deposit:     amount,
+		Deposit:     amount.Amount,
+		Currency:    amount.Currency,
 		Type:        domain.STATEMENT_TYPE_FEE,
 	}
 }
 
-func WithdrawTransactionStatement(balanceID primitive.ObjectID, time string, transactionCode string,
-	amount int) domain.Statement {
+// Fee deposited to a fee collecting balance, written to one of its sub balances
+func CollectFeeStatement(balanceID primitive.ObjectID, time domain.Timestamp, transactionCode string,
+	amount domain.Money) domain.Statement {
+	statement := DepositFeeStatement(balanceID, time, transactionCode, amount)
+	statement.SubBalance = SubBalancePick()
+
+	return statement
+}
+
+func WithdrawTransactionStatement(balanceID primitive.ObjectID, time domain.Timestamp, transactionCode string,
+	amount domain.Money) domain.Statement {
 	return domain.Statement{
 		BalanceID:   balanceID,
 		Time:        time,
 		Description: "Withdraw for " + transactionCode,
 		Reference:   transactionCode,
-		Withdraw:    amount,
+		Withdraw:    amount.Amount,
 		Deposit:     0,
+		Currency:    amount.Currency,
 		Type:        domain.STATEMENT_TYPE_TRANSACTION,
 	}
 }
 
-func DepositTransactionStatement(balanceID primitive.ObjectID, time string, transactionCode string,
-	amount int) domain.Statement {
+func DepositTransactionStatement(balanceID primitive.ObjectID, time domain.Timestamp, transactionCode string,
+	amount domain.Money) domain.Statement {
 	return domain.Statement{
 		BalanceID:   balanceID,
 		Time:        time,
 		Description: "Deposit for " + transactionCode,
 		Reference:   transactionCode,
 		Withdraw:    0,
-		Deposit:     amount,
+		Deposit:     amount.Amount,
+		Currency:    amount.Currency,
 		Type:        domain.STATEMENT_TYPE_TRANSACTION,
 	}
 }
 
-func StatementsByBalanceID(balanceID primitive.ObjectID, page string, limit string) ([]domain.Statement, error) {
-	query := bson.M{"balance_id": balanceID}
-
-	var results []domain.Statement
-	cursor, err := database.Find(domain.STATEMENT_COLLECTION_NAME, query, page, limit)
-	err = cursor.All(context.TODO(), &results)
-
+func StatementsByBalanceID(ctx context.Context, balanceID primitive.ObjectID, page string,
+	limit string) ([]domain.Statement, error) {
+	results, err := Repositories().Statement.FindByBalanceID(ctx, balanceID, page, limit)
 	if err != nil {
 		return []domain.Statement{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
 	}
@@ -77,11 +83,20 @@ func StatementsByBalanceID(balanceID primitive.ObjectID, page string, limit stri
 	return results, nil
 }
 
-func StatementSaveOne(model domain.Statement, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(model, session)
+func StatementSaveOne(ctx context.Context, model domain.Statement) error {
+	err := Repositories().Statement.Save(ctx, model)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func StatementsSequenced(ctx context.Context, balanceID primitive.ObjectID) ([]domain.Statement, error) {
+	results, err := Repositories().Statement.FindSequenced(ctx, balanceID)
+	if err != nil {
+		return []domain.Statement{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
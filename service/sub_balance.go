@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Number of sub balances collected fee is spread over, BALANCE_SUB_COUNT
+// override it and 0 turn sub balance off
+const SUB_BALANCE_DEFAULT_COUNT = 8
+
+var subBalanceNext uint32
+
+func SubBalanceCount() int {
+	count, err := strconv.Atoi(os.Getenv("BALANCE_SUB_COUNT"))
+	if err != nil || count < 0 {
+		return SUB_BALANCE_DEFAULT_COUNT
+	}
+
+	return count
+}
+
+// Sub balance for the next deposit in turn, 0 when sub balance is off
+func SubBalancePick() int {
+	count := SubBalanceCount()
+	if count == 0 {
+		return 0
+	}
+
+	return int(atomic.AddUint32(&subBalanceNext, 1)%uint32(count)) + 1
+}
+
+// Deposit to a sub balance of the balance, return the balance with its sub
+// balances added and the sub balance after. The balance itself is only read,
+// after the deposit in the same transaction so the amount include it and not
+// a snapshot taken before.
+func BalanceDepositSub(ctx context.Context, ID primitive.ObjectID, index int, amount int,
+	currency string) (domain.Balance, domain.SubBalance, error) {
+
+	var balance domain.Balance
+	var subBalance domain.SubBalance
+	err := Repositories().Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		subBalance, err = Repositories().SubBalance.Deposit(ctx, ID, index, amount, currency)
+		if err != nil {
+			return err
+		}
+
+		balance, err = Repositories().Balance.FindByID(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		// Returning the error abort the deposit
+		if !domain.IsSameCurrency(balance.Currency, currency) {
+			return utils.ErrorBadRequest(utils.CurrencyError, "Statement currency not match with balance")
+		}
+
+		return balancesWithSubBalances(ctx, &balance)
+	})
+
+	if err != nil {
+		return domain.Balance{}, domain.SubBalance{}, err
+	}
+
+	return balance, subBalance, nil
+}
+
+func SubBalancesByBalanceID(ctx context.Context, balanceID primitive.ObjectID) ([]domain.SubBalance, error) {
+	results, err := Repositories().SubBalance.FindByBalanceID(ctx, balanceID)
+	if err != nil {
+		return []domain.SubBalance{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+func SubBalancesWithAmount(ctx context.Context, limit int) ([]domain.SubBalance, error) {
+	results, err := Repositories().SubBalance.FindWithAmount(ctx, limit)
+	if err != nil {
+		return []domain.SubBalance{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	return results, nil
+}
+
+// Move the sub balance into its balance in its own transaction, or in the
+// running one when ctx already is
+func SubBalanceConsolidate(ctx context.Context, model domain.SubBalance) error {
+	return Repositories().Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		return Repositories().SubBalance.Consolidate(ctx, model)
+	})
+}
+
+// Add the sub balances to the amount of every balance
+func balancesWithSubBalances(ctx context.Context, balances ...*domain.Balance) error {
+	var IDs []primitive.ObjectID
+	for _, balance := range balances {
+		IDs = append(IDs, balance.ID)
+	}
+
+	if len(IDs) == 0 {
+		return nil
+	}
+
+	sums, err := Repositories().SubBalance.SumByBalanceIDs(ctx, IDs)
+	if err != nil {
+		return err
+	}
+
+	for _, balance := range balances {
+		balance.Amount += sums[balance.ID]
+	}
+
+	return nil
+}
@@ -2,17 +2,20 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func TransactionSaveOne(model *domain.Transaction, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(model, session)
+func TransactionSaveOne(ctx context.Context, model *domain.Transaction) error {
+	err := Repositories().Transaction.Save(ctx, model)
+	if err == repository.ErrDuplicateKey {
+		return utils.ErrorBadRequest(utils.DuplicateExternalID, "External ID already used")
+	}
+
 	if err != nil {
 		return err
 	}
@@ -20,124 +23,117 @@ func TransactionSaveOne(model *domain.Transaction, session mongo.SessionContext)
 	return nil
 }
 
-func TransactionPendingByCodeNoSession(code string) (domain.Transaction, error) {
-	var transaction domain.Transaction
-	query := bson.M{"transaction_code": code, "status": "Pending"}
-	cursor := database.FindOne(domain.TRANSACTION_COLLECTION, query)
-	err := cursor.Decode(&transaction)
-	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
-	}
-
-	if transaction.TransactionCode == "" {
-		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
-	}
+func TransactionPendingByReference(ctx context.Context, code string) (domain.Transaction, error) {
+	transaction, err := Repositories().Transaction.FindByGatewayReference(ctx, code, domain.PENDING_STATUS)
 
-	return transaction, nil
+	return transactionFound(transaction, err)
 }
 
-func TransactionPendingByReferenceNoSession(code string) (domain.Transaction, error) {
-	var transaction domain.Transaction
-	query := bson.M{"gateway_reference": code, "status": "Pending"}
-	cursor := database.FindOne(domain.TRANSACTION_COLLECTION, query)
-	err := cursor.Decode(&transaction)
-	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
-	}
+func TransactionByGatewayReference(ctx context.Context, code string) (domain.Transaction, error) {
+	transaction, err := Repositories().Transaction.FindByGatewayReference(ctx, code, "")
 
-	if transaction.TransactionCode == "" {
-		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
-	}
+	return transactionFound(transaction, err)
+}
 
-	return transaction, nil
+func TransactionByCode(ctx context.Context, code string) (domain.Transaction, error) {
+	transaction, err := Repositories().Transaction.FindByCode(ctx, code, "")
+
+	return transactionFound(transaction, err)
 }
 
-func TransactionByGatewayReferenceNoSession(code string) (domain.Transaction, error) {
-	var transaction domain.Transaction
-	query := bson.M{"gateway_reference": code}
-	cursor := database.FindOne(domain.TRANSACTION_COLLECTION, query)
-	err := cursor.Decode(&transaction)
+func TransactionByID(ctx context.Context, ID string) (domain.Transaction, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().Transaction.FindByID(ctx, objectID)
 	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
 
-	if transaction.TransactionCode == "" {
-		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
-	}
+	return model, nil
+}
 
-	return transaction, nil
+func TransactionPendingByCode(ctx context.Context, code string) (domain.Transaction, error) {
+	transaction, err := Repositories().Transaction.FindByCode(ctx, code, domain.PENDING_STATUS)
+
+	return transactionFound(transaction, err)
 }
 
-func TransactionByCodeNoSession(code string) (domain.Transaction, error) {
-	var transaction domain.Transaction
-	query := bson.M{"transaction_code": code}
-	cursor := database.FindOne(domain.TRANSACTION_COLLECTION, query)
-	err := cursor.Decode(&transaction)
+func TransactionUpdateOne(ctx context.Context, model *domain.Transaction) error {
+	err := Repositories().Transaction.Update(ctx, model)
 	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
-	}
-
-	if transaction.TransactionCode == "" {
-		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
+		return err
 	}
 
-	return transaction, nil
+	return nil
 }
 
-func TransactionByID(ID string, session mongo.SessionContext) (domain.Transaction, error) {
-	model := domain.Transaction{}
-	cursor := database.SessionFindOneByID(domain.TRANSACTION_COLLECTION, ID, session)
-	err := cursor.Decode(&model)
+func TransactionsByActor(ctx context.Context, accountNumber string, ownBalance []primitive.ObjectID,
+	page string, limit string) ([]domain.Transaction, error) {
+	transactions, err := Repositories().Transaction.FindByActor(ctx, accountNumber, ownBalance, page, limit)
 	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
+		return []domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
 	}
 
-	return model, nil
+	return transactions, nil
 }
 
-func TransactionPendingByCode(code string, session mongo.SessionContext) (domain.Transaction, error) {
-	var transaction domain.Transaction
-	query := bson.M{"transaction_code": code, "status": "Pending"}
-	cursor := database.SessionFindOne(domain.TRANSACTION_COLLECTION, query, session)
-	err := cursor.Decode(&transaction)
+func TransactionCountByDeviceSince(ctx context.Context, deviceID string, since time.Time) (int64, error) {
+	count, err := Repositories().Transaction.CountByDeviceSince(ctx, deviceID, since)
 	if err != nil {
-		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+		return 0, utils.ErrorInternalServer(utils.QueryFailed, err.Error())
 	}
 
-	if transaction.TransactionCode == "" {
-		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
+	return count, nil
+}
+
+func TransactionCountByBalanceSince(ctx context.Context, balanceID primitive.ObjectID,
+	since time.Time) (int64, error) {
+	count, err := Repositories().Transaction.CountByBalanceSince(ctx, balanceID, since)
+	if err != nil {
+		return 0, utils.ErrorInternalServer(utils.QueryFailed, err.Error())
 	}
 
-	return transaction, nil
+	return count, nil
 }
 
-func TransactionUpdateOne(model *domain.Transaction, session mongo.SessionContext) error {
-	err := database.SessionUpdateOne(model, session)
+// Count transaction from the balance which amount is multiply of unit
+func TransactionCountRoundAmountSince(ctx context.Context, balanceID primitive.ObjectID, unit int,
+	since time.Time) (int64, error) {
+	count, err := Repositories().Transaction.CountRoundAmountSince(ctx, balanceID, unit, since)
 	if err != nil {
-		return err
+		return 0, utils.ErrorInternalServer(utils.QueryFailed, err.Error())
 	}
 
-	return nil
+	return count, nil
 }
 
-func TransactionsByActorNoSession(accountNumber string, ownBalance []primitive.ObjectID, page string, limit string) ([]domain.Transaction, error) {
+// Beneficiary is destination balance for wallet transfer or bank account for bank transfer
+func TransactionIsBeneficiaryKnown(ctx context.Context, balanceID primitive.ObjectID,
+	transaction domain.Transaction) (bool, error) {
+	count, err := Repositories().Transaction.CountBeneficiary(ctx, balanceID, transaction)
+	if err != nil {
+		return false, utils.ErrorInternalServer(utils.QueryFailed, err.Error())
+	}
 
-	query := bson.M{}
-	orQuery := []bson.M{}
-	orQuery = append(orQuery, bson.M{"from.account_number": accountNumber}, bson.M{"to.account_number": accountNumber})
+	return count > 0, nil
+}
 
-	for _, a := range ownBalance {
-		orQuery = append(orQuery, bson.M{"balance_id": a})
+func TransactionsByStatus(ctx context.Context, corporateID primitive.ObjectID, status string,
+	page string, limit string) ([]domain.Transaction, error) {
+	transactions, err := Repositories().Transaction.FindByStatus(ctx, corporateID, status, page, limit)
+	if err != nil {
+		return []domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
 	}
 
-	query["$or"] = orQuery
-
-	var transaction []domain.Transaction
-	cursor, err := database.Find(domain.TRANSACTION_COLLECTION, query, page, limit)
-	err = cursor.All(context.TODO(), &transaction)
+	return transactions, nil
+}
 
+func transactionFound(transaction domain.Transaction, err error) (domain.Transaction, error) {
 	if err != nil {
-		return []domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+		return domain.Transaction{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed")
+	}
+
+	if transaction.TransactionCode == "" {
+		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not found")
 	}
 
 	return transaction, nil
@@ -2,25 +2,17 @@ package service
 
 import (
 	"context"
-	"os"
-	"strconv"
-	"time"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/domain/dto"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func UserCreate(corporate domain.Corporate, email string, phoneNumber string, fullName string,
-	session mongo.SessionContext) (domain.User, error) {
+func UserCreate(ctx context.Context, corporate domain.Corporate, email string, phoneNumber string, fullName string) (domain.User, error) {
 
-	activationCode := utils.GenerateShortCode()
 	verificationCode := utils.GenerateUUID()
-	attempt, _ := strconv.Atoi(os.Getenv("SECURITY_ATTEMPT"))
+	attempt := LockoutThreshold()
 
 	model := domain.User{
 		CorporateID:      corporate.GetDocumentID(),
@@ -28,23 +20,21 @@ func UserCreate(corporate domain.Corporate, email string, phoneNumber string, fu
 		PhoneNumber:      phoneNumber,
 		FullName:         fullName,
 		PIN:              "",
-		LoginCode:        "-",
-		ActivationCode:   activationCode,
 		VerificationCode: verificationCode,
 		Active:           false,
 		Verified:         false,
 		AccessAttempt:    int8(attempt), // Default value
 		LoginAttempt:     int8(attempt),
 		Audit: domain.Audit{
-			CreatedTime: time.Now().Format(os.Getenv("TIME_FORMAT")),
-			UpdatedTime: time.Now().Format(os.Getenv("TIME_FORMAT")),
+			CreatedTime: domain.TimestampNow(),
+			UpdatedTime: domain.TimestampNow(),
 		},
 		Avatar:    "",
 		Pending:   false,
 		FaceAsPIN: false,
 	}
 
-	err := UserSaveOne(&model, session)
+	err := UserSaveOne(ctx, &model)
 	if err != nil {
 		return model, err
 	}
@@ -52,32 +42,28 @@ func UserCreate(corporate domain.Corporate, email string, phoneNumber string, fu
 	return model, nil
 }
 
-func UserCreateUnpending(corporate domain.Corporate, userPending domain.User, email string, phoneNumber string, fullName string,
-	session mongo.SessionContext) (domain.User, error) {
+func UserCreateUnpending(ctx context.Context, corporate domain.Corporate, userPending domain.User, email string, phoneNumber string, fullName string) (domain.User, error) {
 
-	activationCode := utils.GenerateShortCode()
 	verificationCode := utils.GenerateUUID()
-	attempt, _ := strconv.Atoi(os.Getenv("SECURITY_ATTEMPT"))
+	attempt := LockoutThreshold()
 
 	userPending.Email = email
 	userPending.PhoneNumber = phoneNumber
 	userPending.FullName = fullName
-	userPending.ActivationCode = activationCode
 	userPending.VerificationCode = verificationCode
 	userPending.PIN = ""
-	userPending.LoginCode = "-"
 	userPending.Active = false
 	userPending.Verified = false
 	userPending.LoginAttempt = int8(attempt)
 	userPending.AccessAttempt = int8(attempt)
 	userPending.Audit = domain.Audit{
-		CreatedTime: time.Now().Format(os.Getenv("TIME_FORMAT")),
-		UpdatedTime: time.Now().Format(os.Getenv("TIME_FORMAT")),
+		CreatedTime: domain.TimestampNow(),
+		UpdatedTime: domain.TimestampNow(),
 	}
 	userPending.Avatar = ""
 	userPending.FaceAsPIN = false
 
-	err := UserUpdateOne(&userPending, session)
+	err := UserUpdateOne(ctx, &userPending)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -85,13 +71,12 @@ func UserCreateUnpending(corporate domain.Corporate, userPending domain.User, em
 	return userPending, nil
 }
 
-func UserActivate(user *domain.User, session mongo.SessionContext) error {
+func UserActivate(ctx context.Context, user *domain.User) error {
 
 	user.Active = true
-	user.ActivationCode = ""
 	user.Pending = false
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -99,8 +84,8 @@ func UserActivate(user *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserSaveOne(model *domain.User, session mongo.SessionContext) error {
-	err := database.SessionSaveOne(model, session)
+func UserSaveOne(ctx context.Context, model *domain.User) error {
+	err := Repositories().User.Save(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -108,8 +93,8 @@ func UserSaveOne(model *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserUpdateOne(model *domain.User, session mongo.SessionContext) error {
-	err := database.SessionUpdateOne(model, session)
+func UserUpdateOne(ctx context.Context, model *domain.User) error {
+	err := Repositories().User.Update(ctx, model)
 	if err != nil {
 		return err
 	}
@@ -117,30 +102,18 @@ func UserUpdateOne(model *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserUpdateOneNoSession(model *domain.User) error {
-	err := database.UpdateOne(domain.USER_COLLECTION, model)
+func UserUpdateRole(ctx context.Context, user *domain.User) error {
+	err := Repositories().User.UpdateRole(ctx, user)
 	if err != nil {
-		return err
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
 	}
 
 	return nil
 }
 
-func UserByID(ID string, session mongo.SessionContext) (domain.User, error) {
-	model := domain.User{}
-	cursor := database.SessionFindOneByID(domain.USER_COLLECTION, ID, session)
-	err := cursor.Decode(&model)
-	if err != nil {
-		return domain.User{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
-	}
-
-	return model, nil
-}
-
-func UserByIDNoSession(ID string) (domain.User, error) {
-	model := domain.User{}
-	cursor := database.FindOneByID(domain.USER_COLLECTION, ID)
-	err := cursor.Decode(&model)
+func UserByID(ctx context.Context, ID string) (domain.User, error) {
+	objectID, _ := primitive.ObjectIDFromHex(ID)
+	model, err := Repositories().User.FindByID(ctx, objectID)
 	if err != nil {
 		return domain.User{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
@@ -148,11 +121,8 @@ func UserByIDNoSession(ID string) (domain.User, error) {
 	return model, nil
 }
 
-func UserByPhoneNumber(corporateID primitive.ObjectID, phoneNumber string, session mongo.SessionContext) (domain.User, error) {
-	model := domain.User{}
-	query := bson.M{"phone_number": phoneNumber, "corporate_id": corporateID, "pending": false}
-	cursor := database.SessionFindOne(domain.USER_COLLECTION, query, session)
-	cursor.Decode(&model)
+func UserByPhoneNumber(ctx context.Context, corporateID primitive.ObjectID, phoneNumber string) (domain.User, error) {
+	model, _ := Repositories().User.FindByPhoneNumber(ctx, corporateID, phoneNumber)
 
 	if model.PhoneNumber == "" {
 		return domain.User{}, utils.ErrorBadRequest(utils.UserNotFound, "User not found")
@@ -161,31 +131,15 @@ func UserByPhoneNumber(corporateID primitive.ObjectID, phoneNumber string, sessi
 	return model, nil
 }
 
-func UserByPhoneNumberWithoutSession(corporateID primitive.ObjectID, phoneNumber string) (domain.User, error) {
-	model := domain.User{}
-	query := bson.M{"phone_number": phoneNumber, "corporate_id": corporateID, "pending": false}
-	cursor := database.FindOne(domain.USER_COLLECTION, query)
-	cursor.Decode(&model)
-
-	if model.PhoneNumber == "" {
-		return domain.User{}, utils.ErrorBadRequest(utils.UserNotFound, "User not found")
-	}
-
-	return model, nil
+func UserByPhoneNumberWithoutSession(ctx context.Context, corporateID primitive.ObjectID,
+	phoneNumber string) (domain.User, error) {
+	return UserByPhoneNumber(ctx, corporateID, phoneNumber)
 }
 
-func ValidateUserNotRegisteredYet(corporate domain.Corporate, phoneNumber string, email string,
-	session mongo.SessionContext) (bool, domain.User, error) {
+func ValidateUserNotRegisteredYet(ctx context.Context, corporate domain.Corporate, phoneNumber string, email string) (bool, domain.User, error) {
 
 	isPending := false
-	var model domain.User
-
-	query := bson.M{
-		"corporate_id": corporate.GetDocumentID(),
-		"$or":          bson.A{bson.M{"email": email}, bson.M{"phone_number": phoneNumber}},
-	}
-	cursor := database.SessionFindOne(domain.USER_COLLECTION, query, session)
-	cursor.Decode(&model)
+	model, _ := Repositories().User.FindByEmailOrPhoneNumber(ctx, corporate.GetDocumentID(), email, phoneNumber)
 
 	if model.Pending == true {
 		isPending = true
@@ -199,12 +153,11 @@ func ValidateUserNotRegisteredYet(corporate domain.Corporate, phoneNumber string
 	return isPending, model, nil
 }
 
-func UserByIDWithValidation(ID string, validations []func(user domain.User) error) (domain.User, error) {
-	model := domain.User{}
-	cursor := database.FindOneByID(domain.USER_COLLECTION, ID)
-	err := cursor.Decode(&model)
+func UserByIDWithValidation(ctx context.Context, ID string,
+	validations []func(user domain.User) error) (domain.User, error) {
+	model, err := UserByID(ctx, ID)
 	if err != nil {
-		return domain.User{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
+		return domain.User{}, err
 	}
 
 	for _, element := range validations {
@@ -217,39 +170,44 @@ func UserByIDWithValidation(ID string, validations []func(user domain.User) erro
 	return model, nil
 }
 
-func UserReduceAccessAttempt(userID string, session mongo.SessionContext) error {
+// Reduce access attempt and lock user for a cooldown when the attempt run out,
+// return true when the user get locked
+func UserReduceAccessAttempt(ctx context.Context, userID string) (bool, error) {
 
-	user, err := UserByID(userID, session)
+	user, err := UserByID(ctx, userID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	err = ValidateUserLocked(user)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	remaining := user.AccessAttempt
-	if remaining <= 0 {
-		user.AccessAttempt = 0
-		user.Active = false
-	} else {
-		user.AccessAttempt -= 1
-	}
+	remaining, locked := LockoutRegisterFailure(&user.Lockout, int(user.AccessAttempt))
+	user.AccessAttempt = int8(remaining)
 
-	err = UserUpdateOne(&user, session)
+	err = UserUpdateOne(ctx, &user)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	if locked {
+		err = LockoutAuditSave(ctx, domain.CreateLockoutAudit(user.CorporateID, user.ToActorObject(), user.ToActorObject(),
+			domain.LOCKOUT_ACTION_LOCK, domain.LOCKOUT_REASON_ACCESS_ATTEMPT, user.Lockout.LockedUntil))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return locked, nil
 }
 
-func UserGenerateLoginCode(user *domain.User, session mongo.SessionContext) error {
+// Every login code requested reduce login attempt until refreshed by a successful login
+func UserReduceLoginAttempt(ctx context.Context, user *domain.User) error {
 	user.LoginAttempt -= 1
-	user.LoginCode = utils.GenerateShortCode()
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -257,13 +215,14 @@ func UserGenerateLoginCode(user *domain.User, session mongo.SessionContext) erro
 	return nil
 }
 
-func UserRefreshAttempt(user *domain.User, session mongo.SessionContext) error {
-	attempt, _ := strconv.Atoi(os.Getenv("SECURITY_ATTEMPT"))
+// Successful authentication, every attempt and lock history is reset
+func UserRefreshAttempt(ctx context.Context, user *domain.User) error {
+	attempt := LockoutThreshold()
 	user.LoginAttempt = int8(attempt)
 	user.AccessAttempt = int8(attempt)
-	user.LoginCode = "-"
+	LockoutClear(&user.Lockout, true)
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -271,13 +230,21 @@ func UserRefreshAttempt(user *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserLock(user *domain.User, session mongo.SessionContext) error {
-
-	user.Active = false
+// Lock user for a cooldown, login attempt is refilled so user can login again
+// after the lock expired
+func UserLock(ctx context.Context, user *domain.User) error {
+	attempt := LockoutThreshold()
+	LockoutLock(&user.Lockout)
 	user.AccessAttempt = 0
-	user.LoginAttempt = 0
+	user.LoginAttempt = int8(attempt)
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	err = LockoutAuditSave(ctx, domain.CreateLockoutAudit(user.CorporateID, user.ToActorObject(), user.ToActorObject(),
+		domain.LOCKOUT_ACTION_LOCK, domain.LOCKOUT_REASON_LOGIN_ATTEMPT, user.Lockout.LockedUntil))
 	if err != nil {
 		return err
 	}
@@ -285,13 +252,18 @@ func UserLock(user *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserUnlock(user *domain.User, session mongo.SessionContext) error {
-	attempt, _ := strconv.Atoi(os.Getenv("SECURITY_ATTEMPT"))
-	user.Active = true
+// Clear lock, user locked before Lockout exist is activated back
+func UserUnlock(ctx context.Context, user *domain.User) error {
+	attempt := LockoutThreshold()
+	if user.IsLegacyLocked() {
+		user.Active = true
+	}
+
 	user.AccessAttempt = int8(attempt)
 	user.LoginAttempt = int8(attempt)
+	LockoutClear(&user.Lockout, false)
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -299,16 +271,19 @@ func UserUnlock(user *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserSavePIN(user *domain.User, pin string, session mongo.SessionContext) error {
+func UserSavePIN(ctx context.Context, user *domain.User, pin string) error {
 
 	pin, err := utils.RSADecrypt(pin)
 	if err != nil {
 		return utils.ErrorInternalServer(utils.DecryptError, err.Error())
 	}
 
-	user.PIN = pin
+	user.PIN, err = utils.HashPIN(pin)
+	if err != nil {
+		return err
+	}
 
-	err = UserUpdateOne(user, session)
+	err = UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -316,30 +291,33 @@ func UserSavePIN(user *domain.User, pin string, session mongo.SessionContext) er
 	return nil
 }
 
-func UserGenerateChangePINCode(user *domain.User, pin string, session mongo.SessionContext) (string, error) {
+// Keep the new PIN until confirmed with the forgot PIN code
+func UserSaveChangePIN(ctx context.Context, user *domain.User, pin string) error {
 
 	pin, err := utils.RSADecrypt(pin)
 	if err != nil {
-		return "", utils.ErrorInternalServer(utils.DecryptError, err.Error())
+		return utils.ErrorInternalServer(utils.DecryptError, err.Error())
 	}
 
-	user.ChangePIN = pin
-	user.ChangePINCode = utils.GenerateShortCode()
+	user.ChangePIN, err = utils.HashPIN(pin)
+	if err != nil {
+		return err
+	}
 
-	err = UserUpdateOne(user, session)
+	err = UserUpdateOne(ctx, user)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return user.ChangePINCode, nil
+	return nil
 }
 
-func UserChangePIN(user *domain.User, session mongo.SessionContext) error {
+func UserChangePIN(ctx context.Context, user *domain.User) error {
 	user.PIN = user.ChangePIN
 	user.ChangePIN = " "
-	user.ChangePINCode = " "
+	user.PINUpdatedTime = domain.TimestampNow()
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -347,10 +325,16 @@ func UserChangePIN(user *domain.User, session mongo.SessionContext) error {
 	return nil
 }
 
-func UserChangeNewPIN(user *domain.User, newPIN string, session mongo.SessionContext) error {
-	user.PIN = newPIN
+func UserChangeNewPIN(ctx context.Context, user *domain.User, newPIN string) error {
+	hash, err := utils.HashPIN(newPIN)
+	if err != nil {
+		return err
+	}
 
-	err := UserUpdateOne(user, session)
+	user.PIN = hash
+	user.PINUpdatedTime = domain.TimestampNow()
+
+	err = UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -358,10 +342,25 @@ func UserChangeNewPIN(user *domain.User, newPIN string, session mongo.SessionCon
 	return nil
 }
 
-func UserChangeFaceAsPIN(user *domain.User, isFaceAsPIN bool, session mongo.SessionContext) error {
+// Replace stored PIN with the current hash scheme, used after successful PIN entry
+func UserUpdatePINHash(ctx context.Context, userID primitive.ObjectID, pin string) error {
+	hash, err := utils.HashPIN(pin)
+	if err != nil {
+		return err
+	}
+
+	err = Repositories().User.UpdatePIN(ctx, userID, hash)
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
+	}
+
+	return nil
+}
+
+func UserChangeFaceAsPIN(ctx context.Context, user *domain.User, isFaceAsPIN bool) error {
 	user.FaceAsPIN = isFaceAsPIN
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -369,13 +368,13 @@ func UserChangeFaceAsPIN(user *domain.User, isFaceAsPIN bool, session mongo.Sess
 	return nil
 }
 
-func UserAddBankAccount(user *domain.User, bankAccount domain.Bank, session mongo.SessionContext) error {
+func UserAddBankAccount(ctx context.Context, user *domain.User, bankAccount domain.Bank) error {
 	existinglistBank := user.SavedBankAccount
 	existinglistBank = append(existinglistBank, bankAccount)
 
 	user.SavedBankAccount = existinglistBank
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -383,7 +382,7 @@ func UserAddBankAccount(user *domain.User, bankAccount domain.Bank, session mong
 	return nil
 }
 
-func UserRemoveBankAccount(user *domain.User, bankAccount domain.Bank, session mongo.SessionContext) error {
+func UserRemoveBankAccount(ctx context.Context, user *domain.User, bankAccount domain.Bank) error {
 	existListBank := user.SavedBankAccount
 	var newListBank = existListBank
 	for index, bank := range existListBank {
@@ -395,7 +394,7 @@ func UserRemoveBankAccount(user *domain.User, bankAccount domain.Bank, session m
 
 	user.SavedBankAccount = newListBank
 
-	err := UserUpdateOne(user, session)
+	err := UserUpdateOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -403,159 +402,70 @@ func UserRemoveBankAccount(user *domain.User, bankAccount domain.Bank, session m
 	return nil
 }
 
-func UserVerify(user *domain.User, deviceID string, nik string, digitalID string, session mongo.SessionContext) error {
-	user.Verified = true
-	user.DeviceID = deviceID
-	user.NIK = nik
-	// user.ImageUpgrade = payload.Image
-	user.DigitalID = digitalID
+func UserDTOByID(ctx context.Context, userID string) (dto.User, error) {
+	objectID, _ := primitive.ObjectIDFromHex(userID)
 
-	err := UserUpdateOne(user, session)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func UserDTOByID(userID string) (dto.User, error) {
-
-	objectID, err := primitive.ObjectIDFromHex(userID)
-
-	query := []bson.M{
-		{"$match": bson.M{"_id": objectID}},
-		{"$unwind": "$list_balance"},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "list_balance.balance_id",
-				"foreignField": "_id",
-				"as":           "list_balance.detail",
-			},
-		},
-		{"$unwind": "$list_balance.detail"},
-		{
-			"$group": bson.M{
-				"_id": "$_id",
-				"list_balance": bson.M{
-					"$push": "$list_balance",
-				},
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "user",
-				"localField":   "_id",
-				"foreignField": "_id",
-				"as":           "user_detail",
-			},
-		},
-		{"$unwind": "$user_detail"},
-		{
-			"$addFields": bson.M{
-				"user_detail.list_balance": "$list_balance",
-			},
-		},
-		{
-			"$replaceRoot": bson.M{
-				"newRoot": "$user_detail",
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "main_balance",
-				"foreignField": "_id",
-				"as":           "main_balance",
-			},
-		},
-		{"$unwind": "$main_balance"},
-	}
-
-	var result []dto.User
-	cursor, err := database.Aggregate(domain.USER_COLLECTION, query)
-	cursor.All(context.TODO(), &result)
+	user, err := Repositories().User.FindByID(ctx, objectID)
 	if err != nil {
 		return dto.User{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
 	}
 
-	return result[0], nil
+	return userDTO(ctx, user)
 }
 
-func UsersDTOFindByID(usersID []string) ([]dto.User, error) {
-
-	var queryUsers []bson.M
+func UsersDTOFindByID(ctx context.Context, usersID []string) ([]dto.User, error) {
+	var result []dto.User
 	for _, element := range usersID {
 		objectID, _ := primitive.ObjectIDFromHex(element)
-		queryUsers = append(queryUsers, bson.M{"_id": bson.M{"$eq": objectID}})
-	}
 
-	query := []bson.M{
-		{
-			"$match": bson.M{
-				"$or": queryUsers,
-			},
-		},
-		{"$unwind": "$list_balance"},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "list_balance.balance_id",
-				"foreignField": "_id",
-				"as":           "list_balance.detail",
-			},
-		},
-		{"$unwind": "$list_balance.detail"},
-		{
-			"$group": bson.M{
-				"_id": "$_id",
-				"list_balance": bson.M{
-					"$push": "$list_balance",
-				},
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "user",
-				"localField":   "_id",
-				"foreignField": "_id",
-				"as":           "user_detail",
-			},
-		},
-		{"$unwind": "$user_detail"},
-		{
-			"$addFields": bson.M{
-				"user_detail.list_balance": "$list_balance",
-			},
-		},
-		{
-			"$replaceRoot": bson.M{
-				"newRoot": "$user_detail",
-			},
-		},
-		{
-			"$lookup": bson.M{
-				"from":         "balance",
-				"localField":   "main_balance",
-				"foreignField": "_id",
-				"as":           "main_balance",
-			},
-		},
-		{"$unwind": "$main_balance"},
-	}
+		user, err := Repositories().User.FindByID(ctx, objectID)
+		if err != nil {
+			continue
+		}
 
-	var result []dto.User
-	cursor, err := database.Aggregate(domain.USER_COLLECTION, query)
-	cursor.All(context.TODO(), &result)
-	if err != nil {
-		return []dto.User{}, utils.ErrorInternalServer(utils.QueryFailed, "Query failed or cannot decode")
+		model, err := userDTO(ctx, user)
+		if err != nil {
+			return []dto.User{}, err
+		}
+
+		result = append(result, model)
 	}
 
 	return result, nil
 }
 
-func UserDeleteInactive(user *domain.User, session mongo.SessionContext) error {
-	err := database.SessionDeleteInactive(user, session)
+func userDTO(ctx context.Context, user domain.User) (dto.User, error) {
+	mainBalance, listBalance, err := dtoBalances(ctx, user.MainBalance, user.ListBalance)
+	if err != nil {
+		return dto.User{}, err
+	}
+
+	return dto.User{
+		ID:               user.ID,
+		CorporateID:      user.CorporateID,
+		Email:            user.Email,
+		PhoneNumber:      user.PhoneNumber,
+		FullName:         user.FullName,
+		Active:           user.Active,
+		Verified:         user.Verified,
+		MainBalance:      mainBalance,
+		ListBalance:      listBalance,
+		SavedCard:        user.SavedCard,
+		SavedBankAccount: user.SavedBankAccount,
+		UnReadInbox:      user.UnReadInbox,
+		NIK:              user.NIK,
+		Avatar:           user.Avatar,
+		Pending:          user.Pending,
+		DeviceID:         user.DeviceID,
+		FaceAsPIN:        user.FaceAsPIN,
+		Remittance:       user.Remittance,
+		IsRemittance:     user.IsRemittance,
+		IsAgent:          user.IsAgent,
+	}, nil
+}
+
+func UserDeleteInactive(ctx context.Context, user *domain.User) error {
+	err := Repositories().User.DeleteInactive(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -564,10 +474,14 @@ func UserDeleteInactive(user *domain.User, session mongo.SessionContext) error {
 }
 
 func ValidateUserLocked(user domain.User) error {
-	if user.Active == false {
+	if user.Lockout.IsActive() || user.IsLegacyLocked() {
 		return utils.ErrorBadRequest(utils.UserLocked, "User Locked")
 	}
 
+	if user.Active == false {
+		return utils.ErrorBadRequest(utils.AccountInactive, "User not active")
+	}
+
 	return nil
 }
 
@@ -579,15 +493,6 @@ func ValidateUserExist(user domain.User) error {
 	return nil
 }
 
-func ValidateUserLoginCode(user domain.User, loginCode string) error {
-
-	if user.LoginCode != loginCode {
-		return utils.ErrorBadRequest(utils.InvalidLoginCode, "Invalid login code")
-	}
-
-	return nil
-}
-
 func ValidateUserFullname(fullName string) error {
 	if utils.IsContainSpecialCharacter(fullName) {
 		return utils.ErrorBadRequest(utils.InvalidNameFormat, "Fullname error")
@@ -596,15 +501,6 @@ func ValidateUserFullname(fullName string) error {
 	return nil
 }
 
-func ValidateUserActivationCode(user domain.User, activationCode string) error {
-
-	if user.ActivationCode != activationCode {
-		return utils.ErrorBadRequest(utils.InvalidActivationCode, "Invalid activation code")
-	}
-
-	return nil
-}
-
 func ValidateIsUserAlreadyActive(user domain.User) error {
 	if user.Active {
 		return utils.ErrorBadRequest(utils.UserAlreadyActive, "User already active")
@@ -630,31 +526,12 @@ func removeBankAccountByIndex(s []domain.Bank, index int) []domain.Bank {
 	return result
 }
 
-func ValidateUserChangePINCode(user domain.User, changePINCode string) error {
-
-	if user.ChangePINCode != changePINCode {
-		return utils.ErrorBadRequest(utils.InvalidCode, "Invalid code")
-	}
-
-	return nil
-}
-
 func ValidateUserPIN(user domain.User, pin string) error {
 
-	if pin != user.PIN {
+	valid, _ := utils.VerifyPIN(user.PIN, pin)
+	if !valid {
 		return utils.ErrorBadRequest(utils.InvalidPIN, "Invalid Old PIN")
 	}
 
 	return nil
 }
-
-func UserbyIDNoSession(ID string) (domain.User, error) {
-	model := domain.User{}
-	cursor := database.FindOneByID(domain.USER_COLLECTION, ID)
-	err := cursor.Decode(&model)
-	if err != nil {
-		return model, err
-	}
-
-	return model, nil
-}
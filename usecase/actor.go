@@ -1,24 +1,23 @@
 package usecase
 
 import (
+	"context"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
-func ActorByID(actorID string) (domain.ActorAble, error) {
+func ActorByID(ctx context.Context, actorID string) (domain.ActorAble, error) {
 	var result domain.ActorAble
 	var err error
 
-	result, err = service.UserByIDNoSession(actorID)
+	result, err = service.UserByID(ctx, actorID)
 	if err != nil {
 		return result, err
 	}
 
 	if result.GetActorType() == "" {
-		result, err = service.CorporateByIDNoSession(actorID)
+		result, err = service.CorporateByID(ctx, actorID)
 		if err != nil {
 			return result, err
 		}
@@ -31,20 +30,14 @@ func ActorByID(actorID string) (domain.ActorAble, error) {
 	return result, nil
 }
 
-func ActorAddBalance(actor domain.ActorAble, newAccessBalance domain.AccessBalance) error {
+func ActorAddBalance(ctx context.Context, actor domain.ActorAble, newAccessBalance domain.AccessBalance) error {
 	accessBalance := actor.GetBalances()
 	accessBalance = append(accessBalance, newAccessBalance)
 
-	query := bson.M{"$set": bson.M{"list_balance": accessBalance}}
-	err := database.UpdateQuery(actor.GetActorType(), actor.GetActorID(), query)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return actorUpdateBalances(ctx, actor, accessBalance)
 }
 
-func ActorRemoveBalance(actor domain.ActorAble, balanceID string) error {
+func ActorRemoveBalance(ctx context.Context, actor domain.ActorAble, balanceID string) error {
 	accessBalance := actor.GetBalances()
 
 	var newAccessBalance []domain.AccessBalance
@@ -60,16 +53,10 @@ func ActorRemoveBalance(actor domain.ActorAble, balanceID string) error {
 
 	accessBalance = newAccessBalance
 
-	query := bson.M{"$set": bson.M{"list_balance": accessBalance}}
-	err := database.UpdateQuery(actor.GetActorType(), actor.GetActorID(), query)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return actorUpdateBalances(ctx, actor, accessBalance)
 }
 
-func ActorObjectToActor(actor domain.ActorObject) (domain.ActorAble, error) {
+func ActorObjectToActor(ctx context.Context, actor domain.ActorObject) (domain.ActorAble, error) {
 
 	collection := actor.Type
 	ID := actor.GetActorID()
@@ -77,12 +64,12 @@ func ActorObjectToActor(actor domain.ActorObject) (domain.ActorAble, error) {
 	var err error
 
 	if collection == domain.USER_COLLECTION {
-		result, err = service.UserByIDNoSession(ID.Hex())
+		result, err = service.UserByID(ctx, ID.Hex())
 		if err != nil {
 			return result, err
 		}
 	} else {
-		result, err = service.CorporateByIDNoSession(ID.Hex())
+		result, err = service.CorporateByID(ctx, ID.Hex())
 		if err != nil {
 			return result, err
 		}
@@ -90,3 +77,18 @@ func ActorObjectToActor(actor domain.ActorObject) (domain.ActorAble, error) {
 
 	return result, nil
 }
+
+func actorUpdateBalances(ctx context.Context, actor domain.ActorAble, accessBalance []domain.AccessBalance) error {
+	var err error
+	if actor.GetActorType() == domain.CORPORATE_COLLECTION {
+		err = service.Repositories().Corporate.UpdateBalances(ctx, actor.GetActorID(), accessBalance)
+	} else {
+		err = service.Repositories().User.UpdateBalances(ctx, actor.GetActorID(), accessBalance)
+	}
+
+	if err != nil {
+		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
+	}
+
+	return nil
+}
@@ -7,48 +7,33 @@ import (
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
 	"github.com/takeme-id/core/utils/gateway"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func CreateBalanceUser(user domain.ActorAble, corporate domain.Corporate, balanceName string) (domain.Balance, error) {
+func CreateBalanceUser(ctx context.Context, user domain.ActorAble, corporate domain.Corporate,
+	balanceName string) (domain.Balance, error) {
 	var balance domain.Balance
 
 	if utils.IsContainSpecialCharacter(balanceName) {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidNameFormat, "Name balance error")
 	}
 
-	createBalanceForUser := func(session mongo.SessionContext) error {
+	createBalanceForUser := func(session context.Context) error {
+		var err error
 
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
-		}
-
-		balance, err = service.BalanceCreate(corporate.ID,
+		balance, err = service.BalanceCreate(session, corporate.ID,
 			user.ToActorObject(),
 			balanceName,
-			corporate.Currency,
-			session,
+			corporate.GetCurrency(),
 		)
 
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		user, err := service.UserByID(user.GetActorID().Hex(), session)
+		user, err := service.UserByID(session, user.GetActorID().Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -57,28 +42,21 @@ func CreateBalanceUser(user domain.ActorAble, corporate domain.Corporate, balanc
 			Access:    domain.ACCESS_BALANCE_OWNER,
 		})
 
-		err = service.UserUpdateOne(&user, session)
+		err = service.UserUpdateOne(session, &user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		createVABalance(&balance, user.FullName)
-		err = service.BalanceUpdate(balance, session)
+		createVABalance(ctx, &balance, user.FullName)
+		err = service.BalanceUpdate(session, balance)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, createBalanceForUser)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, createBalanceForUser)
 
 	if err != nil {
 		return balance, utils.ErrorInternalServer(utils.DBStartTransactionFailed,
@@ -88,39 +66,30 @@ func CreateBalanceUser(user domain.ActorAble, corporate domain.Corporate, balanc
 	return balance, nil
 }
 
-func CreateBalanceCorporate(corp domain.ActorAble, corporate domain.Corporate, balanceName string) (domain.Balance, error) {
+func CreateBalanceCorporate(ctx context.Context, corp domain.ActorAble, corporate domain.Corporate,
+	balanceName string) (domain.Balance, error) {
 	var balance domain.Balance
 
 	if utils.IsContainSpecialCharacter(balanceName) {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidNameFormat, "Name balance error")
 	}
 
-	createBalanceForCorporate := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
-		}
+	createBalanceForCorporate := func(session context.Context) error {
+		var err error
 
 		balance, err = service.BalanceCreate(
-			corporate.ID,
+			session, corporate.ID,
 			corp.ToActorObject(),
 			balanceName,
-			corporate.Currency,
-			session,
+			corporate.GetCurrency(),
 		)
 
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		corp, err := service.CorporateByID(corporate.ID.Hex(), session)
+		corp, err := service.CorporateByID(session, corporate.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -129,28 +98,21 @@ func CreateBalanceCorporate(corp domain.ActorAble, corporate domain.Corporate, b
 			Access:    domain.ACCESS_BALANCE_OWNER,
 		})
 
-		err = service.CorporateUpdateOne(&corp, session)
+		err = service.CorporateUpdateOne(session, &corp)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		createVABalance(&balance, corporate.Name)
-		err = service.BalanceUpdate(balance, session)
+		createVABalance(ctx, &balance, corporate.Name)
+		err = service.BalanceUpdate(session, balance)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, createBalanceForCorporate)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, createBalanceForCorporate)
 
 	if err != nil {
 		return balance, utils.ErrorInternalServer(utils.DBStartTransactionFailed,
@@ -160,35 +122,25 @@ func CreateBalanceCorporate(corp domain.ActorAble, corporate domain.Corporate, b
 	return balance, nil
 }
 
-func InitializeBalanceUser(user domain.ActorAble, corporate domain.Corporate, balanceName string) (domain.Balance, error) {
+func InitializeBalanceUser(ctx context.Context, user domain.ActorAble, corporate domain.Corporate,
+	balanceName string) (domain.Balance, error) {
 	var balance domain.Balance
-	createBalanceForUser := func(session mongo.SessionContext) error {
-
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	createBalanceForUser := func(session context.Context) error {
+		var err error
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
-		}
-
-		balance, err = service.BalanceInitialization(user.GetActorID(),
+		balance, err = service.BalanceInitialization(session, user.GetActorID(),
 			corporate.ID,
 			user.ToActorObject(),
 			balanceName,
-			corporate.Currency,
-			session,
+			corporate.GetCurrency(),
 		)
 
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		user, err := service.UserByID(user.GetActorID().Hex(), session)
+		user, err := service.UserByID(session, user.GetActorID().Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -199,28 +151,21 @@ func InitializeBalanceUser(user domain.ActorAble, corporate domain.Corporate, ba
 			Access:    domain.ACCESS_BALANCE_OWNER,
 		})
 
-		err = service.UserUpdateOne(&user, session)
+		err = service.UserUpdateOne(session, &user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		createVABalance(&balance, user.FullName)
-		err = service.BalanceUpdate(balance, session)
+		createVABalance(ctx, &balance, user.FullName)
+		err = service.BalanceUpdate(session, balance)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, createBalanceForUser)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, createBalanceForUser)
 
 	if err != nil {
 		return balance, utils.ErrorInternalServer(utils.DBStartTransactionFailed,
@@ -230,34 +175,25 @@ func InitializeBalanceUser(user domain.ActorAble, corporate domain.Corporate, ba
 	return balance, nil
 }
 
-func InitializeBalanceCorporate(corp domain.ActorAble, corporate domain.Corporate, balanceName string) (domain.Balance, error) {
+func InitializeBalanceCorporate(ctx context.Context, corp domain.ActorAble, corporate domain.Corporate,
+	balanceName string) (domain.Balance, error) {
 	var balance domain.Balance
-	createBalanceForCorporate := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	createBalanceForCorporate := func(session context.Context) error {
+		var err error
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
-		}
-
-		balance, err = service.BalanceInitialization(corp.GetActorID(),
+		balance, err = service.BalanceInitialization(session, corp.GetActorID(),
 			corporate.ID,
 			corp.ToActorObject(),
 			balanceName,
-			corporate.Currency,
-			session,
+			corporate.GetCurrency(),
 		)
 
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		corp, err := service.CorporateByID(corporate.ID.Hex(), session)
+		corp, err := service.CorporateByID(session, corporate.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -268,28 +204,21 @@ func InitializeBalanceCorporate(corp domain.ActorAble, corporate domain.Corporat
 			Access:    domain.ACCESS_BALANCE_OWNER,
 		})
 
-		err = service.CorporateUpdateOne(&corp, session)
+		err = service.CorporateUpdateOne(session, &corp)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		createVABalance(&balance, corporate.Name)
-		err = service.BalanceUpdate(balance, session)
+		createVABalance(ctx, &balance, corporate.Name)
+		err = service.BalanceUpdate(session, balance)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, createBalanceForCorporate)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, createBalanceForCorporate)
 
 	if err != nil {
 		return balance, utils.ErrorInternalServer(utils.DBStartTransactionFailed,
@@ -299,29 +228,42 @@ func InitializeBalanceCorporate(corp domain.ActorAble, corporate domain.Corporat
 	return balance, nil
 }
 
-func WithdrawBalance(statement domain.Statement, session mongo.SessionContext) error {
-	balanceID := statement.BalanceID.Hex()
-	amount := statement.Withdraw
+func WithdrawBalance(ctx context.Context, statement domain.Statement) error {
+	return applyStatement(ctx, statement, -statement.Withdraw)
+}
 
-	balance, err := service.BalanceByID(balanceID, session)
-	if err != nil {
-		return err
-	}
+func DepositBalance(ctx context.Context, statement domain.Statement) error {
+	return applyStatement(ctx, statement, statement.Deposit)
+}
 
-	if balance.Amount < amount {
-		return utils.ErrorBadRequest(utils.InsufficientBalance, "Insufficient balance")
-	}
+// Balance is changed with a single conditional increment, the statement keep
+// the balance after and the sequence taken by the same increment. Collected
+// fee is deposited to the sub balance of the statement instead.
+func applyStatement(ctx context.Context, statement domain.Statement, amount int) error {
+	var balance domain.Balance
+	var err error
 
-	balance.Amount = balance.Amount - amount
+	if statement.SubBalance > 0 && amount > 0 {
+		var subBalance domain.SubBalance
+		balance, subBalance, err = service.BalanceDepositSub(ctx, statement.BalanceID, statement.SubBalance, amount,
+			statement.Currency)
+		balance.StatementSequence = subBalance.StatementSequence
+	} else {
+		statement.SubBalance = 0
+		balance, err = service.BalanceAdjust(ctx, statement.BalanceID, amount, statement.Currency)
+	}
 
-	err = service.BalanceUpdate(balance, session)
 	if err != nil {
 		return err
 	}
 
 	statement.Balance = balance.Amount
+	statement.Sequence = balance.StatementSequence
+	if balance.Currency != "" {
+		statement.Currency = balance.Currency
+	}
 
-	err = service.StatementSaveOne(statement, session)
+	err = service.StatementSaveOne(ctx, statement)
 	if err != nil {
 		return err
 	}
@@ -329,60 +271,105 @@ func WithdrawBalance(statement domain.Statement, session mongo.SessionContext) e
 	return nil
 }
 
-func DepositBalance(statement domain.Statement, session mongo.SessionContext) error {
-	balanceID := statement.BalanceID.Hex()
-	amount := statement.Deposit
+func StatementByBalanceID(ctx context.Context, balanceID string, page string,
+	limit string) ([]domain.Statement, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
+	if err != nil || balance.Owner.Type == "" {
+		return []domain.Statement{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
+
+	}
 
-	balance, err := service.BalanceByID(balanceID, session)
+	statements, err := service.StatementsByBalanceID(ctx, balance.ID, page, limit)
 	if err != nil {
-		return err
+		return []domain.Statement{}, err
 	}
 
-	balance.Amount = balance.Amount + amount
+	return statements, nil
+}
 
-	err = service.BalanceUpdate(balance, session)
-	if err != nil {
-		return err
+// Compare the statements of the balance and of each sub balance with their
+// sequence counter, a missing sequence is an adjust without statement and a
+// duplicated one is applied twice
+func CheckStatementSequence(ctx context.Context, balanceID string) ([]domain.StatementSequenceCheck, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
+	if err != nil || balance.Owner.Type == "" {
+		return []domain.StatementSequenceCheck{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
 	}
 
-	statement.Balance = balance.Amount
+	subBalances, err := service.SubBalancesByBalanceID(ctx, balance.ID)
+	if err != nil {
+		return []domain.StatementSequenceCheck{}, err
+	}
 
-	err = service.StatementSaveOne(statement, session)
+	statements, err := service.StatementsSequenced(ctx, balance.ID)
 	if err != nil {
-		return err
+		return []domain.StatementSequenceCheck{}, err
 	}
 
-	return nil
+	sequences := map[int][]int64{}
+	for _, statement := range statements {
+		sequences[statement.SubBalance] = append(sequences[statement.SubBalance], statement.Sequence)
+	}
+
+	checks := []domain.StatementSequenceCheck{
+		checkSequence(balance.ID, 0, balance.StatementSequence, sequences[0]),
+	}
+
+	for _, subBalance := range subBalances {
+		checks = append(checks, checkSequence(balance.ID, subBalance.Index, subBalance.StatementSequence,
+			sequences[subBalance.Index]))
+	}
+
+	return checks, nil
 }
 
-func StatementByBalanceID(balanceID string, page string, limit string) ([]domain.Statement, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
-	if err != nil || balance.Owner.Type == "" {
-		return []domain.Statement{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
+// Sequences must be ordered, they should be every number from 1 to last
+func checkSequence(balanceID primitive.ObjectID, subBalance int, last int64,
+	sequences []int64) domain.StatementSequenceCheck {
 
+	check := domain.StatementSequenceCheck{
+		BalanceID:    balanceID,
+		SubBalance:   subBalance,
+		LastSequence: last,
+		Missing:      []int64{},
+		Duplicated:   []int64{},
 	}
 
-	statements, err := service.StatementsByBalanceID(balance.ID, page, limit)
-	if err != nil {
-		return []domain.Statement{}, err
+	next := int64(1)
+	for i, sequence := range sequences {
+		if i > 0 && sequence == sequences[i-1] {
+			check.Duplicated = append(check.Duplicated, sequence)
+			continue
+		}
+
+		for ; next < sequence; next++ {
+			check.Missing = append(check.Missing, next)
+		}
+
+		next = sequence + 1
 	}
 
-	return statements, nil
+	for ; next <= last; next++ {
+		check.Missing = append(check.Missing, next)
+	}
+
+	return check
 }
 
-func ShareBalance(corporate domain.Corporate, balanceID string, access string, actorID string, pin string) error {
+func ShareBalance(ctx context.Context, corporate domain.Corporate, balanceID string, access string,
+	actorID string, pin string) error {
 
-	err := ValidateActorPIN(corporate, pin)
+	err := ValidateActorPIN(ctx, corporate, pin)
 	if err != nil {
 		return err
 	}
 
-	balance, err := service.BalanceByIDNoSession(balanceID)
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil || balance.Owner.Type == "" {
 		return utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
 	}
 
-	actor, err := ActorByID(actorID)
+	actor, err := ActorByID(ctx, actorID)
 	if err != nil {
 		return err
 	}
@@ -399,7 +386,7 @@ func ShareBalance(corporate domain.Corporate, balanceID string, access string, a
 		return utils.ErrorBadRequest(utils.AccessBalanceAlreadyHave, "Access balance already have")
 	}
 
-	err = ActorAddBalance(actor, domain.AccessBalance{
+	err = ActorAddBalance(ctx, actor, domain.AccessBalance{
 		BalanceID: balance.ID,
 		Access:    access,
 	})
@@ -411,19 +398,20 @@ func ShareBalance(corporate domain.Corporate, balanceID string, access string, a
 	return nil
 }
 
-func RevokeBalance(corporate domain.Corporate, balanceID string, revokeFrom string, pin string) error {
+func RevokeBalance(ctx context.Context, corporate domain.Corporate, balanceID string, revokeFrom string,
+	pin string) error {
 
-	err := ValidateActorPIN(corporate, pin)
+	err := ValidateActorPIN(ctx, corporate, pin)
 	if err != nil {
 		return err
 	}
 
-	balance, err := service.BalanceByIDNoSession(balanceID)
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil || balance.Owner.Type == "" {
 		return utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
 	}
 
-	actor, err := ActorByID(revokeFrom)
+	actor, err := ActorByID(ctx, revokeFrom)
 	if err != nil {
 		return err
 	}
@@ -432,7 +420,7 @@ func RevokeBalance(corporate domain.Corporate, balanceID string, revokeFrom stri
 		return utils.ErrorBadRequest(utils.InvalidAccessType, "Invalid balance scope")
 	}
 
-	err = ActorRemoveBalance(actor, balanceID)
+	err = ActorRemoveBalance(ctx, actor, balanceID)
 	if err != nil {
 		return err
 	}
@@ -440,10 +428,10 @@ func RevokeBalance(corporate domain.Corporate, balanceID string, revokeFrom stri
 	return nil
 }
 
-func CreateRequestAccesssBalance(corporate domain.Corporate, requester domain.ActorAble,
+func CreateRequestAccesssBalance(ctx context.Context, corporate domain.Corporate, requester domain.ActorAble,
 	balanceID string, access string) (domain.RequestAccessBalance, error) {
 
-	balance, err := service.BalanceByIDNoSession(balanceID)
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil || balance.Owner.Type == "" {
 		return domain.RequestAccessBalance{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
 	}
@@ -456,7 +444,7 @@ func CreateRequestAccesssBalance(corporate domain.Corporate, requester domain.Ac
 		return domain.RequestAccessBalance{}, utils.ErrorBadRequest(utils.AccessBalanceAlreadyHave, "Access balance already have")
 	}
 
-	request, err := service.CreateRAB(corporate, balance, requester.ToActorObject(), balance.Owner, access)
+	request, err := service.CreateRAB(ctx, corporate, balance, requester.ToActorObject(), balance.Owner, access)
 	if err != nil {
 		return domain.RequestAccessBalance{}, err
 	}
@@ -464,8 +452,9 @@ func CreateRequestAccesssBalance(corporate domain.Corporate, requester domain.Ac
 	return request, nil
 }
 
-func ListRequesterAccesssBalance(actor domain.ActorAble, status string) ([]domain.RequestAccessBalance, error) {
-	result, err := service.RABByRequsterID(actor.GetActorID().Hex(), status)
+func ListRequesterAccesssBalance(ctx context.Context, actor domain.ActorAble,
+	status string) ([]domain.RequestAccessBalance, error) {
+	result, err := service.RABByRequsterID(ctx, actor.GetActorID().Hex(), status)
 	if err != nil {
 		return []domain.RequestAccessBalance{}, err
 	}
@@ -473,8 +462,9 @@ func ListRequesterAccesssBalance(actor domain.ActorAble, status string) ([]domai
 	return result, nil
 }
 
-func ListOwnerAccesssBalance(actor domain.ActorAble, status string) ([]domain.RequestAccessBalance, error) {
-	result, err := service.RABByOwnerID(actor.GetActorID().Hex(), status)
+func ListOwnerAccesssBalance(ctx context.Context, actor domain.ActorAble,
+	status string) ([]domain.RequestAccessBalance, error) {
+	result, err := service.RABByOwnerID(ctx, actor.GetActorID().Hex(), status)
 	if err != nil {
 		return []domain.RequestAccessBalance{}, err
 	}
@@ -482,14 +472,15 @@ func ListOwnerAccesssBalance(actor domain.ActorAble, status string) ([]domain.Re
 	return result, nil
 }
 
-func ProccedRAB(requestID string, status string, owner domain.ActorAble, pin string) (domain.RequestAccessBalance, error) {
+func ProccedRAB(ctx context.Context, requestID string, status string, owner domain.ActorAble,
+	pin string) (domain.RequestAccessBalance, error) {
 
-	request, err := service.RABByID(requestID)
+	request, err := service.RABByID(ctx, requestID)
 	if err != nil {
 		return domain.RequestAccessBalance{}, utils.ErrorBadRequest(utils.RequestAccessBalanceNotFound, "Balance not found")
 	}
 
-	requester, err := ActorObjectToActor(request.BalanceRequester)
+	requester, err := ActorObjectToActor(ctx, request.BalanceRequester)
 	if err != nil {
 		return domain.RequestAccessBalance{}, err
 	}
@@ -508,7 +499,7 @@ func ProccedRAB(requestID string, status string, owner domain.ActorAble, pin str
 		return domain.RequestAccessBalance{}, utils.ErrorBadRequest(utils.InvalidBalanceOwner, "Invalid balance access")
 	}
 
-	err = ValidateActorPIN(owner, pin)
+	err = ValidateActorPIN(ctx, owner, pin)
 	if err != nil {
 		return domain.RequestAccessBalance{}, err
 	}
@@ -517,7 +508,7 @@ func ProccedRAB(requestID string, status string, owner domain.ActorAble, pin str
 
 	if status == domain.REQUEST_ACCESS_BALANCE_STATUS_APPROVE {
 
-		err = ActorAddBalance(requester, domain.AccessBalance{
+		err = ActorAddBalance(ctx, requester, domain.AccessBalance{
 			BalanceID: request.BalanceID,
 			Access:    request.Access,
 		})
@@ -528,7 +519,7 @@ func ProccedRAB(requestID string, status string, owner domain.ActorAble, pin str
 
 	}
 
-	err = service.RABUpdateOne(&request)
+	err = service.RABUpdateOne(ctx, &request)
 	if err != nil {
 		return domain.RequestAccessBalance{}, err
 	}
@@ -536,12 +527,12 @@ func ProccedRAB(requestID string, status string, owner domain.ActorAble, pin str
 	return request, nil
 }
 
-func createVABalance(balance *domain.Balance, ownerName string) {
+func createVABalance(ctx context.Context, balance *domain.Balance, ownerName string) {
 
 	balanceName := ownerName + " " + balance.Name
 	gatewayXendit := gateway.XenditGateway{}
 
-	mandiriAccountNumber, err := gatewayXendit.CreateVA(balance.ID.Hex(), balanceName, "MANDIRI")
+	mandiriAccountNumber, err := gatewayXendit.CreateVA(ctx, balance.ID.Hex(), balanceName, "MANDIRI")
 	if err != nil {
 		balance.VA = append(balance.VA, domain.VirtualAccount{
 			BankCode:      "MANDIRI",
@@ -554,7 +545,7 @@ func createVABalance(balance *domain.Balance, ownerName string) {
 		})
 	}
 
-	bniAccountNumber, err := gatewayXendit.CreateVA(balance.ID.Hex(), balanceName, "BNI")
+	bniAccountNumber, err := gatewayXendit.CreateVA(ctx, balance.ID.Hex(), balanceName, "BNI")
 	if err != nil {
 		balance.VA = append(balance.VA, domain.VirtualAccount{
 			BankCode:      "BNI",
@@ -567,7 +558,7 @@ func createVABalance(balance *domain.Balance, ownerName string) {
 		})
 	}
 
-	briAccountNumber, err := gatewayXendit.CreateVA(balance.ID.Hex(), balanceName, "BRI")
+	briAccountNumber, err := gatewayXendit.CreateVA(ctx, balance.ID.Hex(), balanceName, "BRI")
 	if err != nil {
 		balance.VA = append(balance.VA, domain.VirtualAccount{
 			BankCode:      "BRI",
@@ -580,7 +571,7 @@ func createVABalance(balance *domain.Balance, ownerName string) {
 		})
 	}
 
-	permataAccountNumber, err := gatewayXendit.CreateVA(balance.ID.Hex(), balanceName, "PERMATA")
+	permataAccountNumber, err := gatewayXendit.CreateVA(ctx, balance.ID.Hex(), balanceName, "PERMATA")
 	if err != nil {
 		balance.VA = append(balance.VA, domain.VirtualAccount{
 			BankCode:      "PERMATA",
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/usecase/security"
+)
+
+// Challenge to send along the face capture of a face login
+func FaceLoginChallenge(ctx context.Context, phoneNumber string, corporate domain.Corporate,
+	deviceID string) (domain.BiometricChallenge, error) {
+	user, err := service.UserByPhoneNumberWithoutSession(ctx, corporate.ID, phoneNumber)
+	if err != nil {
+		return domain.BiometricChallenge{}, err
+	}
+
+	err = service.ValidateUserLocked(user)
+	if err != nil {
+		return domain.BiometricChallenge{}, err
+	}
+
+	return service.BiometricChallengeIssue(ctx, user, domain.BIOMETRIC_PURPOSE_LOGIN, deviceID)
+}
+
+// Challenge to send along the face capture used as PIN
+func TemporaryPINChallenge(ctx context.Context, user domain.User, deviceID string) (domain.BiometricChallenge, error) {
+	err := service.ValidateUserLocked(user)
+	if err != nil {
+		return domain.BiometricChallenge{}, err
+	}
+
+	return service.BiometricChallengeIssue(ctx, user, domain.BIOMETRIC_PURPOSE_TEMPORARY_PIN, deviceID)
+}
+
+// Face is accepted only with its challenge and from the bound device, invalid
+// challenge and face mismatch both count toward lockout
+func verifyUserFace(ctx context.Context, corporate domain.Corporate, user domain.User, purpose string,
+	challengeID string, nonce string, deviceID string, faceImage string) error {
+
+	err := service.ValidateUserLocked(user)
+	if err != nil {
+		return err
+	}
+
+	err = service.BiometricChallengeConsume(ctx, user, purpose, challengeID, nonce, deviceID)
+	if err != nil {
+		go security.InvalidUserAuth(context.Background(), user)
+		return err
+	}
+
+	_, err = service.IdentityVerifyUser(corporate, user, faceImage)
+	if err != nil {
+		go security.InvalidUserAuth(context.Background(), user)
+		return err
+	}
+
+	return nil
+}
@@ -1,9 +1,7 @@
 package usecase
 
 import (
-	"os"
-	"strconv"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -57,12 +55,12 @@ func (self *CalculateFee) Initialize(corporate domain.Corporate, balance domain.
 	self.transactionType = transaction.Type
 }
 
-func (self *CalculateFee) CalculateByOwnerAndTransaction() ([]domain.Statement, error) {
+func (self *CalculateFee) CalculateByOwnerAndTransaction(ctx context.Context) ([]domain.Statement, error) {
 	var err error
 	if self.balanceType == domain.ACTOR_TYPE_USER {
-		err = self.balanceUser(self.corporate, self.balance, self.transaction)
+		err = self.balanceUser(ctx, self.corporate, self.balance, self.transaction)
 	} else {
-		err = self.balanceCorporate(self.corporate, self.balance, self.transaction)
+		err = self.balanceCorporate(ctx, self.corporate, self.balance, self.transaction)
 	}
 
 	return self.result, err
@@ -75,17 +73,17 @@ func (self *CalculateFee) RollbackFeeStatement(statements []domain.Statement) []
 		if element.Withdraw != 0 {
 			s := service.DepositFeeStatement(
 				element.BalanceID,
-				time.Now().Format(os.Getenv("TIME_FORMAT")),
+				domain.TimestampNow(),
 				element.Reference,
-				element.Withdraw,
+				element.WithdrawMoney(),
 			)
 			result = append(result, s)
 		} else {
 			s := service.WithdrawFeeStatement(
 				element.BalanceID,
-				time.Now().Format(os.Getenv("TIME_FORMAT")),
+				domain.TimestampNow(),
 				element.Reference,
-				element.Deposit,
+				element.DepositMoney(),
 			)
 			result = append(result, s)
 		}
@@ -94,30 +92,31 @@ func (self *CalculateFee) RollbackFeeStatement(statements []domain.Statement) []
 	return result
 }
 
-func (self *CalculateFee) balanceUser(corporate domain.Corporate, userBalance domain.Balance, transaction domain.Transaction) error {
+func (self *CalculateFee) balanceUser(ctx context.Context, corporate domain.Corporate,
+	userBalance domain.Balance, transaction domain.Transaction) error {
 	if self.transactionType == domain.TRANSFER_BANK {
-		a, err := balanceUserTransferBank(corporate, userBalance, transaction)
+		a, err := balanceUserTransferBank(ctx, corporate, userBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.TOPUP {
-		a, err := balanceUserTopupBank(corporate, userBalance, transaction)
+		a, err := balanceUserTopupBank(ctx, corporate, userBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.TRANSFER_WALLET {
-		a, err := balanceUserTransferBalance(corporate, userBalance, transaction)
+		a, err := balanceUserTransferBalance(ctx, corporate, userBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.ACCEPT_PAYMENT_CARD {
-		a, err := balanceUserAcceptPaymentCard(corporate, userBalance, transaction)
+		a, err := balanceUserAcceptPaymentCard(ctx, corporate, userBalance, transaction)
 		if err != nil {
 			return err
 		}
@@ -128,37 +127,38 @@ func (self *CalculateFee) balanceUser(corporate domain.Corporate, userBalance do
 	return nil
 }
 
-func (self *CalculateFee) balanceCorporate(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) error {
+func (self *CalculateFee) balanceCorporate(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) error {
 	if self.transactionType == domain.TRANSFER_BANK {
-		a, err := balanceCorporateTransferBank(corporate, corporateBalance, transaction)
+		a, err := balanceCorporateTransferBank(ctx, corporate, corporateBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.TOPUP {
-		a, err := balanceCorporateTopupBank(corporate, corporateBalance, transaction)
+		a, err := balanceCorporateTopupBank(ctx, corporate, corporateBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.TRANSFER_WALLET {
-		a, err := balanceCorporateTransferBalance(corporate, corporateBalance, transaction)
+		a, err := balanceCorporateTransferBalance(ctx, corporate, corporateBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.DEDUCT {
-		a, err := balanceCorporateDeductBalance(corporate, corporateBalance, transaction)
+		a, err := balanceCorporateDeductBalance(ctx, corporate, corporateBalance, transaction)
 		if err != nil {
 			return err
 		}
 
 		self.result = a
 	} else if self.transactionType == domain.ACCEPT_PAYMENT_CARD {
-		a, err := balanceCorporateAcceptPaymentCard(corporate, corporateBalance, transaction)
+		a, err := balanceCorporateAcceptPaymentCard(ctx, corporate, corporateBalance, transaction)
 		if err != nil {
 			return err
 		}
@@ -169,30 +169,31 @@ func (self *CalculateFee) balanceCorporate(corporate domain.Corporate, corporate
 	return nil
 }
 
-func balanceUserTransferBank(corporate domain.Corporate, userBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
-	userFee := corporate.FeeUser.TransferBank
+func balanceUserTransferBank(ctx context.Context, corporate domain.Corporate, userBalance domain.Balance,
+	transaction domain.Transaction) ([]domain.Statement, error) {
+	userFee := domain.NewMoney(corporate.FeeUser.TransferBank, transaction.Currency)
 	userBalanceID := userBalance.ID
 	corporateBalanceID := corporate.MainBalance
 
 	var result []domain.Statement
 
 	withdrawUser := service.WithdrawFeeStatement(userBalanceID, transaction.Time, transaction.TransactionCode, userFee)
-	depositCorporate := service.DepositFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
+	depositCorporate := service.CollectFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
 
 	result = append(result, withdrawUser)
 	result = append(result, depositCorporate)
 
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.TransferBank
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.TransferBank, transaction.Currency)
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
@@ -201,20 +202,21 @@ func balanceUserTransferBank(corporate domain.Corporate, userBalance domain.Bala
 	return result, nil
 }
 
-func balanceCorporateTransferBank(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceCorporateTransferBank(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.TransferBank
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.TransferBank, transaction.Currency)
 		corporateBalanceID := corporate.MainBalance
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
 	}
@@ -222,30 +224,31 @@ func balanceCorporateTransferBank(corporate domain.Corporate, corporateBalance d
 	return result, nil
 }
 
-func balanceUserTopupBank(corporate domain.Corporate, userBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
-	userFee := corporate.FeeUser.Topup
+func balanceUserTopupBank(ctx context.Context, corporate domain.Corporate, userBalance domain.Balance,
+	transaction domain.Transaction) ([]domain.Statement, error) {
+	userFee := domain.NewMoney(corporate.FeeUser.Topup, transaction.Currency)
 	userBalanceID := userBalance.ID
 	corporateBalanceID := corporate.MainBalance
 
 	var result []domain.Statement
 
 	withdrawUser := service.WithdrawFeeStatement(userBalanceID, transaction.Time, transaction.TransactionCode, userFee)
-	depositCorporate := service.DepositFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
+	depositCorporate := service.CollectFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
 
 	result = append(result, withdrawUser)
 	result = append(result, depositCorporate)
 
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.Topup
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.Topup, transaction.Currency)
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
@@ -254,20 +257,21 @@ func balanceUserTopupBank(corporate domain.Corporate, userBalance domain.Balance
 	return result, nil
 }
 
-func balanceCorporateTopupBank(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceCorporateTopupBank(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.Topup
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.Topup, transaction.Currency)
 		corporateBalanceID := corporate.MainBalance
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
 	}
@@ -275,30 +279,31 @@ func balanceCorporateTopupBank(corporate domain.Corporate, corporateBalance doma
 	return result, nil
 }
 
-func balanceUserTransferBalance(corporate domain.Corporate, userBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
-	userFee := corporate.FeeUser.TransferBalance
+func balanceUserTransferBalance(ctx context.Context, corporate domain.Corporate, userBalance domain.Balance,
+	transaction domain.Transaction) ([]domain.Statement, error) {
+	userFee := domain.NewMoney(corporate.FeeUser.TransferBalance, transaction.Currency)
 	userBalanceID := userBalance.ID
 	corporateBalanceID := corporate.MainBalance
 
 	var result []domain.Statement
 
 	withdrawUser := service.WithdrawFeeStatement(userBalanceID, transaction.Time, transaction.TransactionCode, userFee)
-	depositCorporate := service.DepositFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
+	depositCorporate := service.CollectFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
 
 	result = append(result, withdrawUser)
 	result = append(result, depositCorporate)
 
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.TransferBalance
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.TransferBalance, transaction.Currency)
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
@@ -307,20 +312,21 @@ func balanceUserTransferBalance(corporate domain.Corporate, userBalance domain.B
 	return result, nil
 }
 
-func balanceCorporateTransferBalance(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceCorporateTransferBalance(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.TransferBalance
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.TransferBalance, transaction.Currency)
 		corporateBalanceID := corporate.MainBalance
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
 	}
@@ -328,20 +334,21 @@ func balanceCorporateTransferBalance(corporate domain.Corporate, corporateBalanc
 	return result, nil
 }
 
-func balanceCorporateDeductBalance(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceCorporateDeductBalance(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
 	if IsNotPrincipal(corporate) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := corporate.FeeCorporate.Deduct
+		corporateFee := domain.NewMoney(corporate.FeeCorporate.Deduct, transaction.Currency)
 		corporateBalanceID := corporate.MainBalance
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
 	}
@@ -350,26 +357,25 @@ func balanceCorporateDeductBalance(corporate domain.Corporate, corporateBalance
 }
 
 // TODO PROVIDE LOGIC FOR PRINCIPAL CAN ACCEPT MONEY FROM MULTICURRENCY TRANSACTION
-func balanceCorporateAcceptPaymentCard(corporate domain.Corporate, corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceCorporateAcceptPaymentCard(ctx context.Context, corporate domain.Corporate,
+	corporateBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
 	if IsNotPrincipal(corporate) && IsNotIDRCurrency(transaction.Currency) {
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		percentageFee, err := strconv.ParseFloat(corporate.FeeCorporate.AcceptPaymentCard, 64)
+		corporateFee, err := PercentageFee(corporate.FeeCorporate, transaction)
 		if err != nil {
-			return result, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
+			return result, err
 		}
 
-		corporateFee := int(float64(transaction.SubAmount) * percentageFee)
-
 		corporateBalanceID := corporate.MainBalance
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
 	}
@@ -378,41 +384,39 @@ func balanceCorporateAcceptPaymentCard(corporate domain.Corporate, corporateBala
 }
 
 // TODO PROVIDE LOGIC FOR PRINCIPAL CAN ACCEPT MONEY FROM MULTICURRENCY TRANSACTION
-func balanceUserAcceptPaymentCard(corporate domain.Corporate, userBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
+func balanceUserAcceptPaymentCard(ctx context.Context, corporate domain.Corporate,
+	userBalance domain.Balance, transaction domain.Transaction) ([]domain.Statement, error) {
 	var result []domain.Statement
-	percentageFee, err := strconv.ParseFloat(corporate.FeeUser.AcceptPaymentCard, 64)
+	userFee, err := PercentageFee(corporate.FeeUser, transaction)
 	if err != nil {
-		return result, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
+		return result, err
 	}
 
-	userFee := int(float64(transaction.SubAmount) * percentageFee)
-
 	userBalanceID := userBalance.ID
 	corporateBalanceID := corporate.MainBalance
 
 	withdrawUser := service.WithdrawFeeStatement(userBalanceID, transaction.Time, transaction.TransactionCode, userFee)
-	depositCorporate := service.DepositFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
+	depositCorporate := service.CollectFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, userFee)
 
 	result = append(result, withdrawUser)
 	result = append(result, depositCorporate)
 
 	if IsNotPrincipal(corporate) && IsNotIDRCurrency(transaction.Currency) {
 
-		percentageFee, err := strconv.ParseFloat(corporate.FeeCorporate.AcceptPaymentCard, 64)
+		corporateFee, err := PercentageFee(corporate.FeeCorporate, transaction)
 		if err != nil {
-			return result, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
+			return result, err
 		}
 
-		principal, err := service.CorporateByIDNoSession(corporate.Parent.Hex())
+		principal, err := service.CorporateByID(ctx, corporate.Parent.Hex())
 		if err != nil {
 			return result, err
 		}
 
-		corporateFee := int(float64(transaction.SubAmount) * percentageFee)
 		principalBalanceID := principal.MainBalance
 
 		withdrawCorporate := service.WithdrawFeeStatement(corporateBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
-		depositPrincipal := service.DepositFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
+		depositPrincipal := service.CollectFeeStatement(principalBalanceID, transaction.Time, transaction.TransactionCode, corporateFee)
 
 		result = append(result, withdrawCorporate)
 		result = append(result, depositPrincipal)
@@ -429,8 +433,19 @@ func IsNotPrincipal(corporate domain.Corporate) bool {
 	return true
 }
 
+// Accept payment card fee is percentage of sub amount, rounded with corporate
+// rounding policy (banker's rounding when not set)
+func PercentageFee(fee domain.Fee, transaction domain.Transaction) (domain.Money, error) {
+	result, err := transaction.SubAmountMoney().Percentage(fee.AcceptPaymentCard, fee.Rounding)
+	if err != nil {
+		return domain.Money{}, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
+	}
+
+	return result, nil
+}
+
 func IsNotIDRCurrency(currency string) bool {
-	if currency != "idr" {
+	if domain.NormalizeCurrency(currency) != domain.CURRENCY_IDR {
 		return true
 	}
 
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"strconv"
@@ -114,11 +115,11 @@ func callbackTopupHTTP(corporate domain.Corporate, transaction domain.Transactio
 	reqBody, _ := json.Marshal(payload)
 
 	if resp.StatusCode() != 200 || err != nil {
-		go service.CreateCallbackHistoryRefused(transaction.TransactionCode, url, string(reqBody))
+		go service.CreateCallbackHistoryRefused(context.Background(), transaction.TransactionCode, url, string(reqBody))
 		return utils.ErrorInternalServer(utils.CallbackError, "Callback topup corporate connection refused or Timeout")
 	}
 
-	go service.CreateCallbackHistory(transaction.TransactionCode, url,
+	go service.CreateCallbackHistory(context.Background(), transaction.TransactionCode, url,
 		string(reqBody), string(resp.Body()), strconv.Itoa(resp.StatusCode()))
 
 	return nil
@@ -145,11 +146,11 @@ func callbackDeductHTTP(corporate domain.Corporate, transaction domain.Transacti
 	reqBody, _ := json.Marshal(payload)
 
 	if resp.StatusCode() != 200 || err != nil {
-		go service.CreateCallbackHistoryRefused(transaction.TransactionCode, url, string(reqBody))
+		go service.CreateCallbackHistoryRefused(context.Background(), transaction.TransactionCode, url, string(reqBody))
 		return utils.ErrorInternalServer(utils.CallbackError, "Callback deduct corporate connection refused or Timeout")
 	}
 
-	go service.CreateCallbackHistory(transaction.TransactionCode, url,
+	go service.CreateCallbackHistory(context.Background(), transaction.TransactionCode, url,
 		string(reqBody), string(resp.Body()), strconv.Itoa(resp.StatusCode()))
 
 	return nil
@@ -176,11 +177,11 @@ func callbackTransferHTTP(corporate domain.Corporate, transaction domain.Transac
 	reqBody, _ := json.Marshal(payload)
 
 	if resp.StatusCode() != 200 || err != nil {
-		go service.CreateCallbackHistoryRefused(transaction.TransactionCode, url, string(reqBody))
+		go service.CreateCallbackHistoryRefused(context.Background(), transaction.TransactionCode, url, string(reqBody))
 		return utils.ErrorInternalServer(utils.CallbackError, "Callback transfer corporate connection refused or Timeout")
 	}
 
-	go service.CreateCallbackHistory(transaction.TransactionCode, url,
+	go service.CreateCallbackHistory(context.Background(), transaction.TransactionCode, url,
 		string(reqBody), string(resp.Body()), strconv.Itoa(resp.StatusCode()))
 
 	return nil
@@ -230,11 +231,11 @@ func callbackAcceptPaymentHTTP(corporate domain.Corporate, transaction domain.Tr
 	reqBody, _ := json.Marshal(payload)
 
 	if resp.StatusCode() != 200 || err != nil {
-		go service.CreateCallbackHistoryRefused(transaction.TransactionCode, url, string(reqBody))
+		go service.CreateCallbackHistoryRefused(context.Background(), transaction.TransactionCode, url, string(reqBody))
 		return utils.ErrorInternalServer(utils.CallbackError, "Callback topup corporate connection refused or Timeout")
 	}
 
-	go service.CreateCallbackHistory(transaction.TransactionCode, url,
+	go service.CreateCallbackHistory(context.Background(), transaction.TransactionCode, url,
 		string(reqBody), string(resp.Body()), strconv.Itoa(resp.StatusCode()))
 
 	return nil
@@ -7,38 +7,18 @@ import (
 	"github.com/takeme-id/core/domain/dto"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
-func CorporateSavePIN(corporate domain.Corporate, encryptedPIN string) error {
-	function := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+func CorporateSavePIN(ctx context.Context, corporate domain.Corporate, encryptedPIN string) error {
+	function := func(session context.Context) error {
+		var err error
 
+		corporate, err = service.CorporateByID(session, corporate.ID.Hex())
 		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
-
-		corporate, err = service.CorporateByID(corporate.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = service.CorporateSavePIN(&corporate, encryptedPIN, session)
-		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.CorporateSavePIN(session, &corporate, encryptedPIN)
 		if err != nil {
 			return err
 		}
@@ -46,12 +26,7 @@ func CorporateSavePIN(corporate domain.Corporate, encryptedPIN string) error {
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, function)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
 
 	if err != nil {
 		return err
@@ -60,18 +35,10 @@ func CorporateSavePIN(corporate domain.Corporate, encryptedPIN string) error {
 	return nil
 }
 
-func CorporateChangePIN(corporate domain.Corporate, encryptedOldPIN string, encryptedNewPIN string) error {
-
-	function := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+func CorporateChangePIN(ctx context.Context, corporate domain.Corporate, encryptedOldPIN string,
+	encryptedNewPIN string) error {
 
+	function := func(session context.Context) error {
 		newPIN, err := utils.RSADecrypt(encryptedNewPIN)
 		if err != nil {
 			return err
@@ -82,24 +49,17 @@ func CorporateChangePIN(corporate domain.Corporate, encryptedOldPIN string, encr
 			return err
 		}
 
-		corporate, err = service.CorporateByID(corporate.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = ValidateActorPIN(corporate, encryptedOldPIN)
+		corporate, err = service.CorporateByID(session, corporate.ID.Hex())
 		if err != nil {
 			return err
 		}
 
-		err = service.CorporateChangeNewPIN(&corporate, newPIN, session)
+		err = ValidateActorPIN(ctx, corporate, encryptedOldPIN)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.CorporateChangeNewPIN(session, &corporate, newPIN)
 		if err != nil {
 			return err
 		}
@@ -107,12 +67,7 @@ func CorporateChangePIN(corporate domain.Corporate, encryptedOldPIN string, encr
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, function)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
 
 	if err != nil {
 		return err
@@ -121,9 +76,9 @@ func CorporateChangePIN(corporate domain.Corporate, encryptedOldPIN string, encr
 	return nil
 }
 
-func CorporateCheck(corporate domain.Corporate) (dto.Corporate, error) {
+func CorporateCheck(ctx context.Context, corporate domain.Corporate) (dto.Corporate, error) {
 
-	result, err := service.CorporateDTOByID(corporate.ID.Hex())
+	result, err := service.CorporateDTOByID(ctx, corporate.ID.Hex())
 	if err != nil {
 		return dto.Corporate{}, err
 	}
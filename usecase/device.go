@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+func UserDevices(ctx context.Context, user domain.User, claims domain.Claims) ([]domain.Device, error) {
+	devices, err := service.DevicesByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentDeviceID := ""
+	userSession, err := service.SessionByID(ctx, claims.SessionID)
+	if err == nil {
+		currentDeviceID = userSession.DeviceID
+	}
+
+	for index := range devices {
+		devices[index].Current = devices[index].DeviceID == currentDeviceID
+	}
+
+	return devices, nil
+}
+
+// Revoke device and every session opened from it, device has to pass OTP and
+// PIN again to be trusted
+func RevokeUserDevice(ctx context.Context, user domain.User, deviceID string) error {
+	device, found, err := service.DeviceTrusted(ctx, user.ID, deviceID)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return utils.ErrorBadRequest(utils.DeviceNotBound, "Device not found")
+	}
+
+	err = service.DeviceRevoke(ctx, &device)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := service.SessionsActiveByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, userSession := range sessions {
+		if userSession.DeviceID == deviceID {
+			revokeSession(ctx, userSession)
+		}
+	}
+
+	return nil
+}
+
+// Known device with the same key only need the login OTP. New device, or known
+// device with a new key, also need the PIN so a swapped SIM alone cannot take
+// over the account. Moving from another device start the cooling-off period.
+func trustLoginDevice(ctx context.Context, user *domain.User, deviceID string, name string, publicKey string,
+	encryptedPIN string) error {
+
+	if deviceID == "" {
+		return utils.ErrorBadRequest(utils.DeviceNotBound, "Device ID required")
+	}
+
+	device, found, err := service.DeviceTrusted(ctx, user.ID, deviceID)
+	if err != nil {
+		return err
+	}
+
+	if found && (publicKey == "" || publicKey == device.PublicKey) {
+		return service.DeviceTouch(ctx, &device)
+	}
+
+	_, err = utils.ParseDevicePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	count, err := service.DeviceTrustedCount(service.Repositories().Transactor.WithoutTransaction(ctx), user.ID)
+	if err != nil {
+		return err
+	}
+
+	// User who has not set PIN yet is just signed up
+	if user.PIN != "" {
+		// Face as PIN need a trusted device, so new device always use the PIN
+		pinUser := *user
+		pinUser.FaceAsPIN = false
+
+		err = ValidateActorPIN(service.Repositories().Transactor.WithoutTransaction(ctx), pinUser, encryptedPIN)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = service.DeviceTrust(ctx, *user, deviceID, name, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 || (user.DeviceID != "" && user.DeviceID != deviceID) {
+		user.DeviceMovedTime = domain.TimestampNow()
+	}
+
+	return nil
+}
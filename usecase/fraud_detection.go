@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+// Score of each rule, decision is taken from the total score against
+// FRAUD_REVIEW_SCORE and FRAUD_BLOCK_SCORE
+const (
+	FRAUD_SCORE_DEVICE_VELOCITY  = 30
+	FRAUD_SCORE_BALANCE_VELOCITY = 30
+	FRAUD_SCORE_NEW_BENEFICIARY  = 40
+	FRAUD_SCORE_ROUND_AMOUNT     = 25
+	FRAUD_SCORE_AFTER_PIN_CHANGE = 40
+)
+
+const (
+	FRAUD_DEFAULT_REVIEW_SCORE  = 50
+	FRAUD_DEFAULT_BLOCK_SCORE   = 80
+	FRAUD_DEFAULT_LARGE_AMOUNT  = 5000000
+	FRAUD_VELOCITY_WINDOW       = 10 * time.Minute
+	FRAUD_DEVICE_VELOCITY_MAX   = 5
+	FRAUD_BALANCE_VELOCITY_MAX  = 10
+	FRAUD_ROUND_AMOUNT_UNIT     = 100000
+	FRAUD_ROUND_AMOUNT_WINDOW   = time.Hour
+	FRAUD_ROUND_AMOUNT_MAX      = 3
+	FRAUD_AFTER_PIN_CHANGE_TIME = 24 * time.Hour
+	DEVICE_DEFAULT_COOLING_OFF  = 24 * time.Hour
+	DEVICE_DEFAULT_LARGE_AMOUNT = 1000000
+)
+
+type FraudDetection struct {
+	corporate   domain.Corporate
+	actor       domain.ActorAble
+	balance     domain.Balance
+	transaction *domain.Transaction
+	score       int
+	rules       []string
+}
+
+func (self *FraudDetection) Initialize(corporate domain.Corporate, actor domain.ActorAble,
+	balance domain.Balance, transaction *domain.Transaction) {
+	self.corporate = corporate
+	self.actor = actor
+	self.balance = balance
+	self.transaction = transaction
+	self.score = 0
+	self.rules = []string{}
+
+	if user, ok := actor.(domain.User); ok {
+		self.transaction.DeviceID = user.DeviceID
+	}
+}
+
+// Evaluate score the transaction, store the decision and return error when
+// transaction is blocked. Review decision is held by the caller.
+func (self *FraudDetection) Evaluate(ctx context.Context) (string, error) {
+	checks := []func(ctx context.Context) (bool, string, int, error){
+		self.deviceVelocity,
+		self.balanceVelocity,
+		self.newBeneficiaryLargeAmount,
+		self.roundAmountBurst,
+		self.afterPINChange,
+		self.newDeviceCoolingOff,
+	}
+
+	for _, check := range checks {
+		hit, rule, score, err := check(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		if hit {
+			self.score += score
+			self.rules = append(self.rules, rule)
+		}
+	}
+
+	decision := self.decision()
+	self.transaction.RiskScore = self.score
+	self.transaction.RiskDecision = decision
+
+	fraud := domain.CreateFraudDecision(self.corporate.ID, self.actor, *self.transaction,
+		self.score, self.rules, decision)
+	err := service.FraudSave(ctx, &fraud)
+	if err != nil {
+		return "", err
+	}
+
+	if decision == domain.FRAUD_DECISION_BLOCK {
+		return decision, utils.ErrorBadRequest(utils.TransactionBlocked, "Transaction blocked by risk engine")
+	}
+
+	if decision == domain.FRAUD_DECISION_REVIEW {
+		log.Info("Transaction held for review ", self.transaction.TransactionCode)
+	}
+
+	return decision, nil
+}
+
+func (self *FraudDetection) Rules() []string {
+	return self.rules
+}
+
+func (self *FraudDetection) decision() string {
+	if self.score >= fraudEnvInt("FRAUD_BLOCK_SCORE", FRAUD_DEFAULT_BLOCK_SCORE) {
+		return domain.FRAUD_DECISION_BLOCK
+	}
+
+	if self.score >= fraudEnvInt("FRAUD_REVIEW_SCORE", FRAUD_DEFAULT_REVIEW_SCORE) {
+		return domain.FRAUD_DECISION_REVIEW
+	}
+
+	return domain.FRAUD_DECISION_ALLOW
+}
+
+func (self *FraudDetection) deviceVelocity(ctx context.Context) (bool, string, int, error) {
+	if self.transaction.DeviceID == "" {
+		return false, "", 0, nil
+	}
+
+	count, err := service.TransactionCountByDeviceSince(ctx, self.transaction.DeviceID,
+		fraudWindowStart(FRAUD_VELOCITY_WINDOW))
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	return count >= FRAUD_DEVICE_VELOCITY_MAX, domain.FRAUD_RULE_DEVICE_VELOCITY, FRAUD_SCORE_DEVICE_VELOCITY, nil
+}
+
+func (self *FraudDetection) balanceVelocity(ctx context.Context) (bool, string, int, error) {
+	count, err := service.TransactionCountByBalanceSince(ctx, self.balance.ID,
+		fraudWindowStart(FRAUD_VELOCITY_WINDOW))
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	return count >= FRAUD_BALANCE_VELOCITY_MAX, domain.FRAUD_RULE_BALANCE_VELOCITY, FRAUD_SCORE_BALANCE_VELOCITY, nil
+}
+
+func (self *FraudDetection) newBeneficiaryLargeAmount(ctx context.Context) (bool, string, int, error) {
+	if self.transaction.SubAmount < fraudEnvInt("FRAUD_LARGE_AMOUNT", FRAUD_DEFAULT_LARGE_AMOUNT) {
+		return false, "", 0, nil
+	}
+
+	known, err := service.TransactionIsBeneficiaryKnown(ctx, self.balance.ID, *self.transaction)
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	return !known, domain.FRAUD_RULE_NEW_BENEFICIARY, FRAUD_SCORE_NEW_BENEFICIARY, nil
+}
+
+func (self *FraudDetection) roundAmountBurst(ctx context.Context) (bool, string, int, error) {
+	if self.transaction.SubAmount == 0 || self.transaction.SubAmount%FRAUD_ROUND_AMOUNT_UNIT != 0 {
+		return false, "", 0, nil
+	}
+
+	count, err := service.TransactionCountRoundAmountSince(ctx, self.balance.ID, FRAUD_ROUND_AMOUNT_UNIT,
+		fraudWindowStart(FRAUD_ROUND_AMOUNT_WINDOW))
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	return count+1 >= FRAUD_ROUND_AMOUNT_MAX, domain.FRAUD_RULE_ROUND_AMOUNT, FRAUD_SCORE_ROUND_AMOUNT, nil
+}
+
+// PIN changed or reset through forgot PIN shortly before money leave the balance
+func (self *FraudDetection) afterPINChange(ctx context.Context) (bool, string, int, error) {
+	user, ok := self.actor.(domain.User)
+	if !ok || user.PINUpdatedTime.IsZero() {
+		return false, "", 0, nil
+	}
+
+	hit := time.Since(user.PINUpdatedTime.Time) < FRAUD_AFTER_PIN_CHANGE_TIME
+	return hit, domain.FRAUD_RULE_AFTER_PIN_CHANGE, FRAUD_SCORE_AFTER_PIN_CHANGE, nil
+}
+
+// Large outflow right after the account moved to a new device is always
+// blocked, scored at the block score so it is kept in the fraud decision
+func (self *FraudDetection) newDeviceCoolingOff(ctx context.Context) (bool, string, int, error) {
+	user, ok := self.actor.(domain.User)
+	if !ok || user.DeviceMovedTime.IsZero() {
+		return false, "", 0, nil
+	}
+
+	if self.transaction.SubAmount < fraudEnvInt("DEVICE_COOLING_OFF_AMOUNT", DEVICE_DEFAULT_LARGE_AMOUNT) {
+		return false, "", 0, nil
+	}
+
+	coolingOff := time.Duration(fraudEnvInt("DEVICE_COOLING_OFF_HOUR", int(DEVICE_DEFAULT_COOLING_OFF/time.Hour))) * time.Hour
+	hit := time.Since(user.DeviceMovedTime.Time) < coolingOff
+	return hit, domain.FRAUD_RULE_NEW_DEVICE, fraudEnvInt("FRAUD_BLOCK_SCORE", FRAUD_DEFAULT_BLOCK_SCORE), nil
+}
+
+func fraudWindowStart(window time.Duration) time.Time {
+	return time.Now().Add(-window)
+}
+
+func fraudEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func FraudReviewQueue(ctx context.Context, corporate domain.Corporate, page string,
+	limit string) ([]domain.Fraud, error) {
+	return service.FraudReviewByCorporate(ctx, corporate.ID, page, limit)
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+// Provider and score threshold used to verify face of the corporate user
+func UpdateIdentitySetting(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	setting domain.IdentitySetting) (domain.IdentitySetting, error) {
+
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_CORPORATE_MANAGE)
+	if err != nil {
+		return domain.IdentitySetting{}, err
+	}
+
+	if setting.Provider != "" && !utils.IsIdentityProvider(setting.Provider) {
+		return domain.IdentitySetting{}, utils.ErrorBadRequest(utils.InvalidIdentitySetting, "Invalid provider "+setting.Provider)
+	}
+
+	if !utils.IsIdentityScore(setting.MatchThreshold) || !utils.IsIdentityScore(setting.LivenessThreshold) {
+		return domain.IdentitySetting{}, utils.ErrorBadRequest(utils.InvalidIdentitySetting, "Threshold must be between 0 and 1")
+	}
+
+	err = service.CorporateUpdateIdentity(ctx, corporate.ID, setting)
+	if err != nil {
+		return domain.IdentitySetting{}, err
+	}
+
+	return setting, nil
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+func IPAllowlist(corporate domain.Corporate) []string {
+	return utils.SplitIPAllowlist(corporate.WhitelistIP)
+}
+
+// Replace corporate allowlist, empty list accept request from any IP
+func UpdateIPAllowlist(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	entries []string) ([]string, error) {
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_CORPORATE_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []string{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		network, err := utils.ParseIPNetwork(entry)
+		if err != nil {
+			return nil, utils.ErrorBadRequest(utils.InvalidIPAllowlist, err.Error())
+		}
+
+		current = append(current, network.String())
+	}
+
+	function := func(session context.Context) error {
+		var err error
+
+		corporate, err = service.CorporateByID(session, corporate.ID.Hex())
+		if err != nil {
+			return err
+		}
+
+		history := domain.IPAllowlistHistory{
+			CorporateID: corporate.ID,
+			Actor:       actor.ToActorObject(),
+			Previous:    utils.SplitIPAllowlist(corporate.WhitelistIP),
+			Current:     current,
+			Time:        domain.TimestampNow(),
+		}
+
+		corporate.WhitelistIP = strings.Join(current, ",")
+		err = service.CorporateUpdateOne(session, &corporate)
+		if err != nil {
+			return err
+		}
+
+		err = service.IPAllowlistHistorySave(session, &history)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+func IPAllowlistHistory(ctx context.Context, corporate domain.Corporate, page string,
+	limit string) ([]domain.IPAllowlistHistory, error) {
+	return service.IPAllowlistHistories(ctx, corporate.ID, page, limit)
+}
@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+	"strings"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+	"github.com/takeme-id/core/utils/storage"
+)
+
+type KYCUpload struct {
+	Type   string
+	File   multipart.File
+	Header *multipart.FileHeader
+}
+
+type KYCSubmission struct {
+	Tier         string
+	NIK          string
+	LegalName    string
+	LegalAddress string
+	DigitalID    string
+	DeviceID     string
+	Documents    []domain.KYCDocument
+}
+
+// Open a case, or resubmit the case waiting for more information. Case is
+// only submitted when every required document of the tier is present.
+func SubmitKYC(ctx context.Context, user domain.User, submission KYCSubmission) (domain.KYCCase, error) {
+	if !domain.IsKYCTier(submission.Tier) {
+		return domain.KYCCase{}, utils.ErrorBadRequest(utils.InvalidKYCTier, "Unknown KYC tier")
+	}
+
+	if domain.KYC_TIER_RANK[submission.Tier] <= domain.KYC_TIER_RANK[user.GetKYCTier()] {
+		return domain.KYCCase{}, utils.ErrorBadRequest(utils.InvalidKYCTier, "Tier already granted")
+	}
+
+	var result domain.KYCCase
+
+	function := func(session context.Context) error {
+		kycCase, found, err := service.KYCCaseOpenByUser(session, user.ID)
+		if err != nil {
+			return err
+		}
+
+		now := domain.TimestampNow()
+		action := domain.KYC_ACTION_SUBMIT
+		if found {
+			if kycCase.Status != domain.KYC_STATUS_MORE_INFO_NEEDED || kycCase.Tier != submission.Tier {
+				return utils.ErrorBadRequest(utils.InvalidKYCState, "KYC case already in progress")
+			}
+
+			for _, documentType := range kycCase.RequestedDocuments {
+				if !hasKYCDocument(submission.Documents, documentType) {
+					return utils.ErrorBadRequest(utils.KYCDocumentMissing, "Requested document "+documentType+" missing")
+				}
+			}
+
+			action = domain.KYC_ACTION_RESUBMIT
+			kycCase.RequestedDocuments = nil
+			kycCase.Reason = ""
+		} else {
+			kycCase = domain.KYCCase{
+				CorporateID: user.CorporateID,
+				UserID:      user.ID,
+				Tier:        submission.Tier,
+				Documents:   []domain.KYCDocument{},
+				Actions:     []domain.KYCAction{},
+				Time:        now,
+			}
+		}
+
+		applyKYCSubmission(&kycCase, submission)
+
+		missing := kycCase.MissingDocuments()
+		if len(missing) > 0 {
+			return utils.ErrorBadRequest(utils.KYCDocumentMissing, "Missing document "+strings.Join(missing, ", "))
+		}
+
+		if kycCase.Tier == domain.KYC_TIER_ORGANIZATION && (kycCase.LegalName == "" || kycCase.LegalAddress == "") {
+			return utils.ErrorBadRequest(utils.KYCDocumentMissing, "Legal name and address required")
+		}
+
+		kycCase.AddAction(user.ToActorObject(), action, domain.KYC_STATUS_SUBMITTED, "", now)
+
+		if found {
+			err = service.KYCCaseUpdateOne(session, &kycCase)
+		} else {
+			err = service.KYCCaseSave(session, &kycCase)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result = kycCase
+
+		return nil
+	}
+
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return domain.KYCCase{}, err
+	}
+
+	return result, nil
+}
+
+// Upload document of a tier, e.g. identity for basic or legal documents for organization
+func SubmitKYCDocuments(ctx context.Context, user domain.User, tier string, nik string, legalName string,
+	legalAddress string, uploads []KYCUpload) (domain.KYCCase, error) {
+
+	documents, err := saveKYCUploads(uploads)
+	if err != nil {
+		return domain.KYCCase{}, err
+	}
+
+	return SubmitKYC(ctx, user, KYCSubmission{
+		Tier:         tier,
+		NIK:          nik,
+		LegalName:    legalName,
+		LegalAddress: legalAddress,
+		Documents:    documents,
+	})
+}
+
+func UserKYCCases(ctx context.Context, user domain.User) ([]domain.KYCCase, error) {
+	return service.KYCCasesByUser(ctx, user.ID)
+}
+
+func KYCCases(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble, status string,
+	page string, limit string) ([]domain.KYCCase, error) {
+
+	err := ValidatePermission(ctx, corporate, reviewer, domain.PERMISSION_KYC_REVIEW)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.KYCCases(ctx, corporate.ID, status, page, limit)
+}
+
+func StartKYCReview(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	caseID string) (domain.KYCCase, error) {
+	return reviewKYCCase(ctx, corporate, reviewer, caseID, func(session context.Context,
+		kycCase *domain.KYCCase, user *domain.User) error {
+
+		if kycCase.Status != domain.KYC_STATUS_SUBMITTED {
+			return utils.ErrorBadRequest(utils.InvalidKYCState, "KYC case not submitted")
+		}
+
+		kycCase.Reviewer = reviewer.ToActorObject()
+		kycCase.AddAction(reviewer.ToActorObject(), domain.KYC_ACTION_START_REVIEW, domain.KYC_STATUS_IN_REVIEW,
+			"", domain.TimestampNow())
+
+		return nil
+	})
+}
+
+// Approve case and grant its tier, limits and transfer rights follow the tier
+func ApproveKYC(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble, caseID string,
+	note string) (domain.KYCCase, error) {
+	return reviewKYCCase(ctx, corporate, reviewer, caseID, func(session context.Context,
+		kycCase *domain.KYCCase, user *domain.User) error {
+
+		if kycCase.Status != domain.KYC_STATUS_SUBMITTED && kycCase.Status != domain.KYC_STATUS_IN_REVIEW {
+			return utils.ErrorBadRequest(utils.InvalidKYCState, "KYC case not waiting for review")
+		}
+
+		if len(kycCase.MissingDocuments()) > 0 {
+			return utils.ErrorBadRequest(utils.KYCDocumentMissing, "KYC case has missing document")
+		}
+
+		kycCase.Reviewer = reviewer.ToActorObject()
+		kycCase.Reason = ""
+		kycCase.AddAction(reviewer.ToActorObject(), domain.KYC_ACTION_APPROVE, domain.KYC_STATUS_APPROVED,
+			note, domain.TimestampNow())
+
+		// Never downgrade a tier granted by another case
+		if domain.KYC_TIER_RANK[kycCase.Tier] <= domain.KYC_TIER_RANK[user.GetKYCTier()] {
+			return nil
+		}
+
+		return service.UserGrantKYCTier(session, user, *kycCase)
+	})
+}
+
+func RejectKYC(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble, caseID string,
+	reason string) (domain.KYCCase, error) {
+	if strings.TrimSpace(reason) == "" {
+		return domain.KYCCase{}, utils.ErrorBadRequest(utils.InvalidKYCState, "Rejection reason required")
+	}
+
+	return reviewKYCCase(ctx, corporate, reviewer, caseID, func(session context.Context,
+		kycCase *domain.KYCCase, user *domain.User) error {
+
+		if kycCase.Status != domain.KYC_STATUS_SUBMITTED && kycCase.Status != domain.KYC_STATUS_IN_REVIEW {
+			return utils.ErrorBadRequest(utils.InvalidKYCState, "KYC case not waiting for review")
+		}
+
+		kycCase.Reviewer = reviewer.ToActorObject()
+		kycCase.Reason = reason
+		kycCase.AddAction(reviewer.ToActorObject(), domain.KYC_ACTION_REJECT, domain.KYC_STATUS_REJECTED,
+			reason, domain.TimestampNow())
+
+		return nil
+	})
+}
+
+// Send case back to user, documents listed must be uploaded again on resubmission
+func RequestKYCInfo(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	caseID string, reason string, documents []string) (domain.KYCCase, error) {
+
+	if strings.TrimSpace(reason) == "" {
+		return domain.KYCCase{}, utils.ErrorBadRequest(utils.InvalidKYCState, "Reason required")
+	}
+
+	for _, documentType := range documents {
+		if !domain.IsKYCDocument(documentType) {
+			return domain.KYCCase{}, utils.ErrorBadRequest(utils.KYCDocumentMissing, "Unknown document "+documentType)
+		}
+	}
+
+	return reviewKYCCase(ctx, corporate, reviewer, caseID, func(session context.Context,
+		kycCase *domain.KYCCase, user *domain.User) error {
+
+		if kycCase.Status != domain.KYC_STATUS_SUBMITTED && kycCase.Status != domain.KYC_STATUS_IN_REVIEW {
+			return utils.ErrorBadRequest(utils.InvalidKYCState, "KYC case not waiting for review")
+		}
+
+		kycCase.Reviewer = reviewer.ToActorObject()
+		kycCase.Reason = reason
+		kycCase.RequestedDocuments = documents
+		kycCase.AddAction(reviewer.ToActorObject(), domain.KYC_ACTION_REQUEST_INFO,
+			domain.KYC_STATUS_MORE_INFO_NEEDED, reason, domain.TimestampNow())
+
+		return nil
+	})
+}
+
+// Load case and its user inside one transaction, reviewer cannot review own case
+func reviewKYCCase(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble, caseID string,
+	review func(session context.Context, kycCase *domain.KYCCase, user *domain.User) error) (domain.KYCCase, error) {
+
+	err := ValidatePermission(ctx, corporate, reviewer, domain.PERMISSION_KYC_REVIEW)
+	if err != nil {
+		return domain.KYCCase{}, err
+	}
+
+	var result domain.KYCCase
+
+	function := func(session context.Context) error {
+		kycCase, err := service.KYCCaseByID(session, caseID)
+		if err != nil || kycCase.CorporateID != corporate.ID {
+			return utils.ErrorBadRequest(utils.KYCCaseNotFound, "KYC case not found")
+		}
+
+		if kycCase.UserID == reviewer.GetActorID() {
+			return utils.ErrorForbidden()
+		}
+
+		user, err := service.UserByID(session, kycCase.UserID.Hex())
+		if err != nil {
+			return err
+		}
+
+		err = review(session, &kycCase, &user)
+		if err != nil {
+			return err
+		}
+
+		err = service.KYCCaseUpdateOne(session, &kycCase)
+		if err != nil {
+			return err
+		}
+
+		result = kycCase
+
+		return nil
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return domain.KYCCase{}, err
+	}
+
+	return result, nil
+}
+
+func applyKYCSubmission(kycCase *domain.KYCCase, submission KYCSubmission) {
+	if submission.NIK != "" {
+		kycCase.NIK = submission.NIK
+	}
+
+	if submission.LegalName != "" {
+		kycCase.LegalName = submission.LegalName
+	}
+
+	if submission.LegalAddress != "" {
+		kycCase.LegalAddress = submission.LegalAddress
+	}
+
+	if submission.DigitalID != "" {
+		kycCase.DigitalID = submission.DigitalID
+		kycCase.DeviceID = submission.DeviceID
+	}
+
+	for _, document := range submission.Documents {
+		kycCase.PutDocument(document)
+	}
+}
+
+func saveKYCUploads(uploads []KYCUpload) ([]domain.KYCDocument, error) {
+	documents := []domain.KYCDocument{}
+	for _, upload := range uploads {
+		if upload.File == nil || upload.Header == nil {
+			continue
+		}
+
+		if !domain.IsKYCDocument(upload.Type) || upload.Type == domain.KYC_DOCUMENT_SELFIE {
+			return nil, utils.ErrorBadRequest(utils.KYCDocumentMissing, "Unknown document "+upload.Type)
+		}
+
+		err, file := storage.SaveFile(upload.File, *upload.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, domain.KYCDocument{
+			Type: upload.Type,
+			File: file,
+			Time: domain.TimestampNow(),
+		})
+	}
+
+	return documents, nil
+}
+
+func hasKYCDocument(documents []domain.KYCDocument, documentType string) bool {
+	for _, document := range documents {
+		if document.Type == documentType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+// Limit rule is keyed by corporate, actor type, KYC tier and
+// transaction type, the most specific rule win. Outflow minimum and maximum
+// not set by the rule fall back to MINIMUM_TRANSFER_AMOUNT and
+// MAXIMUM_TRANSFER_AMOUNT.
+//
+// Validate is checked before anything is written so request fail early,
+// Enforce check again inside the commit transaction where concurrent
+// transactions of the same owner conflict.
+
+const (
+	LIMIT_DAILY_WINDOW   = 24 * time.Hour
+	LIMIT_MONTHLY_WINDOW = 30 * 24 * time.Hour
+)
+
+func LimitTier(actor domain.ActorAble) string {
+	tier := actor.GetKYCTier()
+	if tier == "" {
+		return domain.LIMIT_TIER_UNVERIFIED
+	}
+
+	return tier
+}
+
+// Granted tier also match legacy verified rule
+func LimitTiers(actor domain.ActorAble) []string {
+	tier := LimitTier(actor)
+	if tier == domain.LIMIT_TIER_UNVERIFIED {
+		return []string{tier}
+	}
+
+	return []string{tier, domain.LIMIT_TIER_VERIFIED}
+}
+
+func ResolveLimit(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	transactionType string) (domain.Limit, bool, error) {
+	limits, err := service.LimitsMatch(ctx, corporate.ID, actor.GetActorType(), LimitTiers(actor), transactionType)
+	if err != nil {
+		return domain.Limit{}, false, err
+	}
+
+	if len(limits) == 0 {
+		return domain.Limit{}, false, nil
+	}
+
+	result := limits[0]
+	for _, limit := range limits {
+		if limit.Specificity() > result.Specificity() {
+			result = limit
+		}
+	}
+
+	return result, true, nil
+}
+
+// Validate amount and rolling daily / monthly outflow of balance owner
+func ValidateOutflowLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	transaction domain.Transaction) error {
+	return validateOutflowLimit(ctx, corporate, owner, transaction, false)
+}
+
+// ValidateOutflowLimit inside the commit transaction, ctx must be the
+// transaction. Owner usage is written first so of two concurrent outflow one
+// is rerun and count the other.
+func EnforceOutflowLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	transaction domain.Transaction) error {
+	return validateOutflowLimit(ctx, corporate, owner, transaction, true)
+}
+
+func validateOutflowLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	transaction domain.Transaction, touch bool) error {
+	limit, found, err := ResolveLimit(ctx, corporate, owner, transaction.Type)
+	if err != nil {
+		return err
+	}
+
+	fallback := defaultOutflowLimit()
+	if !found {
+		limit = fallback
+	}
+
+	if limit.Minimum == 0 {
+		limit.Minimum = fallback.Minimum
+	}
+
+	if limit.Maximum == 0 {
+		limit.Maximum = fallback.Maximum
+	}
+
+	err = validateAmountLimit(limit, transaction.Amount)
+	if err != nil {
+		return err
+	}
+
+	if limit.DailyOutflow == 0 && limit.MonthlyOutflow == 0 {
+		return nil
+	}
+
+	if touch {
+		err = service.LimitTouchUsage(ctx, owner.GetActorID())
+		if err != nil {
+			return err
+		}
+	}
+
+	balanceIDs, err := service.BalanceIDsByOwner(ctx, owner.GetActorID())
+	if err != nil {
+		return err
+	}
+
+	types := domain.OUTFLOW_TRANSACTION_TYPES
+	if limit.TransactionType != domain.LIMIT_ANY {
+		types = []string{limit.TransactionType}
+	}
+
+	if limit.DailyOutflow > 0 {
+		used, err := service.TransactionOutflowSince(ctx, balanceIDs, types, limitWindowStart(LIMIT_DAILY_WINDOW))
+		if err != nil {
+			return err
+		}
+
+		if used+transaction.Amount > limit.DailyOutflow {
+			return utils.ErrorBadRequest(utils.DailyLimitExceeded, "Transaction reach daily limit")
+		}
+	}
+
+	if limit.MonthlyOutflow > 0 {
+		used, err := service.TransactionOutflowSince(ctx, balanceIDs, types, limitWindowStart(LIMIT_MONTHLY_WINDOW))
+		if err != nil {
+			return err
+		}
+
+		if used+transaction.Amount > limit.MonthlyOutflow {
+			return utils.ErrorBadRequest(utils.MonthlyLimitExceeded, "Transaction reach monthly limit")
+		}
+	}
+
+	return nil
+}
+
+// Validate amount and balance ceiling for money coming from outside (topup, card)
+func ValidateInflowLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	balance domain.Balance, transaction domain.Transaction) error {
+	limit, found, err := ResolveLimit(ctx, corporate, owner, transaction.Type)
+	if err != nil || !found {
+		return err
+	}
+
+	err = validateAmountLimit(limit, transaction.SubAmount)
+	if err != nil {
+		return err
+	}
+
+	return validateBalanceCeiling(limit, balance, transaction.SubAmount)
+}
+
+// ValidateInflowLimit inside the commit transaction with the balance read
+// again, the commit write the balance so a concurrent change conflict
+func EnforceInflowLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	balance domain.Balance, transaction domain.Transaction) error {
+	current, err := service.BalanceByID(ctx, balance.ID.Hex())
+	if err != nil {
+		return err
+	}
+
+	return ValidateInflowLimit(ctx, corporate, owner, current, transaction)
+}
+
+// Validate balance ceiling of receiver for balance to balance transaction
+func ValidateBalanceCeiling(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	balance domain.Balance, transaction domain.Transaction) error {
+	limit, found, err := ResolveLimit(ctx, corporate, owner, transaction.Type)
+	if err != nil || !found {
+		return err
+	}
+
+	return validateBalanceCeiling(limit, balance, transaction.SubAmount)
+}
+
+// ValidateBalanceCeiling inside the commit transaction, see EnforceInflowLimit
+func EnforceBalanceCeiling(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble,
+	balance domain.Balance, transaction domain.Transaction) error {
+	current, err := service.BalanceByID(ctx, balance.ID.Hex())
+	if err != nil {
+		return err
+	}
+
+	return ValidateBalanceCeiling(ctx, corporate, owner, current, transaction)
+}
+
+// Rule of the breached limit, amount under minimum is not a breach
+func LimitBreachRule(err error) (string, bool) {
+	customError, ok := err.(utils.CustomError)
+	if !ok {
+		return "", false
+	}
+
+	switch customError.Code {
+	case utils.MaximumAmountTransaction:
+		return domain.FRAUD_RULE_AMOUNT_LIMIT, true
+	case utils.DailyLimitExceeded:
+		return domain.FRAUD_RULE_DAILY_LIMIT, true
+	case utils.MonthlyLimitExceeded:
+		return domain.FRAUD_RULE_MONTHLY_LIMIT, true
+	case utils.BalanceLimitExceeded:
+		return domain.FRAUD_RULE_BALANCE_LIMIT, true
+	}
+
+	return "", false
+}
+
+func SaveLimit(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	limit domain.Limit) (domain.Limit, error) {
+
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_LIMIT_MANAGE)
+	if err != nil {
+		return domain.Limit{}, err
+	}
+
+	if limit.ActorType == "" {
+		limit.ActorType = domain.LIMIT_ANY
+	}
+
+	if limit.Tier == "" {
+		limit.Tier = domain.LIMIT_ANY
+	}
+
+	if limit.TransactionType == "" {
+		limit.TransactionType = domain.LIMIT_ANY
+	}
+
+	if !domain.IsLimitTier(limit.Tier) {
+		return domain.Limit{}, utils.ErrorBadRequest(utils.InvalidLimit, "Unknown tier "+limit.Tier)
+	}
+
+	if limit.Minimum < 0 || limit.Maximum < 0 || limit.DailyOutflow < 0 ||
+		limit.MonthlyOutflow < 0 || limit.MaximumBalance < 0 {
+		return domain.Limit{}, utils.ErrorBadRequest(utils.InvalidLimit, "Limit cannot be negative")
+	}
+
+	if limit.Maximum > 0 && limit.Minimum > limit.Maximum {
+		return domain.Limit{}, utils.ErrorBadRequest(utils.InvalidLimit, "Minimum greater than maximum")
+	}
+
+	limit.CorporateID = corporate.ID
+	limit.Audit.UpdatedTime = domain.TimestampNow()
+
+	if limit.ID.IsZero() {
+		limit.Audit.CreatedTime = limit.Audit.UpdatedTime
+		err = service.LimitSaveOne(ctx, &limit)
+		if err != nil {
+			return domain.Limit{}, err
+		}
+
+		return limit, nil
+	}
+
+	current, err := service.LimitByID(ctx, limit.ID.Hex())
+	if err != nil {
+		return domain.Limit{}, err
+	}
+
+	if current.CorporateID != corporate.ID {
+		return domain.Limit{}, utils.ErrorBadRequest(utils.LimitNotFound, "Limit not in corporate scope")
+	}
+
+	limit.Audit.CreatedTime = current.Audit.CreatedTime
+	err = service.LimitUpdateOne(ctx, &limit)
+	if err != nil {
+		return domain.Limit{}, err
+	}
+
+	return limit, nil
+}
+
+func LimitsByCorporate(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble) ([]domain.Limit, error) {
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_LIMIT_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.LimitsByCorporate(ctx, corporate.ID)
+}
+
+func validateAmountLimit(limit domain.Limit, amount int) error {
+	if limit.Minimum > 0 && amount < limit.Minimum {
+		return utils.ErrorBadRequest(utils.MinimumAmountTransaction, "Transaction under minimum")
+	}
+
+	if limit.Maximum > 0 && amount > limit.Maximum {
+		return utils.ErrorBadRequest(utils.MaximumAmountTransaction, "Transaction reach maximum")
+	}
+
+	return nil
+}
+
+func validateBalanceCeiling(limit domain.Limit, balance domain.Balance, amount int) error {
+	if limit.MaximumBalance > 0 && balance.Amount+amount > limit.MaximumBalance {
+		return utils.ErrorBadRequest(utils.BalanceLimitExceeded, "Balance reach maximum holding")
+	}
+
+	return nil
+}
+
+func defaultOutflowLimit() domain.Limit {
+	minimum, _ := strconv.Atoi(os.Getenv("MINIMUM_TRANSFER_AMOUNT"))
+	maximum, _ := strconv.Atoi(os.Getenv("MAXIMUM_TRANSFER_AMOUNT"))
+
+	return domain.Limit{
+		ActorType:       domain.LIMIT_ANY,
+		Tier:            domain.LIMIT_ANY,
+		TransactionType: domain.LIMIT_ANY,
+		Minimum:         minimum,
+		Maximum:         maximum,
+	}
+}
+
+func limitWindowStart(window time.Duration) time.Time {
+	return time.Now().Add(-window)
+}
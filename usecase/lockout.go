@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+const LOCKOUT_REASON_ADMIN_UNLOCK = "Unlocked by admin"
+
+// Send unlock code to a locked user
+func RequestUserUnlock(ctx context.Context, corporate domain.Corporate, phoneNumber string, OTPChannel string) error {
+
+	var code string
+	var user domain.User
+
+	function := func(session context.Context) error {
+		var err error
+
+		user, err = service.UserByPhoneNumber(session, corporate.ID, phoneNumber)
+		if err != nil {
+			return err
+		}
+
+		err = validateUserUnlockable(user)
+		if err != nil {
+			return err
+		}
+
+		code, err = service.OTPIssue(session, user, domain.OTP_PURPOSE_UNLOCK)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return err
+	}
+
+	go service.OTPSend(context.Background(), corporate, user, OTPChannel, domain.OTP_PURPOSE_UNLOCK, code)
+
+	return nil
+}
+
+// Unlock user with code sent by RequestUserUnlock
+func UserSelfUnlock(ctx context.Context, corporate domain.Corporate, phoneNumber string, code string) error {
+
+	function := func(session context.Context) error {
+		user, err := service.UserByPhoneNumber(session, corporate.ID, phoneNumber)
+		if err != nil {
+			return err
+		}
+
+		err = validateUserUnlockable(user)
+		if err != nil {
+			return err
+		}
+
+		err = service.OTPVerify(session, user, domain.OTP_PURPOSE_UNLOCK, code)
+		if err != nil {
+			return err
+		}
+
+		err = service.UserUnlock(session, &user)
+		if err != nil {
+			return err
+		}
+
+		err = service.LockoutAuditSave(session, domain.CreateLockoutAudit(user.CorporateID, user.ToActorObject(),
+			user.ToActorObject(), domain.LOCKOUT_ACTION_SELF_UNLOCK, domain.LOCKOUT_REASON_UNLOCK_CODE, domain.Timestamp{}))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unlock user of the corporate by admin, reason is kept on audit
+func AdminUnlockUser(ctx context.Context, corporate domain.Corporate, admin domain.ActorAble, userID string,
+	reason string) error {
+	err := ValidatePermission(ctx, corporate, admin, domain.PERMISSION_USER_MANAGE)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		reason = LOCKOUT_REASON_ADMIN_UNLOCK
+	}
+
+	function := func(session context.Context) error {
+		user, err := service.UserByID(session, userID)
+		if err != nil || user.CorporateID != corporate.ID {
+			return utils.ErrorBadRequest(utils.UserNotFound, "User not found")
+		}
+
+		err = validateUserUnlockable(user)
+		if err != nil {
+			return err
+		}
+
+		err = service.UserUnlock(session, &user)
+		if err != nil {
+			return err
+		}
+
+		err = service.LockoutAuditSave(session, domain.CreateLockoutAudit(corporate.ID, user.ToActorObject(),
+			admin.ToActorObject(), domain.LOCKOUT_ACTION_ADMIN_UNLOCK, reason, domain.Timestamp{}))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unlock child corporate by its parent, locked corporate cannot pass the
+// middleware to unlock itself
+func UnlockCorporate(ctx context.Context, parent domain.Corporate, corporateID string, reason string) error {
+	if reason == "" {
+		reason = LOCKOUT_REASON_ADMIN_UNLOCK
+	}
+
+	function := func(session context.Context) error {
+		corporate, err := service.CorporateByID(session, corporateID)
+		if err != nil || corporate.Parent != parent.ID {
+			return utils.ErrorBadRequest(utils.InvalidCorporateKey, "Corporate not found")
+		}
+
+		if !corporate.Lockout.IsActive() && !corporate.IsLegacyLocked() {
+			return utils.ErrorBadRequest(utils.AccountNotLocked, "Corporate not locked")
+		}
+
+		err = service.CorporateUnlock(session, &corporate)
+		if err != nil {
+			return err
+		}
+
+		err = service.LockoutAuditSave(session, domain.CreateLockoutAudit(corporate.ID, corporate.ToActorObject(),
+			parent.ToActorObject(), domain.LOCKOUT_ACTION_ADMIN_UNLOCK, reason, domain.Timestamp{}))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func LockoutAudits(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble, page string,
+	limit string) ([]domain.LockoutAudit, error) {
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_USER_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.LockoutAudits(ctx, corporate.ID, page, limit)
+}
+
+func validateUserUnlockable(user domain.User) error {
+	if user.Lockout.IsActive() || user.IsLegacyLocked() {
+		return nil
+	}
+
+	if user.Active == false {
+		return utils.ErrorBadRequest(utils.AccountInactive, "User not active")
+	}
+
+	return utils.ErrorBadRequest(utils.AccountNotLocked, "User not locked")
+}
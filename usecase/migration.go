@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"os"
+
+	"github.com/takeme-id/core/migration"
+	"github.com/takeme-id/core/utils/database"
+)
+
+// Backfill currency on documents created before money type introduced.
+//
+// Deprecated: applied as a version of migration.Migrations, run cmd/migrate.
+func MigrateMoneyCurrency(ctx context.Context) error {
+	return migration.MoneyCurrency(ctx, database.DBClient.Database(os.Getenv("MONGO_DB_NAME")))
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+const MESSAGE_TEMPLATE_MAX_LENGTH = 320
+
+// Provider order, language and brand name used for message of the corporate
+func UpdateMessagingSetting(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	setting domain.MessagingSetting) (domain.MessagingSetting, error) {
+
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_CORPORATE_MANAGE)
+	if err != nil {
+		return domain.MessagingSetting{}, err
+	}
+
+	seen := map[string]bool{}
+	for _, provider := range setting.Providers {
+		if !utils.IsNotifier(provider) || seen[provider] {
+			return domain.MessagingSetting{}, utils.ErrorBadRequest(utils.InvalidMessagingSetting, "Invalid provider "+provider)
+		}
+
+		seen[provider] = true
+	}
+
+	if setting.Language != "" && !domain.IsLanguage(setting.Language) {
+		return domain.MessagingSetting{}, utils.ErrorBadRequest(utils.InvalidMessagingSetting, "Language not supported")
+	}
+
+	setting.BrandName = strings.TrimSpace(setting.BrandName)
+
+	err = service.CorporateUpdateMessaging(ctx, corporate.ID, setting)
+	if err != nil {
+		return domain.MessagingSetting{}, err
+	}
+
+	return setting, nil
+}
+
+// Template must keep the code placeholder so user still receive the code
+func SaveMessageTemplate(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble, key string,
+	language string, text string) (domain.MessageTemplate, error) {
+
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_CORPORATE_MANAGE)
+	if err != nil {
+		return domain.MessageTemplate{}, err
+	}
+
+	if !domain.IsMessageTemplateKey(key) || !domain.IsLanguage(language) {
+		return domain.MessageTemplate{}, utils.ErrorBadRequest(utils.InvalidMessageTemplate, "Unknown template key or language")
+	}
+
+	text = strings.TrimSpace(text)
+	if !strings.Contains(text, domain.TEMPLATE_CODE_PLACEHOLDER) || len(text) > MESSAGE_TEMPLATE_MAX_LENGTH {
+		return domain.MessageTemplate{}, utils.ErrorBadRequest(utils.InvalidMessageTemplate,
+			"Template must contain "+domain.TEMPLATE_CODE_PLACEHOLDER+" and fit a single message")
+	}
+
+	template := domain.MessageTemplate{
+		CorporateID: corporate.ID,
+		Key:         key,
+		Language:    language,
+		Text:        text,
+		Actor:       actor.ToActorObject(),
+		Time:        domain.TimestampNow(),
+	}
+
+	function := func(session context.Context) error {
+		err := service.MessageTemplateSave(session, &template)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return domain.MessageTemplate{}, err
+	}
+
+	return template, nil
+}
+
+// Every template key and language, default template when corporate has none
+func MessageTemplates(ctx context.Context, corporate domain.Corporate) ([]domain.MessageTemplate, error) {
+	customs, err := service.MessageTemplates(ctx, corporate.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	custom := map[string]domain.MessageTemplate{}
+	for _, template := range customs {
+		custom[template.Language+"/"+template.Key] = template
+	}
+
+	results := []domain.MessageTemplate{}
+	for _, language := range []string{domain.LANGUAGE_ENGLISH, domain.LANGUAGE_INDONESIAN} {
+		for _, key := range []string{domain.OTP_PURPOSE_ACTIVATION, domain.OTP_PURPOSE_LOGIN,
+			domain.OTP_PURPOSE_FORGOT_PIN, domain.OTP_PURPOSE_UNLOCK} {
+
+			template, ok := custom[language+"/"+key]
+			if !ok {
+				template = domain.MessageTemplate{
+					Key:      key,
+					Language: language,
+					Text:     domain.DEFAULT_MESSAGE_TEMPLATES[language][key],
+				}
+			}
+
+			results = append(results, template)
+		}
+	}
+
+	return results, nil
+}
+
+func NotificationLogs(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble, page string,
+	limit string) ([]domain.NotificationLog, error) {
+
+	err := ValidatePermission(ctx, corporate, actor, domain.PERMISSION_CORPORATE_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NotificationLogs(ctx, corporate.ID, page, limit)
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+// Permission of a role is taken from the corporate role document, fall back
+// to the default role permission when corporate does not define it
+func RolePermissions(ctx context.Context, corporate domain.Corporate, roleName string) ([]string, error) {
+	return service.RolePermissions(ctx, corporate.ID, roleName)
+}
+
+// Attach role permission to user so it can be embedded on the JWT
+func ResolvePrivileges(ctx context.Context, corporate domain.Corporate, user *domain.User) error {
+	permissions, err := RolePermissions(ctx, corporate, user.Role)
+	if err != nil {
+		return err
+	}
+
+	user.Privileges = permissions
+	return nil
+}
+
+// Corporate itself authenticated by secret have every permission on its own scope,
+// user is checked against current role so revoked permission apply immediately
+func ValidatePermission(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	permission string) error {
+	if actor.GetActorType() == domain.ACTOR_TYPE_CORPORATE {
+		if actor.GetActorID() == corporate.ID {
+			return nil
+		}
+
+		return utils.ErrorBadRequest(utils.PermissionDenied, "Corporate out of scope")
+	}
+
+	user, ok := actor.(domain.User)
+	if !ok || user.CorporateID != corporate.ID {
+		return utils.ErrorBadRequest(utils.PermissionDenied, "User out of corporate scope")
+	}
+
+	permissions, err := RolePermissions(ctx, corporate, user.Role)
+	if err != nil {
+		return err
+	}
+
+	role := domain.Role{Permissions: permissions}
+	if !role.HasPermission(permission) {
+		return utils.ErrorBadRequest(utils.PermissionDenied, "Permission denied "+permission)
+	}
+
+	return nil
+}
+
+func Roles(ctx context.Context, corporate domain.Corporate) ([]domain.Role, error) {
+	roles, err := service.RolesByCorporate(ctx, corporate.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{domain.ROLE_VIEWER, domain.ROLE_OPERATOR, domain.ROLE_APPROVER, domain.ROLE_ADMIN} {
+		if !containsRole(roles, name) {
+			roles = append(roles, domain.Role{
+				CorporateID: corporate.ID,
+				Name:        name,
+				Permissions: domain.DEFAULT_ROLE_PERMISSIONS[name],
+			})
+		}
+	}
+
+	return roles, nil
+}
+
+// Create or override permission of a role for the corporate
+func SaveRole(ctx context.Context, corporate domain.Corporate, admin domain.ActorAble, name string,
+	permissions []string) (domain.Role, error) {
+
+	err := ValidatePermission(ctx, corporate, admin, domain.PERMISSION_ROLE_MANAGE)
+	if err != nil {
+		return domain.Role{}, err
+	}
+
+	if name == "" {
+		return domain.Role{}, utils.ErrorBadRequest(utils.InvalidRole, "Role name empty")
+	}
+
+	for _, permission := range permissions {
+		if !domain.IsPermission(permission) {
+			return domain.Role{}, utils.ErrorBadRequest(utils.InvalidRole, "Unknown permission "+permission)
+		}
+	}
+
+	role, err := service.RoleByName(ctx, corporate.ID, name)
+	if err != nil {
+		return domain.Role{}, err
+	}
+
+	role.CorporateID = corporate.ID
+	role.Name = name
+	role.Permissions = permissions
+	role.Audit.UpdatedTime = domain.TimestampNow()
+
+	if role.ID.IsZero() {
+		role.Audit.CreatedTime = role.Audit.UpdatedTime
+		err = service.RoleSaveOne(ctx, &role)
+	} else {
+		err = service.RoleUpdateOne(ctx, &role)
+	}
+
+	if err != nil {
+		return domain.Role{}, err
+	}
+
+	return role, nil
+}
+
+func AssignRole(ctx context.Context, corporate domain.Corporate, admin domain.ActorAble, userID string,
+	roleName string) (domain.User, error) {
+
+	err := ValidatePermission(ctx, corporate, admin, domain.PERMISSION_ROLE_MANAGE)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if admin.GetActorID().Hex() == userID {
+		return domain.User{}, utils.ErrorBadRequest(utils.InvalidRole, "Cannot change own role")
+	}
+
+	user, err := service.UserByID(ctx, userID)
+	if err != nil || user.CorporateID != corporate.ID {
+		return domain.User{}, utils.ErrorBadRequest(utils.UserNotFound, "User not found in corporate")
+	}
+
+	if roleName != "" {
+		role, err := service.RoleByName(ctx, corporate.ID, roleName)
+		if err != nil {
+			return domain.User{}, err
+		}
+
+		_, isDefault := domain.DEFAULT_ROLE_PERMISSIONS[roleName]
+		if role.ID.IsZero() && !isDefault {
+			return domain.User{}, utils.ErrorBadRequest(utils.RoleNotFound, "Role not found")
+		}
+	}
+
+	user.Role = roleName
+	user.Audit.UpdatedTime = domain.TimestampNow()
+
+	err = service.UserUpdateRole(ctx, &user)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func containsRole(roles []domain.Role, name string) bool {
+	for _, role := range roles {
+		if role.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
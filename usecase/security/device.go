@@ -0,0 +1,68 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+// Middleware for sensitive action, e.g. transfer, PIN change or device
+// revoke. Beside the corporate signature and user token, request must be
+// signed by the trusted device of the token session.
+func DeviceSignedMiddleware(h http.HandlerFunc, permissions ...string) http.HandlerFunc {
+	return Middleware(func(w http.ResponseWriter, r *http.Request) {
+		data := r.Context().Value("data").(utils.ContextValue)
+		claims := data["claims"].(domain.Claims)
+		user := data["user"].(domain.User)
+
+		device, err := validateDeviceSignature(r, user, claims)
+		if err != nil {
+			utils.ResponseError(err, w, r)
+			return
+		}
+
+		data["device"] = device
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "data", data)))
+	}, true, permissions...)
+}
+
+// deviceSignature header is the device signature of the same canonical request
+// signed by the corporate secret, so timestamp header is required
+func validateDeviceSignature(r *http.Request, user domain.User, claims domain.Claims) (domain.Device, error) {
+	signature := r.Header.Get("deviceSignature")
+	timestamp := r.Header.Get("timestamp")
+	requestID := r.Header.Get("requestID")
+	payload, _ := r.Context().Value("payload").([]byte)
+
+	if signature == "" || timestamp == "" || claims.SessionID == "" {
+		return domain.Device{}, utils.ErrorBadRequest(utils.InvalidDeviceSignature, "Device signature required")
+	}
+
+	session, err := service.SessionByID(r.Context(), claims.SessionID)
+	if err != nil {
+		return domain.Device{}, utils.ErrorUnauthorized()
+	}
+
+	device, found, err := service.DeviceTrusted(r.Context(), user.ID, session.DeviceID)
+	if err != nil {
+		return domain.Device{}, err
+	}
+
+	if !found {
+		return domain.Device{}, utils.ErrorBadRequest(utils.DeviceNotBound, "Device not trusted")
+	}
+
+	err = utils.VerifyDeviceSignature(device.PublicKey, []byte(canonicalRequest(r, timestamp, requestID, payload)), signature)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Invalid device signature from user %v device %v", user.ID.Hex(), device.DeviceID))
+		go InvalidUserAuth(context.Background(), user)
+		return domain.Device{}, err
+	}
+
+	return device, nil
+}
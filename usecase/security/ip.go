@@ -0,0 +1,64 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+)
+
+var (
+	trustedProxies     []*net.IPNet
+	trustedProxiesOnce sync.Once
+)
+
+// TRUSTED_PROXIES is comma separated IP or CIDR of load balancer allowed to set X-Forwarded-For
+func TrustedProxies() []*net.IPNet {
+	trustedProxiesOnce.Do(func() {
+		var err error
+		trustedProxies, err = utils.ParseIPAllowlist(os.Getenv("TRUSTED_PROXIES"))
+		if err != nil {
+			log.Error(fmt.Sprintf("Invalid TRUSTED_PROXIES because %v", err.Error()))
+		}
+	})
+
+	return trustedProxies
+}
+
+// Corporate without allowlist accept every IP
+func validateIPAllowlist(r *http.Request, corporate domain.Corporate) error {
+	if len(utils.SplitIPAllowlist(corporate.WhitelistIP)) == 0 {
+		return nil
+	}
+
+	ip := utils.ClientIP(r, TrustedProxies())
+
+	networks, err := utils.ParseIPAllowlist(corporate.WhitelistIP)
+	if err != nil {
+		log.Error(fmt.Sprintf("Invalid allowlist of corporate %v because %v", corporate.ID.Hex(), err.Error()))
+	}
+
+	if utils.IPInNetworks(ip, networks) {
+		return nil
+	}
+
+	log.Warn(fmt.Sprintf("Blocked IP %v for corporate %v", ip, corporate.ID.Hex()))
+	go recordBlockedIP(context.Background(), corporate, ip.String())
+
+	return utils.ErrorForbidden()
+}
+
+func recordBlockedIP(ctx context.Context, corporate domain.Corporate, ip string) {
+	fraud := domain.CreateIPFraud(corporate, ip)
+	err := service.FraudSave(ctx, &fraud)
+	if err != nil {
+		log.Error(fmt.Sprintf("Record blocked IP failed because %v", err.Error()))
+	}
+}
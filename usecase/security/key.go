@@ -0,0 +1,46 @@
+package security
+
+import (
+	"net/http"
+
+	"github.com/takeme-id/core/utils"
+)
+
+type PublicKeyResponse struct {
+	Current utils.RSAPublicKey   `json:"current"`
+	Keys    []utils.RSAPublicKey `json:"keys"`
+}
+
+// Public key used by client to encrypt PIN, payload is sent as "<kid>.<ciphertext>"
+func PublicKeyHandler(w http.ResponseWriter, r *http.Request) {
+	keyring, err := utils.RSAKeys()
+	if err != nil {
+		utils.ResponseError(utils.ErrorInternalServer(utils.DecryptError, "RSA keyring not loaded"), w, r)
+		return
+	}
+
+	current, err := keyring.CurrentPublicKey()
+	if err != nil {
+		utils.ResponseError(err, w, r)
+		return
+	}
+
+	keys, err := keyring.PublicKeys()
+	if err != nil {
+		utils.ResponseError(err, w, r)
+		return
+	}
+
+	utils.ResponseSuccess(PublicKeyResponse{Current: current, Keys: keys}, w, r)
+}
+
+// JWKS document of the token signing keys, served as is for standard JWT library
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	jwks, err := utils.JWKS()
+	if err != nil {
+		utils.ResponseError(err, w, r)
+		return
+	}
+
+	utils.ResponseSuccessCustom(jwks, w, r)
+}
@@ -3,11 +3,16 @@ package security
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
@@ -15,7 +20,13 @@ import (
 	"github.com/takeme-id/core/utils"
 )
 
-func Middleware(h http.HandlerFunc, secure bool) http.HandlerFunc {
+const SIGNATURE_DEFAULT_CLOCK_SKEW = 300
+
+// Permissions are required route permission, user must hold all of them.
+// Route with permission always need the JWT, the corporate secret alone does
+// not give access to it. Permission is read from the current role of the
+// user so role change apply on the next request.
+func Middleware(h http.HandlerFunc, secure bool, permissions ...string) http.HandlerFunc {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -38,22 +49,33 @@ func Middleware(h http.HandlerFunc, secure bool) http.HandlerFunc {
 			return
 		}
 
-		// If signature invalid reduce access_attempt
+		// Only a wrong secret reduce access_attempt, a replayed requestID or a
+		// stale timestamp is not a guess of the secret
 		err = validateSignature(r, corporate)
 		if err != nil {
-			go InvalidCorporateAuth(corporate)
+			if isInvalidSecret(err) {
+				go InvalidCorporateAuth(context.Background(), corporate)
+			}
 			utils.ResponseError(err, w, r)
 			return
 		}
 
-		if secure == true {
+		// Checked after the signature so unsigned request can not flood the
+		// blocked IP fraud record
+		err = validateIPAllowlist(r, corporate)
+		if err != nil {
+			utils.ResponseError(err, w, r)
+			return
+		}
+
+		if secure == true || len(permissions) > 0 {
 			claims, err = validateJWT(r)
 			if err != nil {
 				utils.ResponseError(err, w, r)
 				return
 			}
 
-			user, err = service.UserByIDWithValidation(claims.SocketID, []func(domain.User) error{
+			user, err = service.UserByIDWithValidation(r.Context(), claims.SocketID, []func(domain.User) error{
 				service.ValidateUserExist,
 				service.ValidateUserLocked,
 			})
@@ -62,6 +84,12 @@ func Middleware(h http.HandlerFunc, secure bool) http.HandlerFunc {
 				utils.ResponseError(err, w, r)
 				return
 			}
+
+			err = validatePermissions(r.Context(), claims, user, corporate, permissions)
+			if err != nil {
+				utils.ResponseError(err, w, r)
+				return
+			}
 		}
 
 		data := utils.ContextValue{
@@ -108,23 +136,120 @@ func logPayloadBaseonLength(payload []byte, requestID string, signature string,
 	}
 }
 
+// Signature is HMAC-SHA512 of
+//
+//	METHOD \n PATH \n timestamp \n requestID \n hex(sha256(body))
+//
+// timestamp is unix seconds and must be inside SIGNATURE_CLOCK_SKEW seconds,
+// requestID can only be used once by a corporate inside that window. Body only
+// signature without timestamp is rejected unless SIGNATURE_LEGACY_ENABLED is true.
 func validateSignature(r *http.Request, corporate domain.Corporate) error {
 
 	// Get secret by corporateID and signature
 	signature := r.Header.Get("signature")
 	requestID := r.Header.Get("requestID")
-	payload := r.Context().Value("payload").([]byte)
+	timestamp := r.Header.Get("timestamp")
+	payload, _ := r.Context().Value("payload").([]byte)
 
 	secretKey := corporate.Secret
-	result := hmacSHA512(payload, []byte(secretKey))
 
+	if timestamp == "" {
+		if os.Getenv("SIGNATURE_LEGACY_ENABLED") != "true" {
+			return utils.ErrorBadRequest(utils.InvalidRequestTimestamp, "Timestamp header required")
+		}
+
+		result := hmacSHA512(payload, []byte(secretKey))
+		logPayloadBaseonLength(payload, requestID, signature, result)
+
+		if !hmac.Equal([]byte(result), []byte(signature)) {
+			return utils.ErrorBadRequest(utils.InvalidCorporateKey, "Invalid secret")
+		}
+
+		return validateRequestID(r.Context(), corporate, requestID, signatureClockSkew())
+	}
+
+	requestTime, err := validateTimestamp(timestamp)
+	if err != nil {
+		return err
+	}
+
+	result := hmacSHA512([]byte(canonicalRequest(r, timestamp, requestID, payload)), []byte(secretKey))
 	logPayloadBaseonLength(payload, requestID, signature, result)
 
-	if result == signature {
-		return nil
+	if !hmac.Equal([]byte(result), []byte(signature)) {
+		return utils.ErrorBadRequest(utils.InvalidCorporateKey, "Invalid secret")
+	}
+
+	// Remember requestID until the timestamp itself leave the window
+	return validateRequestID(r.Context(), corporate, requestID, time.Until(requestTime.Add(signatureClockSkew())))
+}
+
+func isInvalidSecret(err error) bool {
+	customError, ok := err.(utils.CustomError)
+	return ok && customError.Code == utils.InvalidCorporateKey
+}
+
+func canonicalRequest(r *http.Request, timestamp string, requestID string, payload []byte) string {
+	bodyHash := sha256.Sum256(payload)
+
+	return strings.Join([]string{
+		r.Method,
+		r.URL.Path,
+		timestamp,
+		requestID,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+func validateTimestamp(timestamp string) (time.Time, error) {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, utils.ErrorBadRequest(utils.InvalidRequestTimestamp, "Invalid timestamp header")
+	}
+
+	requestTime := time.Unix(unix, 0)
+	skew := time.Since(requestTime)
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > signatureClockSkew() {
+		return time.Time{}, utils.ErrorBadRequest(utils.InvalidRequestTimestamp, "Timestamp outside clock skew window")
+	}
+
+	return requestTime, nil
+}
+
+func validateRequestID(ctx context.Context, corporate domain.Corporate, requestID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = signatureClockSkew()
+	}
+
+	saved, err := service.RequestNonceSave(ctx, &domain.RequestNonce{
+		CorporateID: corporate.ID,
+		RequestID:   requestID,
+		Time:        domain.TimestampNow(),
+		ExpiredAt:   time.Now().Add(ttl),
+	})
+	if err != nil {
+		return err
 	}
 
-	return utils.ErrorBadRequest(utils.InvalidCorporateKey, "Invalid secret")
+	if !saved {
+		log.Warn(fmt.Sprintf("Replayed requestID %v from corporate %v", requestID, corporate.ID.Hex()))
+		return utils.ErrorBadRequest(utils.DuplicateRequestID, "RequestID already used")
+	}
+
+	return nil
+}
+
+func signatureClockSkew() time.Duration {
+	second, err := strconv.Atoi(os.Getenv("SIGNATURE_CLOCK_SKEW"))
+	if err != nil || second <= 0 {
+		second = SIGNATURE_DEFAULT_CLOCK_SKEW
+	}
+
+	return time.Duration(second) * time.Second
 }
 
 func validateJWT(r *http.Request) (domain.Claims, error) {
@@ -140,9 +265,73 @@ func validateJWT(r *http.Request) (domain.Claims, error) {
 		return domain.Claims{}, utils.ErrorUnauthorized()
 	}
 
+	err = validateTokenRevocation(r.Context(), claims)
+	if err != nil {
+		return domain.Claims{}, err
+	}
+
 	return claims, nil
 }
 
+func validatePermissions(ctx context.Context, claims domain.Claims, user domain.User, corporate domain.Corporate,
+	permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	if claims.CorporateID != corporate.ID.Hex() || user.CorporateID != corporate.ID {
+		return utils.ErrorForbidden()
+	}
+
+	// Privileges of the JWT are only a snapshot taken at login
+	privileges, err := service.RolePermissions(ctx, corporate.ID, user.Role)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !hasPrivilege(privileges, permission) {
+			log.Info(fmt.Sprintf("User %v missing permission %v", claims.SocketID, permission))
+			return utils.ErrorForbidden()
+		}
+	}
+
+	return nil
+}
+
+func hasPrivilege(privileges []string, permission string) bool {
+	for _, privilege := range privileges {
+		if privilege == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Token issued before session exist has no jti and sid, it is valid until expired
+func validateTokenRevocation(ctx context.Context, claims domain.Claims) error {
+	if claims.Id != "" {
+		revoked, err := service.IsTokenRevoked(ctx, claims.Id)
+		if err != nil {
+			return err
+		}
+
+		if revoked {
+			return utils.ErrorUnauthorized()
+		}
+	}
+
+	if claims.SessionID != "" {
+		session, err := service.SessionByID(ctx, claims.SessionID)
+		if err != nil || session.Revoked || session.UserID.Hex() != claims.SocketID {
+			return utils.ErrorUnauthorized()
+		}
+	}
+
+	return nil
+}
+
 func hmacSHA512(data, secret []byte) string {
 
 	// Create a new HMAC by defining the hash type and the key (as byte array)
@@ -181,6 +370,12 @@ func MiddlewareWithoutSignature(h http.HandlerFunc, secure bool) http.HandlerFun
 			return
 		}
 
+		err = validateIPAllowlist(r, corporate)
+		if err != nil {
+			utils.ResponseError(err, w, r)
+			return
+		}
+
 		data := utils.ContextValue{
 			"claims":    claims,
 			"userID":    claims.SocketID,
@@ -7,123 +7,77 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
-	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
-func InvalidCorporateAuth(corporate domain.Corporate) {
-
-	transactionFunction := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+func InvalidCorporateAuth(ctx context.Context, corporate domain.Corporate) {
 
+	transactionFunction := func(session context.Context) error {
+		locked, err := service.CorporateReduceAccessAttempt(session, corporate.ID.Hex())
 		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Invalid corporate auth start transaction")
+			return err
 		}
 
-		err = service.CorporateReduceAccessAttempt(corporate.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
+		if locked {
+			log.Warn(fmt.Sprintf("Corporate %v locked after too many failed attempt", corporate.ID.Hex()))
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, transactionFunction)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, transactionFunction)
 
 	if err != nil {
 		log.Error(fmt.Sprintf("Invalid corporate auth failed because %v ", err.Error()))
 	}
 }
 
-func InvalidUserAuth(user domain.User) {
-	transactionFunction := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
+func InvalidUserAuth(ctx context.Context, user domain.User) {
+	transactionFunction := func(session context.Context) error {
+		locked, err := service.UserReduceAccessAttempt(session, user.ID.Hex())
 		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Invalid corporate auth start transaction")
+			return err
 		}
 
-		err = service.UserReduceAccessAttempt(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
+		if locked {
+			log.Warn(fmt.Sprintf("User %v locked after too many failed attempt", user.ID.Hex()))
 		}
 
 		fraud := domain.CreateFraud(domain.USER_FAILED_ATTEMPT, user, domain.USER_COLLECTION)
-		err = service.FraudSave(fraud, session)
+		err = service.FraudSave(session, &fraud)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, transactionFunction)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, transactionFunction)
 
 	if err != nil {
 		log.Error(fmt.Sprintf("Invalid corporate auth failed because %v ", err.Error()))
 	}
 }
 
-func LockUser(userID string) {
-
-	transactionFunction := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Invalid lock user start transaction")
-		}
+func LockUser(ctx context.Context, userID string) {
 
-		user, err := service.UserByID(userID, session)
+	transactionFunction := func(session context.Context) error {
+		user, err := service.UserByID(session, userID)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.UserLock(&user, session)
+		err = service.UserLock(session, &user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, transactionFunction)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, transactionFunction)
 
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed lock user with id  %v ", userID))
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Access token is short lived, corporate TokenExpired can only make it shorter.
+// Refresh token is "<session id>.<secret>" and rotated on every refresh.
+const (
+	SESSION_DEFAULT_ACCESS_MINUTE = 15
+	SESSION_DEFAULT_REFRESH_HOUR  = 30 * 24
+	SESSION_REFRESH_TOKEN_LENGTH  = 32
+	SESSION_TOKEN_SEPARATOR       = "."
+)
+
+// Create session for a successful login inside the login transaction
+func CreateUserSession(ctx context.Context, user domain.User, corporate domain.Corporate, deviceID string) (domain.AuthToken, error) {
+
+	now := time.Now()
+	userSession := domain.Session{
+		ID:              primitive.NewObjectID(),
+		UserID:          user.ID,
+		CorporateID:     corporate.ID,
+		DeviceID:        deviceID,
+		CreatedTime:     domain.NewTimestamp(now),
+		LastRefreshTime: domain.NewTimestamp(now),
+		ExpiredTime:     domain.NewTimestamp(now.Add(refreshTokenDuration())),
+	}
+
+	refreshToken, err := rotateRefreshToken(&userSession)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	accessToken, expiresIn, err := issueAccessToken(user, corporate, &userSession)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	err = service.SessionSaveOne(ctx, &userSession)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	return domain.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    expiresIn,
+		SessionID:    userSession.ID.Hex(),
+	}, nil
+}
+
+// Exchange refresh token with a new access and refresh token. Refresh token
+// used twice mean it was leaked, the whole session is revoked.
+func RefreshUserSession(ctx context.Context, corporate domain.Corporate,
+	refreshToken string) (domain.AuthToken, error) {
+	parts := strings.SplitN(refreshToken, SESSION_TOKEN_SEPARATOR, 2)
+	if len(parts) != 2 {
+		return domain.AuthToken{}, utils.ErrorUnauthorized()
+	}
+
+	userSession, err := service.SessionByID(ctx, parts[0])
+	if err != nil || userSession.CorporateID != corporate.ID || userSession.Revoked ||
+		isSessionTimePassed(userSession.ExpiredTime) {
+		return domain.AuthToken{}, utils.ErrorUnauthorized()
+	}
+
+	hash := utils.HashToken(parts[1])
+	if userSession.PreviousRefreshTokenHash != "" && hash == userSession.PreviousRefreshTokenHash {
+		log.Warn(fmt.Sprintf("Refresh token reuse on session %v, session revoked", userSession.ID.Hex()))
+		revokeSession(ctx, userSession)
+		return domain.AuthToken{}, utils.ErrorUnauthorized()
+	}
+
+	if hash != userSession.RefreshTokenHash {
+		return domain.AuthToken{}, utils.ErrorUnauthorized()
+	}
+
+	user, err := service.UserByIDWithValidation(ctx, userSession.UserID.Hex(), []func(domain.User) error{
+		service.ValidateUserExist,
+		service.ValidateUserLocked,
+	})
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	err = ResolvePrivileges(ctx, corporate, &user)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	previousTokenID := userSession.TokenID
+	previousTokenExpired := userSession.TokenExpiredTime
+
+	newRefreshToken, err := rotateRefreshToken(&userSession)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	accessToken, expiresIn, err := issueAccessToken(user, corporate, &userSession)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	userSession.LastRefreshTime = domain.TimestampNow()
+	rotated, err := service.SessionRotate(ctx, &userSession, hash)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	if !rotated {
+		return domain.AuthToken{}, utils.ErrorUnauthorized()
+	}
+
+	revokeToken(ctx, previousTokenID, previousTokenExpired)
+
+	return domain.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    expiresIn,
+		SessionID:    userSession.ID.Hex(),
+	}, nil
+}
+
+func UserLogout(ctx context.Context, user domain.User, claims domain.Claims) error {
+	userSession, err := service.SessionByID(ctx, claims.SessionID)
+	if err != nil {
+		return err
+	}
+
+	if userSession.UserID != user.ID {
+		return utils.ErrorBadRequest(utils.SessionNotFound, "Session not owned by user")
+	}
+
+	revokeSession(ctx, userSession)
+	if claims.Id != userSession.TokenID {
+		revokeToken(ctx, claims.Id, domain.NewTimestamp(time.Unix(claims.ExpiresAt, 0)))
+	}
+
+	return nil
+}
+
+func UserLogoutAll(ctx context.Context, user domain.User) error {
+	sessions, err := service.SessionsActiveByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, userSession := range sessions {
+		revokeSession(ctx, userSession)
+	}
+
+	return nil
+}
+
+// Revoke a single device session, e.g. lost phone
+func UserRevokeSession(ctx context.Context, user domain.User, sessionID string) error {
+	userSession, err := service.SessionByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if userSession.UserID != user.ID {
+		return utils.ErrorBadRequest(utils.SessionNotFound, "Session not owned by user")
+	}
+
+	revokeSession(ctx, userSession)
+	return nil
+}
+
+func UserSessions(ctx context.Context, user domain.User, claims domain.Claims) ([]domain.Session, error) {
+	sessions, err := service.SessionsActiveByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range sessions {
+		sessions[index].Current = sessions[index].ID.Hex() == claims.SessionID
+	}
+
+	return sessions, nil
+}
+
+func issueAccessToken(user domain.User, corporate domain.Corporate, userSession *domain.Session) (string, int, error) {
+	tokenID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return "", 0, err
+	}
+
+	duration := accessTokenDuration(corporate)
+	expiredTime := time.Now().Add(duration)
+
+	accessToken, err := utils.JWTEncode(user, corporate, userSession.ID.Hex(), tokenID, expiredTime)
+	if err != nil {
+		return "", 0, err
+	}
+
+	userSession.TokenID = tokenID
+	userSession.TokenExpiredTime = domain.NewTimestamp(expiredTime)
+
+	return accessToken, int(duration.Seconds()), nil
+}
+
+func rotateRefreshToken(userSession *domain.Session) (string, error) {
+	secret, err := utils.GenerateSecureToken(SESSION_REFRESH_TOKEN_LENGTH)
+	if err != nil {
+		return "", err
+	}
+
+	userSession.PreviousRefreshTokenHash = userSession.RefreshTokenHash
+	userSession.RefreshTokenHash = utils.HashToken(secret)
+
+	return userSession.ID.Hex() + SESSION_TOKEN_SEPARATOR + secret, nil
+}
+
+func revokeSession(ctx context.Context, userSession domain.Session) {
+	userSession.RevokedTime = domain.TimestampNow()
+	err := service.SessionRevoke(ctx, &userSession)
+	if err != nil {
+		log.Error(fmt.Sprintf("Revoke session %v failed because %v", userSession.ID.Hex(), err.Error()))
+	}
+
+	revokeToken(ctx, userSession.TokenID, userSession.TokenExpiredTime)
+}
+
+func revokeToken(ctx context.Context, tokenID string, expiredTime domain.Timestamp) {
+	if tokenID == "" || isSessionTimePassed(expiredTime) {
+		return
+	}
+
+	err := service.RevokedTokenSave(ctx, &domain.RevokedToken{
+		TokenID:     tokenID,
+		ExpiredTime: expiredTime,
+		Time:        domain.TimestampNow(),
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("Revoke token failed because %v", err.Error()))
+	}
+}
+
+func accessTokenDuration(corporate domain.Corporate) time.Duration {
+	minute, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_EXPIRED"))
+	if err != nil || minute <= 0 {
+		minute = SESSION_DEFAULT_ACCESS_MINUTE
+	}
+
+	if corporate.TokenExpired > 0 && corporate.TokenExpired < minute {
+		minute = corporate.TokenExpired
+	}
+
+	return time.Duration(minute) * time.Minute
+}
+
+func refreshTokenDuration() time.Duration {
+	hour, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_EXPIRED"))
+	if err != nil || hour <= 0 {
+		hour = SESSION_DEFAULT_REFRESH_HOUR
+	}
+
+	return time.Duration(hour) * time.Hour
+}
+
+func isSessionTimePassed(value domain.Timestamp) bool {
+	return value.IsZero() || time.Now().After(value.Time)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/takeme-id/core/service"
+)
+
+const (
+	SUB_BALANCE_SWEEP_BATCH            = 100
+	SUB_BALANCE_SWEEP_DEFAULT_INTERVAL = time.Minute
+)
+
+// Move every sub balance holding an amount back into its balance, return how
+// many were moved. Each one is moved in its own transaction so a sub balance
+// being deposited to only delay itself, it is moved on the next sweep.
+func ConsolidateSubBalances(ctx context.Context) (int, error) {
+	moved := 0
+	for ctx.Err() == nil {
+		subBalances, err := service.SubBalancesWithAmount(ctx, SUB_BALANCE_SWEEP_BATCH)
+		if err != nil {
+			return moved, err
+		}
+
+		consolidated := 0
+		for _, subBalance := range subBalances {
+			err = service.SubBalanceConsolidate(ctx, subBalance)
+			if err != nil {
+				log.Error(fmt.Sprintf("Consolidate sub balance %v failed because %v", subBalance.ID.Hex(), err.Error()))
+				continue
+			}
+
+			consolidated++
+		}
+
+		moved += consolidated
+		if len(subBalances) < SUB_BALANCE_SWEEP_BATCH || consolidated == 0 {
+			break
+		}
+	}
+
+	return moved, ctx.Err()
+}
+
+// Sweep the sub balances every SUB_BALANCE_SWEEP_SECOND until ctx is done,
+// started once by the application
+func RunSubBalanceSweep(ctx context.Context) {
+	interval := SUB_BALANCE_SWEEP_DEFAULT_INTERVAL
+	second, err := strconv.Atoi(os.Getenv("SUB_BALANCE_SWEEP_SECOND"))
+	if err == nil && second > 0 {
+		interval = time.Duration(second) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := ConsolidateSubBalances(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error(fmt.Sprintf("Sub balance sweep failed because %v", err.Error()))
+			}
+		}
+	}
+}
@@ -1,9 +1,7 @@
 package acceptpayment
 
 import (
-	"os"
-	"strconv"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -24,12 +22,27 @@ type AcceptCard struct {
 	transactionUsecase transaction.Base
 }
 
-func (self AcceptCard) Initialize(from domain.Card, balanceID string, amount int,
+func (self AcceptCard) Initialize(ctx context.Context, from domain.Card, balanceID string, amount int,
 	reference string, currency string, returnURL string, externalID string) (string, string, error) {
 
+	balance, _, corporate, err := identifyBalance(ctx, balanceID)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = validateCurrency(currency, balance)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = validateLimit(ctx, corporate, balance, amount)
+	if err != nil {
+		return "", "", err
+	}
+
 	gateway := gateway.StripeGateway{}
 
-	status, authURL, err := gateway.ChargeCard(balanceID, amount, returnURL, from, externalID)
+	status, authURL, err := gateway.ChargeCard(ctx, balanceID, domain.NewMoney(amount, currency), returnURL, from, externalID)
 	if err != nil {
 		return "", "", err
 	}
@@ -37,12 +50,27 @@ func (self AcceptCard) Initialize(from domain.Card, balanceID string, amount int
 	return status, authURL, nil
 }
 
-func (self AcceptCard) InitializeSubscribe(from domain.Card, balanceID string, amount int,
+func (self AcceptCard) InitializeSubscribe(ctx context.Context, from domain.Card, balanceID string, amount int,
 	reference string, currency string, returnURL string, externalID string, interval string) (string, string, string, error) {
 
+	balance, _, corporate, err := identifyBalance(ctx, balanceID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	err = validateCurrency(currency, balance)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	err = validateLimit(ctx, corporate, balance, amount)
+	if err != nil {
+		return "", "", "", err
+	}
+
 	gateway := gateway.StripeGateway{}
 
-	status, authURL, subsID, err := gateway.ChargeCardSubscribe(balanceID, amount, returnURL, from, externalID, interval)
+	status, authURL, subsID, err := gateway.ChargeCardSubscribe(ctx, balanceID, domain.NewMoney(amount, currency), returnURL, from, externalID, interval)
 	if err != nil {
 		return "", "", subsID, err
 	}
@@ -50,10 +78,10 @@ func (self AcceptCard) InitializeSubscribe(from domain.Card, balanceID string, a
 	return status, authURL, subsID, nil
 }
 
-func (self AcceptCard) Execute(from domain.Card, balanceID string, amount int,
+func (self AcceptCard) Execute(ctx context.Context, from domain.Card, balanceID string, amount int,
 	reference string, currency string, externalID string) (domain.Transaction, domain.Balance, error) {
 
-	balance, owner, corporate, err := identifyBalance(balanceID)
+	balance, owner, corporate, err := identifyBalance(ctx, balanceID)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
@@ -76,7 +104,7 @@ func (self AcceptCard) Execute(from domain.Card, balanceID string, amount int,
 		return domain.Transaction{}, domain.Balance{}, err
 	}
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, balance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, balance, transaction)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
@@ -89,7 +117,7 @@ func (self AcceptCard) Execute(from domain.Card, balanceID string, amount int,
 		return domain.Transaction{}, domain.Balance{}, err
 	}
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
@@ -99,8 +127,9 @@ func (self AcceptCard) Execute(from domain.Card, balanceID string, amount int,
 	return transaction, balance, nil
 }
 
-func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject, domain.Corporate, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, domain.TransactionObject,
+	domain.Corporate, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 			utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
@@ -112,7 +141,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 	balanceOwnerType := balance.Owner.Type
 
 	if balanceOwnerType == domain.ACTOR_TYPE_CORPORATE {
-		corporate, err := service.CorporateByIDNoSession(ownerID)
+		corporate, err := service.CorporateByID(ctx, ownerID)
 		if err != nil {
 			return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 				utils.ErrorBadRequest(utils.CorporateNotFound, "Corporate id not found")
@@ -120,7 +149,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 
 		balanceOwner = corporate.ToTransactionObject()
 	} else {
-		user, err := service.UserByIDNoSession(ownerID)
+		user, err := service.UserByID(ctx, ownerID)
 		if err != nil {
 			return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 				utils.ErrorBadRequest(utils.UserNotFound, "User id not found")
@@ -129,7 +158,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 		balanceOwner = user.ToTransactionObject()
 	}
 
-	corporate, err := service.CorporateByIDNoSession(corporateID)
+	corporate, err := service.CorporateByID(ctx, corporateID)
 	if err != nil {
 		return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 			utils.ErrorBadRequest(utils.CorporateNotFound, "Corporate id not found")
@@ -141,22 +170,15 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 func createTransaction(corporate domain.Corporate, balance domain.Balance, from domain.Card,
 	to domain.TransactionObject, subAmount int, reference string, gateway gateway.Gateway, externalID string) (domain.Transaction, domain.Statement, error) {
 
-	var totalFee = 0
+	fee := corporate.FeeCorporate
 	if balance.Owner.Type == domain.ACTOR_TYPE_USER {
+		fee = corporate.FeeUser
+	}
 
-		s, err := strconv.ParseFloat(corporate.FeeUser.AcceptPaymentCard, 64)
-		if err != nil {
-			return domain.Transaction{}, domain.Statement{}, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
-		}
-
-		totalFee = int(float64(subAmount) * s)
-	} else {
-		s, err := strconv.ParseFloat(corporate.FeeCorporate.AcceptPaymentCard, 64)
-		if err != nil {
-			return domain.Transaction{}, domain.Statement{}, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
-		}
-
-		totalFee = int(float64(subAmount) * s)
+	subAmountMoney := domain.NewMoney(subAmount, balance.Currency)
+	totalFee, err := subAmountMoney.Percentage(fee.AcceptPaymentCard, fee.Rounding)
+	if err != nil {
+		return domain.Transaction{}, domain.Statement{}, utils.ErrorBadRequest(utils.WrongAcceptCardFee, "Cannot convert accept payment card fee")
 	}
 
 	transcation := domain.Transaction{
@@ -168,21 +190,21 @@ func createTransaction(corporate domain.Corporate, balance domain.Balance, from
 		FromBalanceID:    balance.ID,
 		From:             from.ToTransactionObject(),
 		To:               to,
-		TotalFee:         totalFee,
+		TotalFee:         totalFee.Amount,
 		SubAmount:        subAmount,
-		Amount:           subAmount - totalFee,
-		Time:             time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Amount:           subAmount - totalFee.Amount,
+		Time:             domain.TimestampNow(),
 		Notes:            "",
 		Status:           domain.COMPLETED_STATUS,
 		Unpaid:           false,
 		ExternalID:       externalID,
 		Gateway:          gateway.Name(),
 		GatewayReference: reference,
-		Currency:         corporate.Currency,
+		Currency:         subAmountMoney.Currency,
 	}
 
 	statement := service.DepositTransactionStatement(
-		balance.ID, transcation.Time, transcation.TransactionCode, subAmount)
+		balance.ID, transcation.Time, transcation.TransactionCode, domain.NewMoney(subAmount, transcation.Currency))
 
 	return transcation, statement, nil
 }
@@ -1,13 +1,14 @@
 package acceptpayment
 
 import (
+	"context"
 	"github.com/takeme-id/core/utils/gateway"
 )
 
-func CancelSubscribe(subscribeCode string) error {
+func CancelSubscribe(ctx context.Context, subscribeCode string) error {
 	gateway := gateway.StripeGateway{}
 
-	err := gateway.CancelSubscribe(subscribeCode)
+	err := gateway.CancelSubscribe(ctx, subscribeCode)
 	if err != nil {
 		return err
 	}
@@ -1,14 +1,38 @@
 package acceptpayment
 
 import (
+	"context"
 	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/usecase"
 	"github.com/takeme-id/core/utils"
 )
 
+// Card is charged in the currency given, it must be known so the gateway get
+// the amount in the right unit
 func validateCurrency(incomeCurrency string, balance domain.Balance) error {
-	if incomeCurrency != balance.Currency {
+	if !domain.IsSupportedCurrency(incomeCurrency) {
+		return utils.ErrorBadRequest(utils.CurrencyError, "Currency not supported")
+	}
+
+	if domain.NormalizeCurrency(incomeCurrency) != domain.NormalizeCurrency(balance.Currency) {
 		return utils.ErrorBadRequest(utils.CurrencyError, "Transaction cross currency")
 	}
 
 	return nil
 }
+
+// Checked before card is charged, money already captured on Execute
+func validateLimit(ctx context.Context, corporate domain.Corporate, balance domain.Balance, amount int) error {
+	owner, err := usecase.ActorObjectToActor(ctx, balance.Owner)
+	if err != nil {
+		return err
+	}
+
+	transaction := domain.Transaction{
+		Type:      domain.ACCEPT_PAYMENT_CARD,
+		SubAmount: amount,
+		Currency:  balance.Currency,
+	}
+
+	return usecase.ValidateInflowLimit(ctx, corporate, owner, balance, transaction)
+}
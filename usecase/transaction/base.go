@@ -2,28 +2,23 @@ package transaction
 
 import (
 	"context"
+	"strings"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/usecase"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 type Base struct {
 }
 
-func (self Base) CreateFeeStatement(corporate domain.Corporate, balance domain.Balance,
+func (self Base) CreateFeeStatement(ctx context.Context, corporate domain.Corporate, balance domain.Balance,
 	transaction domain.Transaction) ([]domain.Statement, error) {
 	feeCalculator := usecase.CalculateFee{}
 	feeCalculator.Initialize(corporate, balance, transaction)
 
-	statements, err := feeCalculator.CalculateByOwnerAndTransaction()
+	statements, err := feeCalculator.CalculateByOwnerAndTransaction(ctx)
 	if err != nil {
 		return []domain.Statement{}, err
 	}
@@ -31,12 +26,12 @@ func (self Base) CreateFeeStatement(corporate domain.Corporate, balance domain.B
 	return statements, nil
 }
 
-func (self Base) RollbackFeeStatement(corporate domain.Corporate, balance domain.Balance,
+func (self Base) RollbackFeeStatement(ctx context.Context, corporate domain.Corporate, balance domain.Balance,
 	transaction domain.Transaction) ([]domain.Statement, error) {
 	feeCalculator := usecase.CalculateFee{}
 	feeCalculator.Initialize(corporate, balance, transaction)
 
-	feeStatements, err := feeCalculator.CalculateByOwnerAndTransaction()
+	feeStatements, err := feeCalculator.CalculateByOwnerAndTransaction(ctx)
 	statements := feeCalculator.RollbackFeeStatement(feeStatements)
 
 	if err != nil {
@@ -46,40 +41,115 @@ func (self Base) RollbackFeeStatement(corporate domain.Corporate, balance domain
 	return statements, nil
 }
 
-func (self Base) Commit(statements []domain.Statement, transaction *domain.Transaction) error {
-	function := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+// Must be called before Commit for transaction which take money out of balance
+func (self Base) EvaluateFraud(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	balance domain.Balance, transaction *domain.Transaction) error {
+	fraudDetection := usecase.FraudDetection{}
+	fraudDetection.Initialize(corporate, actor, balance, transaction)
 
-		if err != nil {
-			session.AbortTransaction(session)
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
+	decision, err := fraudDetection.Evaluate(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Transaction still committed but only reserve fund until reviewed
+	if decision == domain.FRAUD_DECISION_REVIEW {
+		holdTransaction(transaction, actor, fraudDetection.Rules())
+	}
+
+	return nil
+}
+
+// Limit breach hold the transaction for review instead of rejecting it,
+// other error is returned as is
+func (self Base) HoldOnLimit(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	transaction *domain.Transaction, err error) error {
+	rule, ok := usecase.LimitBreachRule(err)
+	if !ok {
+		return err
+	}
+
+	holdTransaction(transaction, actor, []string{rule})
+
+	fraud := domain.CreateFraudDecision(corporate.ID, actor, *transaction, transaction.RiskScore,
+		[]string{rule}, domain.FRAUD_DECISION_REVIEW)
+	return service.FraudSave(ctx, &fraud)
+}
+
+// Check of Commit with limit breached by a concurrent transaction hold the
+// transaction too, already held transaction stay held
+func (self Base) HoldOnBreach(corporate domain.Corporate, actor domain.ActorAble, transaction *domain.Transaction,
+	check func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := check(ctx)
+		if _, ok := usecase.LimitBreachRule(err); ok && transaction.Status == domain.HELD_STATUS {
+			return nil
+		}
+
+		return self.HoldOnLimit(ctx, corporate, actor, transaction, err)
+	}
+}
+
+func holdTransaction(transaction *domain.Transaction, actor domain.ActorAble, rules []string) {
+	reason := strings.Join(rules, ",")
+	if transaction.HoldReason != "" {
+		transaction.HoldReason += ","
+	}
+
+	transaction.Status = domain.HELD_STATUS
+	transaction.HoldReason += reason
+	transaction.ReviewHistories = append(transaction.ReviewHistories, domain.ReviewHistory{
+		Action: domain.REVIEW_ACTION_HELD,
+		Actor:  actor.ToActorObject(),
+		Reason: reason,
+		Time:   domain.TimestampNow(),
+	})
+}
+
+// Checks run inside the transaction before anything is written, usually the
+// Enforce limit of usecase, an error abort the commit
+func (self Base) Commit(ctx context.Context, statements []domain.Statement, transaction *domain.Transaction,
+	checks ...func(ctx context.Context) error) error {
+	err := validateStatementCurrency(statements, *transaction)
+	if err != nil {
+		return err
+	}
+
+	// Check may hold the transaction, every attempt start from the original
+	original := *transaction
+
+	function := func(session context.Context) error {
+		*transaction = original
+
+		for _, check := range checks {
+			err := check(session)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+
+		if transaction.Status == domain.HELD_STATUS {
+			err = reserveBalanceWithStatement(session, statements, transaction)
+		} else {
+			err = adjustBalanceWithStatement(session, statements)
 		}
 
-		err = adjustBalanceWithStatement(statements, session)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.TransactionSaveOne(transaction, session)
+		err = service.TransactionSaveOne(session, transaction)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, function)
-		},
-	)
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
 
 	if err != nil {
 		return err
@@ -88,34 +158,78 @@ func (self Base) Commit(statements []domain.Statement, transaction *domain.Trans
 	return nil
 }
 
-func (self Base) CommitRollback(statements []domain.Statement) error {
-	function := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+// Every amount of the transaction is in its currency, so must be every
+// statement it write
+func validateStatementCurrency(statements []domain.Statement, transaction domain.Transaction) error {
+	if !domain.IsSupportedCurrency(transaction.Currency) {
+		return utils.ErrorBadRequest(utils.CurrencyError, "Transaction currency not supported")
+	}
+
+	for _, statement := range statements {
+		if domain.NormalizeCurrency(statement.Currency) != domain.NormalizeCurrency(transaction.Currency) {
+			return utils.ErrorBadRequest(utils.CurrencyError, "Statement currency not match with transaction")
+		}
+	}
+
+	return nil
+}
+
+// Approve apply the held deposit, reject give back reserved fund to its balance
+func (self Base) CommitReview(ctx context.Context, transaction *domain.Transaction, approve bool,
+	reviewer domain.ActorAble, reason string) error {
+	function := func(session context.Context) error {
+		current, err := service.TransactionByID(session, transaction.ID.Hex())
+		if err != nil {
+			return err
+		}
+
+		if current.Status != domain.HELD_STATUS {
+			return utils.ErrorBadRequest(utils.TransactionNotHeld, "Transaction is not held")
+		}
+
+		action := domain.REVIEW_ACTION_APPROVED
+		if approve {
+			err = adjustBalanceWithStatement(session, current.HeldStatements)
+			current.Status = domain.COMPLETED_STATUS
+			if current.Type == domain.TRANSFER_BANK {
+				current.Status = domain.PENDING_STATUS
+			}
+		} else {
+			action = domain.REVIEW_ACTION_REJECTED
+			err = adjustBalanceWithStatement(session, releaseStatements(current.ReservedStatements))
+			current.Status = domain.REJECTED_STATUS
+		}
+
+		if err != nil {
+			return err
+		}
+
+		current.ReservedStatements = nil
+		current.HeldStatements = nil
+		current.ReviewHistories = append(current.ReviewHistories, domain.ReviewHistory{
+			Action: action,
+			Actor:  reviewer.ToActorObject(),
+			Reason: reason,
+			Time:   domain.TimestampNow(),
+		})
 
+		err = service.TransactionUpdateOne(session, &current)
 		if err != nil {
-			session.AbortTransaction(session)
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize balance start transaction failed")
+			return err
 		}
 
-		err = adjustBalanceWithStatement(statements, session)
+		// Fraud case close together with the review so it never stay open on a decided transaction
+		err = service.FraudCloseReview(session, current.TransactionCode, reviewer.ToActorObject(), action)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		*transaction = current
 
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, function)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
 
 	if err != nil {
 		return err
@@ -124,16 +238,36 @@ func (self Base) CommitRollback(statements []domain.Statement) error {
 	return nil
 }
 
-func adjustBalanceWithStatement(statements []domain.Statement, session mongo.SessionContext) error {
+func (self Base) CommitRollback(ctx context.Context, statements []domain.Statement) error {
+	function := func(session context.Context) error {
+		err := adjustBalanceWithStatement(session, statements)
+		if err != nil {
+			return err
+		}
+
+		return nil
+
+	}
+
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func adjustBalanceWithStatement(ctx context.Context, statements []domain.Statement) error {
 
 	for _, statement := range statements {
 		if statement.Deposit != 0 {
-			err := usecase.DepositBalance(statement, session)
+			err := usecase.DepositBalance(ctx, statement)
 			if err != nil {
 				return err
 			}
 		} else if statement.Withdraw != 0 {
-			err := usecase.WithdrawBalance(statement, session)
+			err := usecase.WithdrawBalance(ctx, statement)
 			if err != nil {
 				return err
 			}
@@ -142,3 +276,42 @@ func adjustBalanceWithStatement(statements []domain.Statement, session mongo.Ses
 
 	return nil
 }
+
+func reserveBalanceWithStatement(ctx context.Context, statements []domain.Statement, transaction *domain.Transaction) error {
+	var reserved []domain.Statement
+	var held []domain.Statement
+
+	for _, statement := range statements {
+		if statement.Withdraw != 0 {
+			reserved = append(reserved, statement)
+		} else if statement.Deposit != 0 {
+			held = append(held, statement)
+		}
+	}
+
+	err := adjustBalanceWithStatement(ctx, reserved)
+	if err != nil {
+		return err
+	}
+
+	transaction.ReservedStatements = reserved
+	transaction.HeldStatements = held
+
+	return nil
+}
+
+func releaseStatements(statements []domain.Statement) []domain.Statement {
+	var result []domain.Statement
+	for _, statement := range statements {
+		amount := statement.WithdrawMoney()
+		if statement.Type == domain.STATEMENT_TYPE_FEE {
+			result = append(result, service.DepositFeeStatement(statement.BalanceID, domain.TimestampNow(),
+				statement.Reference, amount))
+		} else {
+			result = append(result, service.DepositTransactionStatement(statement.BalanceID, domain.TimestampNow(),
+				statement.Reference, amount))
+		}
+	}
+
+	return result
+}
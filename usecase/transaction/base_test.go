@@ -0,0 +1,334 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func useMemory(t *testing.T) {
+	service.UseRepositories(repository.NewMemory())
+	t.Cleanup(func() {
+		service.UseRepositories(nil)
+	})
+}
+
+func saveBalance(t *testing.T, amount int) domain.Balance {
+	ctx := context.Background()
+	balance, err := service.BalanceCreate(ctx, primitive.NewObjectID(), domain.ActorObject{
+		ID:   primitive.NewObjectID(),
+		Type: domain.USER_COLLECTION,
+	}, "Main", domain.CURRENCY_IDR)
+	if err != nil {
+		t.Fatalf("create balance: %v", err)
+	}
+
+	if amount > 0 {
+		_, err = service.BalanceAdjust(ctx, balance.ID, amount, domain.CURRENCY_IDR)
+		if err != nil {
+			t.Fatalf("fund balance: %v", err)
+		}
+	}
+
+	return balance
+}
+
+func balanceAmount(t *testing.T, balance domain.Balance) int {
+	model, err := service.Repositories().Balance.FindByID(context.Background(), balance.ID)
+	if err != nil {
+		t.Fatalf("find balance: %v", err)
+	}
+
+	return model.Amount
+}
+
+func transferStatements(from domain.Balance, to domain.Balance, code string, amount int) []domain.Statement {
+	money := domain.NewMoney(amount, domain.CURRENCY_IDR)
+
+	return []domain.Statement{
+		service.WithdrawTransactionStatement(from.ID, domain.TimestampNow(), code, money),
+		service.DepositTransactionStatement(to.ID, domain.TimestampNow(), code, money),
+	}
+}
+
+func transferTransaction(from domain.Balance, to domain.Balance, code string, amount int,
+	status string) *domain.Transaction {
+	return &domain.Transaction{
+		TransactionCode: code,
+		Type:            domain.TRANSFER_WALLET,
+		FromBalanceID:   from.ID,
+		ToBalanceID:     to.ID,
+		Amount:          amount,
+		SubAmount:       amount,
+		Status:          status,
+		Time:            domain.TimestampNow(),
+		Currency:        domain.CURRENCY_IDR,
+	}
+}
+
+func TestCommitTransfer(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 1000)
+	to := saveBalance(t, 0)
+
+	statements := transferStatements(from, to, "TRX-1", 300)
+	transaction := transferTransaction(from, to, "TRX-1", 300, domain.COMPLETED_STATUS)
+
+	err := Base{}.Commit(ctx, statements, transaction)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if amount := balanceAmount(t, from); amount != 700 {
+		t.Errorf("from amount = %v, want 700", amount)
+	}
+
+	if amount := balanceAmount(t, to); amount != 300 {
+		t.Errorf("to amount = %v, want 300", amount)
+	}
+
+	saved, err := service.TransactionByID(ctx, transaction.ID.Hex())
+	if err != nil || saved.Status != domain.COMPLETED_STATUS {
+		t.Errorf("saved transaction = %+v, %v", saved, err)
+	}
+
+	sequenced, err := service.Repositories().Statement.FindSequenced(ctx, from.ID)
+	if err != nil {
+		t.Fatalf("find statements: %v", err)
+	}
+
+	last := sequenced[len(sequenced)-1]
+	if last.Withdraw != 300 || last.Balance != 700 || last.Sequence != 2 {
+		t.Errorf("last statement = %+v", last)
+	}
+}
+
+func TestCommitRollback(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 1000)
+	to := saveBalance(t, 0)
+
+	err := Base{}.Commit(ctx, transferStatements(from, to, "TRX-1", 300),
+		transferTransaction(from, to, "TRX-1", 300, domain.COMPLETED_STATUS))
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	err = Base{}.CommitRollback(ctx, transferStatements(to, from, "TRX-1", 300))
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	if amount := balanceAmount(t, from); amount != 1000 {
+		t.Errorf("from amount = %v, want 1000", amount)
+	}
+
+	if amount := balanceAmount(t, to); amount != 0 {
+		t.Errorf("to amount = %v, want 0", amount)
+	}
+}
+
+func TestCommitCurrencyMismatch(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 1000)
+	to := saveBalance(t, 0)
+
+	transaction := transferTransaction(from, to, "TRX-1", 300, domain.COMPLETED_STATUS)
+	transaction.Currency = domain.CURRENCY_USD
+
+	err := Base{}.Commit(ctx, transferStatements(from, to, "TRX-1", 300), transaction)
+	customError, ok := err.(utils.CustomError)
+	if !ok || customError.Code != utils.CurrencyError {
+		t.Fatalf("commit error = %v, want currency error", err)
+	}
+
+	if amount := balanceAmount(t, from); amount != 1000 {
+		t.Errorf("from amount = %v, want 1000", amount)
+	}
+}
+
+func TestCommitInsufficientBalance(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 100)
+	to := saveBalance(t, 0)
+
+	// Deposit written first must be undone with the failed withdraw
+	statements := transferStatements(from, to, "TRX-1", 300)
+	statements[0], statements[1] = statements[1], statements[0]
+	transaction := transferTransaction(from, to, "TRX-1", 300, domain.COMPLETED_STATUS)
+
+	err := Base{}.Commit(ctx, statements, transaction)
+	customError, ok := err.(utils.CustomError)
+	if !ok || customError.Code != utils.InsufficientBalance {
+		t.Fatalf("commit error = %v, want insufficient balance", err)
+	}
+
+	if amount := balanceAmount(t, from); amount != 100 {
+		t.Errorf("from amount = %v, want 100", amount)
+	}
+
+	if amount := balanceAmount(t, to); amount != 0 {
+		t.Errorf("to amount = %v, want 0", amount)
+	}
+
+	_, err = service.Repositories().Transaction.FindByCode(ctx, "TRX-1", "")
+	if err != repository.ErrNotFound {
+		t.Errorf("transaction saved, find error = %v", err)
+	}
+}
+
+func TestCommitReview(t *testing.T) {
+	for _, approve := range []bool{true, false} {
+		useMemory(t)
+		ctx := context.Background()
+		from := saveBalance(t, 1000)
+		to := saveBalance(t, 0)
+
+		transaction := transferTransaction(from, to, "TRX-1", 300, domain.HELD_STATUS)
+		err := Base{}.Commit(ctx, transferStatements(from, to, "TRX-1", 300), transaction)
+		if err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+
+		if balanceAmount(t, from) != 700 || balanceAmount(t, to) != 0 {
+			t.Fatalf("held amount = %v, %v, want 700, 0", balanceAmount(t, from), balanceAmount(t, to))
+		}
+
+		corporateID := primitive.NewObjectID()
+		err = service.Repositories().Fraud.Save(ctx, &domain.Fraud{
+			CorporateID:     corporateID,
+			TransactionCode: "TRX-1",
+			Decision:        domain.FRAUD_DECISION_REVIEW,
+			ReviewStatus:    domain.FRAUD_REVIEW_PENDING,
+			Time:            domain.TimestampNow(),
+		})
+		if err != nil {
+			t.Fatalf("save fraud: %v", err)
+		}
+
+		err = Base{}.CommitReview(ctx, transaction, approve, domain.User{ID: primitive.NewObjectID()}, "checked")
+		if err != nil {
+			t.Fatalf("review approve %v: %v", approve, err)
+		}
+
+		wantFrom, wantTo, wantStatus := 700, 300, domain.COMPLETED_STATUS
+		if !approve {
+			wantFrom, wantTo, wantStatus = 1000, 0, domain.REJECTED_STATUS
+		}
+
+		if balanceAmount(t, from) != wantFrom || balanceAmount(t, to) != wantTo {
+			t.Errorf("approve %v amount = %v, %v, want %v, %v", approve, balanceAmount(t, from),
+				balanceAmount(t, to), wantFrom, wantTo)
+		}
+
+		saved, err := service.TransactionByID(ctx, transaction.ID.Hex())
+		if err != nil || saved.Status != wantStatus || len(saved.ReservedStatements) != 0 {
+			t.Errorf("approve %v saved transaction = %+v, %v", approve, saved, err)
+		}
+
+		pending, err := service.Repositories().Fraud.FindPendingReview(ctx, corporateID, "1", "10")
+		if err != nil || len(pending) != 0 {
+			t.Errorf("approve %v pending review = %+v, %v, want closed", approve, pending, err)
+		}
+
+		err = Base{}.CommitReview(ctx, transaction, approve, domain.User{ID: primitive.NewObjectID()}, "again")
+		customError, ok := err.(utils.CustomError)
+		if !ok || customError.Code != utils.TransactionNotHeld {
+			t.Errorf("approve %v second review error = %v, want not held", approve, err)
+		}
+	}
+}
+
+func TestCommitSpendSubBalance(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 100)
+	to := saveBalance(t, 0)
+
+	fee := service.DepositFeeStatement(from.ID, domain.TimestampNow(), "FEE-1", domain.NewMoney(500, domain.CURRENCY_IDR))
+	fee.SubBalance = 1
+	err := Base{}.CommitRollback(ctx, []domain.Statement{fee})
+	if err != nil {
+		t.Fatalf("deposit fee: %v", err)
+	}
+
+	// Fee still in the sub balance is spent without being consolidated first
+	err = Base{}.Commit(ctx, transferStatements(from, to, "TRX-1", 550),
+		transferTransaction(from, to, "TRX-1", 550, domain.COMPLETED_STATUS))
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	balance, err := service.BalanceByID(ctx, from.ID.Hex())
+	if err != nil || balance.Amount != 50 {
+		t.Errorf("from balance = %v, %v, want 50", balance.Amount, err)
+	}
+
+	statements, err := service.Repositories().Statement.FindSequenced(ctx, from.ID)
+	if err != nil {
+		t.Fatalf("find statements: %v", err)
+	}
+
+	last := statements[len(statements)-1]
+	if last.Balance != 50 {
+		t.Errorf("last statement balance = %v, want 50", last.Balance)
+	}
+
+	err = Base{}.Commit(ctx, transferStatements(from, to, "TRX-2", 51),
+		transferTransaction(from, to, "TRX-2", 51, domain.COMPLETED_STATUS))
+	customError, ok := err.(utils.CustomError)
+	if !ok || customError.Code != utils.InsufficientBalance {
+		t.Errorf("commit error = %v, want insufficient balance", err)
+	}
+}
+
+func TestCommitHoldOnBreach(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	from := saveBalance(t, 1000)
+	to := saveBalance(t, 0)
+
+	corporate := domain.Corporate{ID: primitive.NewObjectID()}
+	actor := domain.User{ID: primitive.NewObjectID()}
+	transaction := transferTransaction(from, to, "TRX-1", 300, domain.COMPLETED_STATUS)
+
+	// Daily limit reached by a concurrent transaction only seen inside the commit
+	breach := func(ctx context.Context) error {
+		return utils.ErrorBadRequest(utils.DailyLimitExceeded, "Transaction reach daily limit")
+	}
+
+	err := Base{}.Commit(ctx, transferStatements(from, to, "TRX-1", 300), transaction,
+		Base{}.HoldOnBreach(corporate, actor, transaction, breach))
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if transaction.Status != domain.HELD_STATUS || transaction.HoldReason != domain.FRAUD_RULE_DAILY_LIMIT {
+		t.Errorf("transaction status %v reason %v, want held for daily limit", transaction.Status, transaction.HoldReason)
+	}
+
+	if balanceAmount(t, from) != 700 || balanceAmount(t, to) != 0 {
+		t.Errorf("held amount = %v, %v, want 700, 0", balanceAmount(t, from), balanceAmount(t, to))
+	}
+
+	reviews, err := service.FraudReviewByCorporate(ctx, corporate.ID, "1", "10")
+	if err != nil || len(reviews) != 1 || reviews[0].TransactionCode != "TRX-1" {
+		t.Errorf("pending review = %+v, %v", reviews, err)
+	}
+
+	minimum := utils.ErrorBadRequest(utils.MinimumAmountTransaction, "Transaction under minimum")
+	err = Base{}.HoldOnLimit(ctx, corporate, actor, transferTransaction(from, to, "TRX-2", 1,
+		domain.COMPLETED_STATUS), minimum)
+	if err != minimum {
+		t.Errorf("hold on minimum error = %v, want rejected", err)
+	}
+}
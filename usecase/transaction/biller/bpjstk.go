@@ -1,8 +1,7 @@
 package biller
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/domain/dto"
@@ -26,11 +25,11 @@ type BPJSTKBiller struct {
 	currency           string
 }
 
-func (self BPJSTKBiller) Execute(corporate domain.Corporate, actor domain.ActorAble,
+func (self BPJSTKBiller) Execute(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
 	to domain.TransactionObject, balanceID string, encryptedPIN string, externalID string,
 	paymentCode string, currency string) (domain.Transaction, interface{}, error) {
 
-	balance, err := identifyBalance(balanceID)
+	balance, err := identifyBalance(ctx, balanceID)
 	if err != nil {
 		return domain.Transaction{}, nil, err
 	}
@@ -48,7 +47,7 @@ func (self BPJSTKBiller) Execute(corporate domain.Corporate, actor domain.ActorA
 
 	transaction, transactionStatement := createTransaction(self.corporate, self.fromBalance, self.actor, to, 80000, externalID)
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, self.fromBalance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, self.fromBalance, transaction)
 	if err != nil {
 		return domain.Transaction{}, nil, err
 	}
@@ -56,11 +55,28 @@ func (self BPJSTKBiller) Execute(corporate domain.Corporate, actor domain.ActorA
 	statements = append(statements, transactionStatement)
 	statements = append(statements, feeStatement...)
 
-	err = validationActor(self.actor, self.fromBalance.ID.Hex(), self.pin)
+	err = validationActor(ctx, self.actor, self.fromBalance.ID.Hex(), self.pin)
 	if err != nil {
 		return domain.Transaction{}, nil, err
 	}
 
+	err = self.transactionUsecase.HoldOnLimit(ctx, corporate, self.actor, &transaction,
+		usecase.ValidateOutflowLimit(ctx, corporate, self.actor, transaction))
+	if err != nil {
+		return domain.Transaction{}, nil, err
+	}
+
+	err = self.transactionUsecase.EvaluateFraud(ctx, corporate, self.actor, self.fromBalance, &transaction)
+	if err != nil {
+		return domain.Transaction{}, nil, err
+	}
+
+	// Biller is paid synchronously so it cannot wait for review
+	if transaction.Status == domain.HELD_STATUS {
+		service.FraudCloseReview(ctx, transaction.TransactionCode, self.actor.ToActorObject(), domain.REVIEW_ACTION_REJECTED)
+		return domain.Transaction{}, nil, utils.ErrorBadRequest(utils.TransactionBlocked, "Biller transaction cannot be held")
+	}
+
 	err, ref := self.billerBase.BillerPayBPJSTKPMI(transaction, paymentCode, currency)
 	if err != nil {
 		return domain.Transaction{}, nil, err
@@ -68,7 +84,9 @@ func (self BPJSTKBiller) Execute(corporate domain.Corporate, actor domain.ActorA
 
 	transaction.GatewayReference = ref
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction, func(ctx context.Context) error {
+		return usecase.EnforceOutflowLimit(ctx, corporate, self.actor, transaction)
+	})
 	if err != nil {
 		return domain.Transaction{}, nil, err
 	}
@@ -92,8 +110,8 @@ func (self BPJSTKBiller) Execute(corporate domain.Corporate, actor domain.ActorA
 	}, err
 }
 
-func identifyBalance(balanceID string) (domain.Balance, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
 	}
@@ -123,23 +141,23 @@ func createTransaction(corporate domain.Corporate, balance domain.Balance, from
 		TotalFee:        totalFee,
 		SubAmount:       subAmount,
 		Amount:          subAmount + totalFee,
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		Notes:           "",
 		Status:          domain.COMPLETED_STATUS,
 		Unpaid:          false,
 		ExternalID:      externalID,
-		Currency:        "idr",
+		Currency:        domain.CURRENCY_IDR,
 	}
 
 	statement := service.WithdrawTransactionStatement(
-		balance.ID, transcation.Time, transcation.TransactionCode, subAmount)
+		balance.ID, transcation.Time, transcation.TransactionCode, domain.NewMoney(subAmount, transcation.Currency))
 
 	return transcation, statement
 }
 
-func validationActor(actor domain.ActorAble, balanceID string, pin string) error {
+func validationActor(ctx context.Context, actor domain.ActorAble, balanceID string, pin string) error {
 
-	err := usecase.ValidateActorPIN(actor, pin)
+	err := usecase.ValidateActorPIN(ctx, actor, pin)
 	if err != nil {
 		return err
 	}
@@ -149,7 +167,7 @@ func validationActor(actor domain.ActorAble, balanceID string, pin string) error
 		return err
 	}
 
-	err = usecase.ValidateIsVerify(actor)
+	err = usecase.ValidateIsVerify(actor, domain.BILLER)
 	if err != nil {
 		return err
 	}
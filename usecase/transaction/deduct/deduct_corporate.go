@@ -1,8 +1,7 @@
 package deduct
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -24,25 +23,25 @@ type DeductCorporate struct {
 	transactionUsecase transaction.Base
 }
 
-func (self DeductCorporate) Execute(corporate domain.Corporate, actor domain.ActorAble,
+func (self DeductCorporate) Execute(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
 	toBalanceID string, fromBalanceID string, subAmount int, encryptedPIN string, externalID string) (domain.Transaction, error) {
 
-	fromBalance, err := identifyBalance(fromBalanceID)
+	fromBalance, err := identifyBalance(ctx, fromBalanceID)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	from, err := usecase.ActorObjectToActor(fromBalance.Owner.ToActorObject())
+	from, err := usecase.ActorObjectToActor(ctx, fromBalance.Owner.ToActorObject())
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	toBalance, err := identifyBalance(toBalanceID)
+	toBalance, err := identifyBalance(ctx, toBalanceID)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	to, err := usecase.ActorObjectToActor(toBalance.Owner.ToActorObject())
+	to, err := usecase.ActorObjectToActor(ctx, toBalance.Owner.ToActorObject())
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -64,7 +63,7 @@ func (self DeductCorporate) Execute(corporate domain.Corporate, actor domain.Act
 	transaction, transactionStatement := createTransaction(self.corporate, self.fromBalance, self.actor, self.from, self.to,
 		self.toBalance, self.subAmount, self.externalID)
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, self.fromBalance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, self.fromBalance, transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -72,7 +71,7 @@ func (self DeductCorporate) Execute(corporate domain.Corporate, actor domain.Act
 	statements = append(statements, transactionStatement...)
 	statements = append(statements, feeStatement...)
 
-	err = validationActor(self.actor, self.fromBalance, toBalance, self.pin)
+	err = validationActor(ctx, self.actor, self.fromBalance, toBalance, self.pin)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -82,16 +81,29 @@ func (self DeductCorporate) Execute(corporate domain.Corporate, actor domain.Act
 		return domain.Transaction{}, err
 	}
 
-	err = validationTransaction(transaction)
+	err = self.transactionUsecase.HoldOnLimit(ctx, corporate, self.actor, &transaction,
+		validationTransaction(ctx, corporate, from, to, toBalance, transaction))
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	err = self.transactionUsecase.EvaluateFraud(ctx, corporate, self.actor, self.fromBalance, &transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction,
+		self.transactionUsecase.HoldOnBreach(corporate, self.actor, &transaction,
+			enforceTransaction(corporate, from, to, toBalance, transaction)))
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	// Continued by reviewer approval
+	if transaction.Status == domain.HELD_STATUS {
+		return transaction, nil
+	}
+
 	go usecase.PublishDeductCallback(corporate, fromBalance, transaction)
 
 	return transaction, nil
@@ -121,21 +133,21 @@ func createTransaction(corporate domain.Corporate, fromBalance domain.Balance, a
 		TotalFee:        totalFee,
 		SubAmount:       subAmount,
 		Amount:          subAmount + totalFee,
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		Notes:           "",
 		Status:          domain.COMPLETED_STATUS,
 		Unpaid:          false,
 		ExternalID:      externalID,
-		Currency:        corporate.Currency,
+		Currency:        corporate.GetCurrency(),
 	}
 
 	var statements []domain.Statement
 
 	fromStatement := service.DepositTransactionStatement(
-		toBalance.ID, transaction.Time, transaction.TransactionCode, subAmount)
+		toBalance.ID, transaction.Time, transaction.TransactionCode, domain.NewMoney(subAmount, transaction.Currency))
 
 	toStatement := service.WithdrawTransactionStatement(
-		fromBalance.ID, transaction.Time, transaction.TransactionCode, subAmount)
+		fromBalance.ID, transaction.Time, transaction.TransactionCode, domain.NewMoney(subAmount, transaction.Currency))
 
 	statements = append(statements, fromStatement)
 	statements = append(statements, toStatement)
@@ -143,8 +155,8 @@ func createTransaction(corporate domain.Corporate, fromBalance domain.Balance, a
 	return transaction, statements
 }
 
-func identifyBalance(balanceID string) (domain.Balance, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
 	}
@@ -152,9 +164,10 @@ func identifyBalance(balanceID string) (domain.Balance, error) {
 	return balance, nil
 }
 
-func validationActor(actor domain.ActorAble, sourceBalance domain.Balance, targetBalance domain.Balance, pin string) error {
+func validationActor(ctx context.Context, actor domain.ActorAble, sourceBalance domain.Balance,
+	targetBalance domain.Balance, pin string) error {
 
-	err := usecase.ValidateActorPIN(actor, pin)
+	err := usecase.ValidateActorPIN(ctx, actor, pin)
 	if err != nil {
 		return err
 	}
@@ -164,7 +177,7 @@ func validationActor(actor domain.ActorAble, sourceBalance domain.Balance, targe
 		return err
 	}
 
-	err = usecase.ValidateIsVerify(actor)
+	err = usecase.ValidateIsVerify(actor, domain.DEDUCT)
 	if err != nil {
 		return err
 	}
@@ -185,16 +198,30 @@ func validateDeductScope(actor domain.ActorAble, balance domain.Balance) error {
 	return nil
 }
 
-func validationTransaction(transaction domain.Transaction) error {
-	err := validateMaximum(transaction)
+func validationTransaction(ctx context.Context, corporate domain.Corporate, from domain.ActorAble, to domain.ActorAble,
+	toBalance domain.Balance, transaction domain.Transaction) error {
+	err := usecase.ValidateOutflowLimit(ctx, corporate, from, transaction)
 	if err != nil {
 		return err
 	}
 
-	err = validateMinimum(transaction)
+	err = usecase.ValidateBalanceCeiling(ctx, corporate, to, toBalance, transaction)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Limit checked again inside the commit transaction
+func enforceTransaction(corporate domain.Corporate, from domain.ActorAble, to domain.ActorAble,
+	toBalance domain.Balance, transaction domain.Transaction) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := usecase.EnforceOutflowLimit(ctx, corporate, from, transaction)
+		if err != nil {
+			return err
+		}
+
+		return usecase.EnforceBalanceCeiling(ctx, corporate, to, toBalance, transaction)
+	}
+}
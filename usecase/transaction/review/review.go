@@ -0,0 +1,122 @@
+package review
+
+import (
+	"context"
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/usecase"
+	"github.com/takeme-id/core/usecase/transaction"
+	"github.com/takeme-id/core/usecase/transaction/transfer/transfer_bank"
+	"github.com/takeme-id/core/utils"
+)
+
+func HeldTransactions(ctx context.Context, corporate domain.Corporate, page string,
+	limit string) ([]domain.Transaction, error) {
+	return service.TransactionsByStatus(ctx, corporate.ID, domain.HELD_STATUS, page, limit)
+}
+
+// Approve continue held transaction through the same path as if it was never held
+func ApproveTransaction(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	transactionCode string, encryptedPIN string, note string) (domain.Transaction, error) {
+
+	heldTransaction, err := identifyHeldTransaction(ctx, corporate, reviewer, transactionCode, encryptedPIN)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	transactionUsecase := transaction.Base{}
+	err = transactionUsecase.CommitReview(ctx, &heldTransaction, true, reviewer, note)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	continueTransaction(ctx, corporate, heldTransaction)
+
+	return heldTransaction, nil
+}
+
+// Reject release reserved fund and notify corporate
+func RejectTransaction(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	transactionCode string, encryptedPIN string, reason string) (domain.Transaction, error) {
+
+	heldTransaction, err := identifyHeldTransaction(ctx, corporate, reviewer, transactionCode, encryptedPIN)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	transactionUsecase := transaction.Base{}
+	err = transactionUsecase.CommitReview(ctx, &heldTransaction, false, reviewer, reason)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	publishCallback(ctx, corporate, heldTransaction)
+
+	return heldTransaction, nil
+}
+
+func identifyHeldTransaction(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	transactionCode string, encryptedPIN string) (domain.Transaction, error) {
+
+	err := validateReviewer(ctx, corporate, reviewer, encryptedPIN)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	heldTransaction, err := service.TransactionByCode(ctx, transactionCode)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if heldTransaction.CorporateID != corporate.ID {
+		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotFound, "Transaction not in corporate scope")
+	}
+
+	if heldTransaction.Status != domain.HELD_STATUS {
+		return domain.Transaction{}, utils.ErrorBadRequest(utils.TransactionNotHeld, "Transaction is not held")
+	}
+
+	return heldTransaction, nil
+}
+
+func validateReviewer(ctx context.Context, corporate domain.Corporate, reviewer domain.ActorAble,
+	encryptedPIN string) error {
+	err := usecase.ValidatePermission(ctx, corporate, reviewer, domain.PERMISSION_TRANSACTION_REVIEW)
+	if err != nil {
+		return utils.ErrorBadRequest(utils.InvalidReviewer, "Reviewer has no review permission")
+	}
+
+	return usecase.ValidateActorPIN(ctx, reviewer, encryptedPIN)
+}
+
+func continueTransaction(ctx context.Context, corporate domain.Corporate, heldTransaction domain.Transaction) {
+	if heldTransaction.Type == domain.TRANSFER_BANK {
+		go transfer_bank.TransferBank{}.CreateTransferGateway(context.Background(), heldTransaction)
+		return
+	}
+
+	publishCallback(ctx, corporate, heldTransaction)
+}
+
+// Callback of the transaction type, sent on approval and on rejection
+func publishCallback(ctx context.Context, corporate domain.Corporate, heldTransaction domain.Transaction) {
+	if heldTransaction.Type == domain.TRANSFER_BANK {
+		go usecase.PublishTransferCallback(corporate, heldTransaction)
+		return
+	}
+
+	if heldTransaction.Type == domain.DEDUCT {
+		// deduct keep the debited balance on to_balance_id
+		balance, err := service.BalanceByID(ctx, heldTransaction.ToBalanceID.Hex())
+		if err == nil {
+			go usecase.PublishDeductCallback(corporate, balance, heldTransaction)
+		}
+
+		return
+	}
+
+	balance, err := service.BalanceByID(ctx, heldTransaction.ToBalanceID.Hex())
+	if err == nil {
+		go usecase.PublishTopupCallback(corporate, balance, heldTransaction)
+	}
+}
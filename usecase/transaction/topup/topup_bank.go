@@ -1,8 +1,7 @@
 package topup
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -23,10 +22,10 @@ type TopupBank struct {
 	transactionUsecase transaction.Base
 }
 
-func (self TopupBank) Execute(from domain.Bank, balanceID string, amount int,
+func (self TopupBank) Execute(ctx context.Context, from domain.Bank, balanceID string, amount int,
 	reference string, currency string) (domain.Transaction, domain.Balance, error) {
 
-	balance, owner, corporate, err := identifyBalance(balanceID)
+	balance, owner, corporate, err := identifyBalance(ctx, balanceID)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
@@ -46,7 +45,7 @@ func (self TopupBank) Execute(from domain.Bank, balanceID string, amount int,
 	transaction, transactionStatement := createTransaction(self.corporate, self.balance, self.from,
 		self.to, self.amount, self.reference, gateway)
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, balance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, balance, transaction)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
@@ -59,18 +58,37 @@ func (self TopupBank) Execute(from domain.Bank, balanceID string, amount int,
 		return domain.Transaction{}, domain.Balance{}, err
 	}
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	balanceOwner, err := usecase.ActorObjectToActor(ctx, balance.Owner)
 	if err != nil {
 		return domain.Transaction{}, domain.Balance{}, err
 	}
 
+	err = self.transactionUsecase.HoldOnLimit(ctx, corporate, balanceOwner, &transaction,
+		validateLimit(ctx, corporate, balanceOwner, balance, transaction))
+	if err != nil {
+		return domain.Transaction{}, domain.Balance{}, err
+	}
+
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction,
+		self.transactionUsecase.HoldOnBreach(corporate, balanceOwner, &transaction,
+			enforceLimit(corporate, balanceOwner, balance, transaction)))
+	if err != nil {
+		return domain.Transaction{}, domain.Balance{}, err
+	}
+
+	// Deposit wait for reviewer approval
+	if transaction.Status == domain.HELD_STATUS {
+		return transaction, balance, nil
+	}
+
 	go usecase.PublishTopupCallback(corporate, balance, transaction)
 
 	return transaction, balance, nil
 }
 
-func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject, domain.Corporate, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, domain.TransactionObject,
+	domain.Corporate, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 			utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
@@ -82,7 +100,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 	balanceOwnerType := balance.Owner.Type
 
 	if balanceOwnerType == domain.ACTOR_TYPE_CORPORATE {
-		corporate, err := service.CorporateByIDNoSession(ownerID)
+		corporate, err := service.CorporateByID(ctx, ownerID)
 		if err != nil {
 			return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 				utils.ErrorBadRequest(utils.CorporateNotFound, "Corporate id not found")
@@ -90,7 +108,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 
 		balanceOwner = corporate.ToTransactionObject()
 	} else {
-		user, err := service.UserByIDNoSession(ownerID)
+		user, err := service.UserByID(ctx, ownerID)
 		if err != nil {
 			return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 				utils.ErrorBadRequest(utils.UserNotFound, "User id not found")
@@ -99,7 +117,7 @@ func identifyBalance(balanceID string) (domain.Balance, domain.TransactionObject
 		balanceOwner = user.ToTransactionObject()
 	}
 
-	corporate, err := service.CorporateByIDNoSession(corporateID)
+	corporate, err := service.CorporateByID(ctx, corporateID)
 	if err != nil {
 		return domain.Balance{}, domain.TransactionObject{}, domain.Corporate{},
 			utils.ErrorBadRequest(utils.CorporateNotFound, "Corporate id not found")
@@ -130,18 +148,18 @@ func createTransaction(corporate domain.Corporate, balance domain.Balance, from
 		TotalFee:         totalFee,
 		SubAmount:        subAmount,
 		Amount:           subAmount - totalFee,
-		Time:             time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:             domain.TimestampNow(),
 		Notes:            "",
 		Status:           domain.COMPLETED_STATUS,
 		Unpaid:           false,
 		ExternalID:       "",
 		Gateway:          gateway.Name(),
 		GatewayReference: reference,
-		Currency:         corporate.Currency,
+		Currency:         corporate.GetCurrency(),
 	}
 
 	statement := service.DepositTransactionStatement(
-		balance.ID, transcation.Time, transcation.TransactionCode, subAmount)
+		balance.ID, transcation.Time, transcation.TransactionCode, domain.NewMoney(subAmount, transcation.Currency))
 
 	return transcation, statement
 }
@@ -1,7 +1,9 @@
 package topup
 
 import (
+	"context"
 	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/usecase"
 	"github.com/takeme-id/core/utils"
 )
 
@@ -12,3 +14,16 @@ func validateCurrency(incomeCurrency string, balance domain.Balance) error {
 
 	return nil
 }
+
+func validateLimit(ctx context.Context, corporate domain.Corporate, owner domain.ActorAble, balance domain.Balance,
+	transaction domain.Transaction) error {
+	return usecase.ValidateInflowLimit(ctx, corporate, owner, balance, transaction)
+}
+
+// Limit checked again inside the commit transaction
+func enforceLimit(corporate domain.Corporate, owner domain.ActorAble, balance domain.Balance,
+	transaction domain.Transaction) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return usecase.EnforceInflowLimit(ctx, corporate, owner, balance, transaction)
+	}
+}
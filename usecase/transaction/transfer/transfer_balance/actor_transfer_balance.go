@@ -1,8 +1,7 @@
 package transfer_balance
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -25,25 +24,25 @@ type ActorTransferBalance struct {
 	isTopuoType        bool
 }
 
-func (self ActorTransferBalance) Execute(corporate domain.Corporate, actor domain.ActorAble,
+func (self ActorTransferBalance) Execute(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
 	toBalanceID string, fromBalanceID string, subAmount int, encryptedPIN string, externalID string, isTopupType bool) (domain.Transaction, error) {
 
-	fromBalance, err := identifyBalance(fromBalanceID)
+	fromBalance, err := identifyBalance(ctx, fromBalanceID)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	from, err := usecase.ActorObjectToActor(fromBalance.Owner.ToActorObject())
+	from, err := usecase.ActorObjectToActor(ctx, fromBalance.Owner.ToActorObject())
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	toBalance, err := identifyBalance(toBalanceID)
+	toBalance, err := identifyBalance(ctx, toBalanceID)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	to, err := usecase.ActorObjectToActor(toBalance.Owner.ToActorObject())
+	to, err := usecase.ActorObjectToActor(ctx, toBalance.Owner.ToActorObject())
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -71,7 +70,7 @@ func (self ActorTransferBalance) Execute(corporate domain.Corporate, actor domai
 	transaction, transactionStatement := createTransaction(self.corporate, self.fromBalance, self.actor, self.from, self.to,
 		self.toBalance, self.subAmount, self.externalID, isTopupType)
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, self.fromBalance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, self.fromBalance, transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -80,27 +79,40 @@ func (self ActorTransferBalance) Execute(corporate domain.Corporate, actor domai
 	statements = append(statements, feeStatement...)
 
 	if self.actor.GetActorType() == domain.ACTOR_TYPE_USER {
-		err = validationActorUser(self.actor, self.fromBalance.ID.Hex(), self.pin)
+		err = validationActorUser(ctx, self.actor, self.fromBalance.ID.Hex(), self.pin)
 		if err != nil {
 			return domain.Transaction{}, err
 		}
 	} else {
-		err = validationActorCorporate(self.actor, fromBalance, corporate, self.pin)
+		err = validationActorCorporate(ctx, self.actor, fromBalance, corporate, self.pin)
 		if err != nil {
 			return domain.Transaction{}, err
 		}
 	}
 
-	err = validationTransaction(transaction)
+	err = self.transactionUsecase.HoldOnLimit(ctx, corporate, self.actor, &transaction,
+		validationTransaction(ctx, corporate, from, to, toBalance, transaction))
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	err = self.transactionUsecase.EvaluateFraud(ctx, corporate, self.actor, self.fromBalance, &transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction,
+		self.transactionUsecase.HoldOnBreach(corporate, self.actor, &transaction,
+			enforceTransaction(corporate, from, to, toBalance, transaction)))
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	// Continued by reviewer approval
+	if transaction.Status == domain.HELD_STATUS {
+		return transaction, nil
+	}
+
 	go usecase.PublishTopupCallback(corporate, toBalance, transaction)
 
 	return transaction, nil
@@ -135,21 +147,21 @@ func createTransaction(corporate domain.Corporate, fromBalance domain.Balance, a
 		TotalFee:        totalFee,
 		SubAmount:       subAmount,
 		Amount:          subAmount + totalFee,
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		Notes:           "",
 		Status:          domain.COMPLETED_STATUS,
 		Unpaid:          false,
 		ExternalID:      externalID,
-		Currency:        corporate.Currency,
+		Currency:        corporate.GetCurrency(),
 	}
 
 	var statements []domain.Statement
 
 	fromStatement := service.WithdrawTransactionStatement(
-		fromBalance.ID, transaction.Time, transaction.TransactionCode, subAmount)
+		fromBalance.ID, transaction.Time, transaction.TransactionCode, domain.NewMoney(subAmount, transaction.Currency))
 
 	toStatement := service.DepositTransactionStatement(
-		toBalance.ID, transaction.Time, transaction.TransactionCode, subAmount)
+		toBalance.ID, transaction.Time, transaction.TransactionCode, domain.NewMoney(subAmount, transaction.Currency))
 
 	statements = append(statements, fromStatement)
 	statements = append(statements, toStatement)
@@ -157,8 +169,8 @@ func createTransaction(corporate domain.Corporate, fromBalance domain.Balance, a
 	return transaction, statements
 }
 
-func identifyBalance(balanceID string) (domain.Balance, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
 	}
@@ -166,9 +178,9 @@ func identifyBalance(balanceID string) (domain.Balance, error) {
 	return balance, nil
 }
 
-func validationActorUser(actor domain.ActorAble, balanceID string, pin string) error {
+func validationActorUser(ctx context.Context, actor domain.ActorAble, balanceID string, pin string) error {
 
-	err := usecase.ValidateActorPIN(actor, pin)
+	err := usecase.ValidateActorPIN(ctx, actor, pin)
 	if err != nil {
 		return err
 	}
@@ -178,7 +190,7 @@ func validationActorUser(actor domain.ActorAble, balanceID string, pin string) e
 		return err
 	}
 
-	err = usecase.ValidateIsVerify(actor)
+	err = usecase.ValidateIsVerify(actor, domain.TRANSFER_WALLET)
 	if err != nil {
 		return err
 	}
@@ -186,9 +198,10 @@ func validationActorUser(actor domain.ActorAble, balanceID string, pin string) e
 	return nil
 }
 
-func validationActorCorporate(actor domain.ActorAble, balance domain.Balance, corporate domain.Corporate, pin string) error {
+func validationActorCorporate(ctx context.Context, actor domain.ActorAble, balance domain.Balance,
+	corporate domain.Corporate, pin string) error {
 
-	err := usecase.ValidateActorPIN(actor, pin)
+	err := usecase.ValidateActorPIN(ctx, actor, pin)
 	if err != nil {
 		return err
 	}
@@ -197,7 +210,7 @@ func validationActorCorporate(actor domain.ActorAble, balance domain.Balance, co
 		return utils.ErrorBadRequest(utils.InvalidBalanceAccess, "Invalid balance access")
 	}
 
-	err = usecase.ValidateIsVerify(actor)
+	err = usecase.ValidateIsVerify(actor, domain.TRANSFER_WALLET)
 	if err != nil {
 		return err
 	}
@@ -205,16 +218,30 @@ func validationActorCorporate(actor domain.ActorAble, balance domain.Balance, co
 	return nil
 }
 
-func validationTransaction(transaction domain.Transaction) error {
-	err := validateMaximum(transaction)
+func validationTransaction(ctx context.Context, corporate domain.Corporate, from domain.ActorAble, to domain.ActorAble,
+	toBalance domain.Balance, transaction domain.Transaction) error {
+	err := usecase.ValidateOutflowLimit(ctx, corporate, from, transaction)
 	if err != nil {
 		return err
 	}
 
-	err = validateMinimum(transaction)
+	err = usecase.ValidateBalanceCeiling(ctx, corporate, to, toBalance, transaction)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// Limit checked again inside the commit transaction
+func enforceTransaction(corporate domain.Corporate, from domain.ActorAble, to domain.ActorAble,
+	toBalance domain.Balance, transaction domain.Transaction) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := usecase.EnforceOutflowLimit(ctx, corporate, from, transaction)
+		if err != nil {
+			return err
+		}
+
+		return usecase.EnforceBalanceCeiling(ctx, corporate, to, toBalance, transaction)
+	}
+}
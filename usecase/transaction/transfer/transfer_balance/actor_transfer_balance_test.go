@@ -0,0 +1,54 @@
+package transfer_balance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/usecase/transaction"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCommitCorporateWithoutCurrency(t *testing.T) {
+	service.UseRepositories(repository.NewMemory())
+	t.Cleanup(func() {
+		service.UseRepositories(nil)
+	})
+
+	ctx := context.Background()
+	corporate := domain.Corporate{ID: primitive.NewObjectID(), Name: "Legacy"}
+	user := domain.User{ID: primitive.NewObjectID(), CorporateID: corporate.ID}
+
+	from, err := service.BalanceCreate(ctx, corporate.ID, user.ToActorObject(), "Main", corporate.GetCurrency())
+	if err != nil {
+		t.Fatalf("create balance: %v", err)
+	}
+
+	_, err = service.BalanceAdjust(ctx, from.ID, 1000, domain.CURRENCY_IDR)
+	if err != nil {
+		t.Fatalf("fund balance: %v", err)
+	}
+
+	to, err := service.BalanceCreate(ctx, corporate.ID, corporate.ToActorObject(), "Main", corporate.GetCurrency())
+	if err != nil {
+		t.Fatalf("create balance: %v", err)
+	}
+
+	model, statements := createTransaction(corporate, from, user, user.ToTransactionObject(),
+		corporate.ToTransactionObject(), to, 300, "", false)
+	if model.Currency != domain.CURRENCY_IDR {
+		t.Fatalf("currency = %q, want %q", model.Currency, domain.CURRENCY_IDR)
+	}
+
+	err = transaction.Base{}.Commit(ctx, statements, &model)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	saved, err := service.Repositories().Balance.FindByID(ctx, to.ID)
+	if err != nil || saved.Amount != 300 {
+		t.Errorf("to balance = %+v, %v, want 300", saved, err)
+	}
+}
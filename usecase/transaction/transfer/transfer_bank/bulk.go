@@ -1,15 +1,17 @@
 package transfer_bank
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/usecase"
 	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func CreateBulkInquiry(corporate domain.Corporate, reference string, banks []domain.Bank,
+func CreateBulkInquiry(ctx context.Context, corporate domain.Corporate, reference string, banks []domain.Bank,
 	actor domain.ActorObject) (domain.BulkInquiry, error) {
 
 	totalBulk := len(banks)
@@ -19,17 +21,17 @@ func CreateBulkInquiry(corporate domain.Corporate, reference string, banks []dom
 
 	bulk := service.CreateBulkInquiry(corporate, totalBulk, reference, banks, actor)
 
-	err := service.SaveBulkInquiry(&bulk)
+	err := service.SaveBulkInquiry(ctx, &bulk)
 	if err != nil {
 		return domain.BulkInquiry{}, err
 	}
 
-	go executeBulkInquiry(corporate, actor, bulk)
+	go executeBulkInquiry(context.Background(), corporate, actor, bulk)
 
 	return bulk, nil
 }
 
-func CreateBulkTransfer(corporate domain.Corporate, reference string, transfers []domain.Transfer,
+func CreateBulkTransfer(ctx context.Context, corporate domain.Corporate, reference string, transfers []domain.Transfer,
 	actor domain.ActorObject, balanceID string) (domain.BulkTransfer, error) {
 
 	totalBulk := len(transfers)
@@ -37,7 +39,7 @@ func CreateBulkTransfer(corporate domain.Corporate, reference string, transfers
 		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkListEmpty, "Bulk list empty")
 	}
 
-	balance, err := service.BalanceByIDNoSession(balanceID)
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil || balance.Owner.Type == "" {
 		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance not found")
 	}
@@ -47,7 +49,7 @@ func CreateBulkTransfer(corporate domain.Corporate, reference string, transfers
 		return domain.BulkTransfer{}, err
 	}
 
-	err = service.SaveBulkTransfer(&bulk)
+	err = service.SaveBulkTransfer(ctx, &bulk)
 	if err != nil {
 		return domain.BulkTransfer{}, err
 	}
@@ -55,15 +57,23 @@ func CreateBulkTransfer(corporate domain.Corporate, reference string, transfers
 	return bulk, nil
 }
 
-func ActorExecuteBulkTransfer(corporate domain.Corporate, user domain.ActorAble, pin string,
+func ActorExecuteBulkTransfer(ctx context.Context, corporate domain.Corporate, user domain.ActorAble, pin string,
 	bulkID string) (domain.BulkTransfer, error) {
 
-	bulk, err := service.BulkTransferByID(bulkID)
-	if err != nil || bulk.Time == "" || bulk.Status != domain.BULK_UNEXECUTED_STATUS {
-		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk Not found or already executed")
+	bulk, err := service.BulkTransferByID(ctx, bulkID)
+	if err != nil || bulk.Time.IsZero() || bulk.CorporateID != corporate.ID {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk Not found")
 	}
 
-	err = usecase.ValidateActorPIN(user, pin)
+	if bulk.Status == domain.BULK_PENDING_APPROVAL_STATUS {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkApprovalIncomplete, "Bulk waiting for approval")
+	}
+
+	if bulk.Status != domain.BULK_UNEXECUTED_STATUS || bulk.ApprovedCount() < bulk.RequiredApprovals {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk already executed or rejected")
+	}
+
+	err = usecase.ValidateActorPIN(ctx, user, pin)
 	if err != nil {
 		return domain.BulkTransfer{}, err
 	}
@@ -73,20 +83,261 @@ func ActorExecuteBulkTransfer(corporate domain.Corporate, user domain.ActorAble,
 		return domain.BulkTransfer{}, err
 	}
 
-	err = usecase.ValidateIsVerify(user)
+	err = usecase.ValidateIsVerify(user, domain.TRANSFER_BANK)
 	if err != nil {
 		return domain.BulkTransfer{}, err
 	}
 
-	go executeBulkTransfer(corporate, user, pin, bulk)
+	// Only one of concurrent execute move the bulk to progress
+	set, err := service.BulkTransferSetStatus(ctx, bulk.ID, domain.BULK_UNEXECUTED_STATUS, domain.BULK_PROGRESS_STATUS)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	if !set {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk already executed or rejected")
+	}
+
+	go executeBulkTransfer(context.Background(), corporate, user, pin, bulk)
 
 	bulk.Status = domain.BULK_PROGRESS_STATUS
 	return bulk, nil
 }
 
-func ViewBulkInquiry(bulkID string) (domain.BulkInquiry, error) {
+// Approver sign off a bulk created by another actor, bulk become executable
+// once the number of approval reach the corporate threshold
+func ApproveBulkTransfer(ctx context.Context, corporate domain.Corporate, approver domain.ActorAble, pin string,
+	bulkID string, note string) (domain.BulkTransfer, error) {
+
+	bulk, err := identifyPendingBulk(ctx, corporate, approver, pin, bulkID)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	approval := domain.BulkApproval{
+		Approver: approver.ToActorObject(),
+		Action:   domain.BULK_APPROVAL_APPROVED,
+		Note:     note,
+		Time:     domain.TimestampNow(),
+	}
+
+	function := func(session context.Context) error {
+		err := addBulkApproval(session, bulk.ID, approval)
+		if err != nil {
+			return err
+		}
+
+		current, err := service.BulkTransferByID(session, bulk.ID.Hex())
+		if err != nil {
+			return err
+		}
+
+		if current.ApprovedCount() >= current.RequiredApprovals {
+			err = setBulkStatus(session, bulk.ID, domain.BULK_PENDING_APPROVAL_STATUS, domain.BULK_UNEXECUTED_STATUS)
+			if err != nil {
+				return err
+			}
+
+			current.Status = domain.BULK_UNEXECUTED_STATUS
+		}
+
+		bulk = current
+
+		return nil
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	if bulk.Status == domain.BULK_UNEXECUTED_STATUS {
+		go usecase.PublishBulkCallback(corporate, bulk.Owner, bulk.ID.Hex(), bulk.Status, corporate.BulkTransferCallbackURL)
+	}
+
+	return bulk, nil
+}
+
+// A single rejection stop the bulk, maker have to create a new one
+func RejectBulkTransfer(ctx context.Context, corporate domain.Corporate, approver domain.ActorAble, pin string,
+	bulkID string, reason string) (domain.BulkTransfer, error) {
+
+	bulk, err := identifyPendingBulk(ctx, corporate, approver, pin, bulkID)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	approval := domain.BulkApproval{
+		Approver: approver.ToActorObject(),
+		Action:   domain.BULK_APPROVAL_REJECTED,
+		Note:     reason,
+		Time:     domain.TimestampNow(),
+	}
+
+	function := func(session context.Context) error {
+		err := addBulkApproval(session, bulk.ID, approval)
+		if err != nil {
+			return err
+		}
+
+		return setBulkStatus(session, bulk.ID, domain.BULK_PENDING_APPROVAL_STATUS, domain.BULK_REJECTED_STATUS)
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	bulk.Approvals = append(bulk.Approvals, approval)
+	bulk.Status = domain.BULK_REJECTED_STATUS
+
+	go usecase.PublishBulkCallback(corporate, bulk.Owner, bulk.ID.Hex(), bulk.Status, corporate.BulkTransferCallbackURL)
+
+	return bulk, nil
+}
+
+func PendingApprovalBulkTransfers(ctx context.Context, corporate domain.Corporate, page string,
+	limit string) ([]domain.BulkTransfer, error) {
+	return service.BulkTransfersByStatus(ctx, corporate.ID, domain.BULK_PENDING_APPROVAL_STATUS, page, limit)
+}
+
+// Thresholds turn maker-checker on or off for the whole corporate, only admin
+// with the permission can change them and every change is kept
+func SaveBulkApprovalThresholds(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
+	thresholds []domain.BulkApprovalThreshold) ([]domain.BulkApprovalThreshold, error) {
+
+	err := usecase.ValidatePermission(ctx, corporate, actor, domain.PERMISSION_BULK_APPROVAL_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]bool{}
+	for _, threshold := range thresholds {
+		if threshold.MinimumAmount < 0 || threshold.RequiredApprovals < 0 {
+			return nil, utils.ErrorBadRequest(utils.InvalidBulkApprovalThreshold, "Threshold cannot be negative")
+		}
+
+		if seen[threshold.MinimumAmount] {
+			return nil, utils.ErrorBadRequest(utils.InvalidBulkApprovalThreshold, "Duplicate threshold amount")
+		}
+
+		seen[threshold.MinimumAmount] = true
+	}
+
+	function := func(session context.Context) error {
+		current, err := service.CorporateByID(session, corporate.ID.Hex())
+		if err != nil {
+			return err
+		}
+
+		history := domain.BulkApprovalHistory{
+			CorporateID: corporate.ID,
+			Actor:       actor.ToActorObject(),
+			Previous:    current.BulkApprovals,
+			Current:     thresholds,
+			Time:        domain.TimestampNow(),
+		}
+
+		err = service.CorporateUpdateBulkApprovals(session, corporate.ID, thresholds)
+		if err != nil {
+			return err
+		}
+
+		return service.BulkApprovalHistorySave(session, &history)
+	}
+
+	err = service.Repositories().Transactor.WithTransaction(ctx, function)
+	if err != nil {
+		return nil, err
+	}
+
+	return thresholds, nil
+}
+
+func BulkApprovalHistories(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble, page string,
+	limit string) ([]domain.BulkApprovalHistory, error) {
+
+	err := usecase.ValidatePermission(ctx, corporate, actor, domain.PERMISSION_BULK_APPROVAL_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.BulkApprovalHistories(ctx, corporate.ID, page, limit)
+}
+
+func identifyPendingBulk(ctx context.Context, corporate domain.Corporate, approver domain.ActorAble, pin string,
+	bulkID string) (domain.BulkTransfer, error) {
+
+	bulk, err := service.BulkTransferByID(ctx, bulkID)
+	if err != nil || bulk.Time.IsZero() || bulk.CorporateID != corporate.ID {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk Not found")
+	}
+
+	if bulk.Status != domain.BULK_PENDING_APPROVAL_STATUS {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.BulkNotFound, "Bulk not waiting for approval")
+	}
+
+	if bulk.Owner.ID == approver.GetActorID() {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.InvalidBulkApprover, "Maker cannot approve own bulk")
+	}
+
+	if bulk.HasReviewed(approver.GetActorID()) {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.InvalidBulkApprover, "Approver already reviewed bulk")
+	}
+
+	err = usecase.ValidatePermission(ctx, corporate, approver, domain.PERMISSION_BULK_APPROVE)
+	if err != nil {
+		return domain.BulkTransfer{}, utils.ErrorBadRequest(utils.InvalidBulkApprover, "Approver has no approve permission")
+	}
+
+	err = usecase.ValidateActorPIN(ctx, approver, pin)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	err = usecase.ValidateAccessBalance(approver, bulk.BalanceID.Hex())
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	err = usecase.ValidateIsVerify(approver, domain.TRANSFER_BANK)
+	if err != nil {
+		return domain.BulkTransfer{}, err
+	}
+
+	return bulk, nil
+}
+
+// Bulk reviewed concurrently by another request is not waiting anymore
+func addBulkApproval(ctx context.Context, ID primitive.ObjectID, approval domain.BulkApproval) error {
+	added, err := service.BulkTransferAddApproval(ctx, ID, approval)
+	if err != nil {
+		return err
+	}
+
+	if !added {
+		return utils.ErrorBadRequest(utils.InvalidBulkApprover, "Bulk not waiting for approval or already reviewed")
+	}
+
+	return nil
+}
+
+func setBulkStatus(ctx context.Context, ID primitive.ObjectID, from string, to string) error {
+	set, err := service.BulkTransferSetStatus(ctx, ID, from, to)
+	if err != nil {
+		return err
+	}
+
+	if !set {
+		return utils.ErrorBadRequest(utils.BulkNotFound, "Bulk not waiting for approval")
+	}
+
+	return nil
+}
+
+func ViewBulkInquiry(ctx context.Context, bulkID string) (domain.BulkInquiry, error) {
 
-	bulk, err := service.BulkInquiryByID(bulkID)
+	bulk, err := service.BulkInquiryByID(ctx, bulkID)
 	if err != nil {
 		return domain.BulkInquiry{}, err
 	}
@@ -94,9 +345,9 @@ func ViewBulkInquiry(bulkID string) (domain.BulkInquiry, error) {
 	return bulk, nil
 }
 
-func ViewBulkTransfer(bulkID string) (domain.BulkTransfer, error) {
+func ViewBulkTransfer(ctx context.Context, bulkID string) (domain.BulkTransfer, error) {
 
-	bulk, err := service.BulkTransferByID(bulkID)
+	bulk, err := service.BulkTransferByID(ctx, bulkID)
 	if err != nil {
 		return domain.BulkTransfer{}, err
 	}
@@ -104,13 +355,14 @@ func ViewBulkTransfer(bulkID string) (domain.BulkTransfer, error) {
 	return bulk, nil
 }
 
-func executeBulkInquiry(corporate domain.Corporate, actor domain.ActorObject, bulk domain.BulkInquiry) {
+func executeBulkInquiry(ctx context.Context, corporate domain.Corporate, actor domain.ActorObject,
+	bulk domain.BulkInquiry) {
 	var result []domain.Inquiry
 	for _, inq := range bulk.List {
 
 		var a domain.Inquiry
 
-		bank, err := InquiryBankAccount(inq.AccountNumber, inq.BankName)
+		bank, err := InquiryBankAccount(ctx, inq.AccountNumber, inq.BankName)
 		if err != nil {
 			a.AccountName = inq.AccountName
 			a.AccountNumber = inq.AccountNumber
@@ -136,20 +388,21 @@ func executeBulkInquiry(corporate domain.Corporate, actor domain.ActorObject, bu
 
 	bulk.List = result
 	bulk.Status = domain.BULK_COMPLETED_STATUS
-	go service.BulkInquiryUpdateOne(&bulk)
+	go service.BulkInquiryUpdateOne(context.Background(), &bulk)
 
 	go usecase.PublishBulkCallback(corporate, actor, bulk.ID.Hex(), bulk.Status, corporate.BulkInquiryCallbackURL)
 }
 
-func executeBulkTransfer(corporate domain.Corporate, user domain.ActorAble, pin string, bulk domain.BulkTransfer) {
+func executeBulkTransfer(ctx context.Context, corporate domain.Corporate, user domain.ActorAble, pin string,
+	bulk domain.BulkTransfer) {
 
+	// Status already moved to progress by the execute request
 	bulk.Status = domain.BULK_PROGRESS_STATUS
-	service.BulkTransferUpdateOne(&bulk)
 
 	transfers := bulk.List
 	for index, transfer := range transfers {
 		usecase := UserTransferBank{}
-		trx, err := usecase.Execute(corporate, user, transfer.ToBankAccount.ToTransactionObject(),
+		trx, err := usecase.Execute(ctx, corporate, user, transfer.ToBankAccount.ToTransactionObject(),
 			bulk.BalanceID.Hex(), transfer.Amount, pin, transfer.ExternalID)
 		if err != nil {
 			err, ok := err.(utils.CustomError)
@@ -167,6 +420,6 @@ func executeBulkTransfer(corporate domain.Corporate, user domain.ActorAble, pin
 	}
 
 	bulk.Status = domain.BULK_COMPLETED_STATUS
-	service.BulkTransferUpdateOne(&bulk)
+	service.BulkTransferUpdateOne(ctx, &bulk)
 	go usecase.PublishBulkCallback(corporate, bulk.Owner, bulk.ID.Hex(), bulk.Status, corporate.BulkTransferCallbackURL)
 }
@@ -0,0 +1,50 @@
+package transfer_bank
+
+import (
+	"context"
+	"testing"
+
+	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/repository"
+	"github.com/takeme-id/core/service"
+	"github.com/takeme-id/core/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSaveBulkApprovalThresholds(t *testing.T) {
+	service.UseRepositories(repository.NewMemory())
+	t.Cleanup(func() {
+		service.UseRepositories(nil)
+	})
+
+	ctx := context.Background()
+	corporate := domain.Corporate{ID: primitive.NewObjectID(), Name: "Takeme"}
+	err := service.CorporateSave(ctx, corporate)
+	if err != nil {
+		t.Fatalf("save corporate: %v", err)
+	}
+
+	thresholds := []domain.BulkApprovalThreshold{{MinimumAmount: 1000000, RequiredApprovals: 2}}
+
+	viewer := domain.User{ID: primitive.NewObjectID(), CorporateID: corporate.ID, Role: domain.ROLE_VIEWER}
+	_, err = SaveBulkApprovalThresholds(ctx, corporate, viewer, thresholds)
+	customError, ok := err.(utils.CustomError)
+	if !ok || customError.Code != utils.PermissionDenied {
+		t.Fatalf("viewer error = %v, want permission denied", err)
+	}
+
+	admin := domain.User{ID: primitive.NewObjectID(), CorporateID: corporate.ID, Role: domain.ROLE_ADMIN}
+	_, err = SaveBulkApprovalThresholds(ctx, corporate, admin, thresholds)
+	if err != nil {
+		t.Fatalf("admin save: %v", err)
+	}
+
+	histories, err := BulkApprovalHistories(ctx, corporate, admin, "1", "10")
+	if err != nil || len(histories) != 1 {
+		t.Fatalf("histories = %+v, %v, want one", histories, err)
+	}
+
+	if histories[0].Actor.ID != admin.ID || len(histories[0].Previous) != 0 || len(histories[0].Current) != 1 {
+		t.Errorf("history = %+v", histories[0])
+	}
+}
@@ -1,14 +1,15 @@
 package transfer_bank
 
 import (
+	"context"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils/gateway"
 )
 
-func InquiryBankAccount(accountNumber string, bankCode string) (domain.Bank, error) {
+func InquiryBankAccount(ctx context.Context, accountNumber string, bankCode string) (domain.Bank, error) {
 	gateway := gateway.OYGateway{}
 
-	accountName, err := gateway.Inquiry(bankCode, accountNumber)
+	accountName, err := gateway.Inquiry(ctx, bankCode, accountNumber)
 	if err != nil {
 		return domain.Bank{}, err
 	}
@@ -1,8 +1,7 @@
 package transfer_bank
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -16,15 +15,15 @@ type RollbackTransferBank struct {
 	transactionUsecase transaction.Base
 }
 
-func (self *RollbackTransferBank) Initialize(rollbackTransaction domain.Transaction) error {
-	corporate, err := service.CorporateByIDNoSession(rollbackTransaction.CorporateID.Hex())
+func (self *RollbackTransferBank) Initialize(ctx context.Context, rollbackTransaction domain.Transaction) error {
+	corporate, err := service.CorporateByID(ctx, rollbackTransaction.CorporateID.Hex())
 	if err != nil {
 		return err
 	}
 
 	self.corporate = corporate
 
-	balance, err := service.BalanceByIDNoSession(rollbackTransaction.FromBalanceID.Hex())
+	balance, err := service.BalanceByID(ctx, rollbackTransaction.FromBalanceID.Hex())
 	if err != nil {
 		return err
 	}
@@ -37,13 +36,13 @@ func (self *RollbackTransferBank) Initialize(rollbackTransaction domain.Transact
 	return nil
 }
 
-func (self *RollbackTransferBank) ExecuteRollback() error {
+func (self *RollbackTransferBank) ExecuteRollback(ctx context.Context) error {
 	transactionStatement := service.DepositTransactionStatement(
-		self.balance.ID, time.Now().Format(os.Getenv("TIME_FORMAT")),
+		self.balance.ID, domain.TimestampNow(),
 		self.transaction.TransactionCode,
-		self.transaction.SubAmount)
+		self.transaction.SubAmountMoney())
 
-	feeStatements, err := self.transactionUsecase.RollbackFeeStatement(self.corporate, self.balance, self.transaction)
+	feeStatements, err := self.transactionUsecase.RollbackFeeStatement(ctx, self.corporate, self.balance, self.transaction)
 	if err != nil {
 		return err
 	}
@@ -52,7 +51,7 @@ func (self *RollbackTransferBank) ExecuteRollback() error {
 	statements = append(statements, transactionStatement)
 	statements = append(statements, feeStatements...)
 
-	err = self.transactionUsecase.CommitRollback(statements)
+	err = self.transactionUsecase.CommitRollback(ctx, statements)
 	if err != nil {
 		return err
 	}
@@ -3,21 +3,13 @@ package transfer_bank
 import (
 	"context"
 	"fmt"
-	"os"
-	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/usecase"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
 	"github.com/takeme-id/core/utils/gateway"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 type TransferBank struct {
@@ -83,7 +75,7 @@ func (self TransferBank) SetupGateway(transaction *domain.Transaction) {
 	}
 }
 
-func (self TransferBank) CreateTransferGateway(transaction domain.Transaction) {
+func (self TransferBank) CreateTransferGateway(ctx context.Context, transaction domain.Transaction) {
 	oy := gateway.OYGateway{}
 	mmbc := gateway.MMBCGateway{}
 	xendit := gateway.XenditGateway{}
@@ -94,33 +86,33 @@ func (self TransferBank) CreateTransferGateway(transaction domain.Transaction) {
 
 	switch gatewayCode {
 	case gateway.OY:
-		reference, err = oy.CreateTransfer(transaction)
+		reference, err = oy.CreateTransfer(ctx, transaction)
 	case gateway.MMBC:
-		reference, err = mmbc.CreateTransfer(transaction)
+		reference, err = mmbc.CreateTransfer(ctx, transaction)
 	case gateway.Xendit:
-		reference, err = xendit.CreateTransfer(transaction)
+		reference, err = xendit.CreateTransfer(ctx, transaction)
 	}
 
 	if gatewayCode == "" {
 		transaction.Status = domain.FAILED_STATUS
 
 		rollbackUsecase := RollbackTransferBank{}
-		rollbackUsecase.Initialize(transaction)
-		rollbackUsecase.ExecuteRollback()
+		rollbackUsecase.Initialize(ctx, transaction)
+		rollbackUsecase.ExecuteRollback(ctx)
 	}
 
-	commitTransactionGateway(transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
+	commitTransactionGateway(ctx, transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
 
 	if err != nil {
-		self.CreateTransferGateway(transaction)
+		self.CreateTransferGateway(ctx, transaction)
 		return
 	}
 
 	return
 }
 
-func (self TransferBank) ProcessCallbackGatewayTransfer(gatewayCode string, transactionCode string, reference string,
-	status string) (domain.Transaction, error) {
+func (self TransferBank) ProcessCallbackGatewayTransfer(ctx context.Context, gatewayCode string,
+	transactionCode string, reference string, status string) (domain.Transaction, error) {
 
 	var corporate domain.Corporate
 	var transaction domain.Transaction
@@ -128,12 +120,12 @@ func (self TransferBank) ProcessCallbackGatewayTransfer(gatewayCode string, tran
 	var err error
 
 	if status == domain.REFUND_STATUS {
-		transaction, err = service.TransactionByGatewayReferenceNoSession(reference)
-		corporate, err = service.CorporateByIDNoSession(transaction.CorporateID.Hex())
+		transaction, err = service.TransactionByGatewayReference(ctx, reference)
+		corporate, err = service.CorporateByID(ctx, transaction.CorporateID.Hex())
 		nextGateway = checkUnexecutedGateway(transaction)
 	} else {
-		transaction, err = service.TransactionPendingByReferenceNoSession(reference)
-		corporate, err = service.CorporateByIDNoSession(transaction.CorporateID.Hex())
+		transaction, err = service.TransactionPendingByReference(ctx, reference)
+		corporate, err = service.CorporateByID(ctx, transaction.CorporateID.Hex())
 		nextGateway = checkUnexecutedGateway(transaction)
 	}
 
@@ -146,17 +138,17 @@ func (self TransferBank) ProcessCallbackGatewayTransfer(gatewayCode string, tran
 	}
 
 	if nextGateway != "" && (status == domain.FAILED_STATUS || status == domain.REFUND_STATUS) {
-		go self.CreateTransferGateway(transaction)
+		go self.CreateTransferGateway(context.Background(), transaction)
 		return domain.Transaction{}, nil
 	}
 
 	if nextGateway == "" && (status == domain.FAILED_STATUS || status == domain.REFUND_STATUS) {
 		transaction.Status = domain.FAILED_STATUS
-		commitTransactionGateway(transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
+		commitTransactionGateway(ctx, transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
 
 		rollbackUsecase := RollbackTransferBank{}
-		rollbackUsecase.Initialize(transaction)
-		rollbackUsecase.ExecuteRollback()
+		rollbackUsecase.Initialize(ctx, transaction)
+		rollbackUsecase.ExecuteRollback(ctx)
 
 		go usecase.PublishTransferCallback(corporate, transaction)
 
@@ -164,7 +156,7 @@ func (self TransferBank) ProcessCallbackGatewayTransfer(gatewayCode string, tran
 	}
 
 	transaction.Status = domain.COMPLETED_STATUS
-	commitTransactionGateway(transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
+	commitTransactionGateway(ctx, transaction.ID.Hex(), transaction.Status, gatewayCode, reference, transaction.GatewayStrategies)
 
 	go usecase.PublishTransferCallback(corporate, transaction)
 
@@ -202,21 +194,11 @@ func checkUnexecutedGateway(transaction domain.Transaction) string {
 	return gatewayCode
 }
 
-func commitTransactionGateway(transactionID string, status string, gatewayCode string, reference string, gatewayStrategy []domain.GatewayStrategy) {
-	function := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			session.AbortTransaction(session)
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "Initialize commit gateway start transaction failed")
-		}
-
-		transaction, err := service.TransactionByID(transactionID, session)
+func commitTransactionGateway(ctx context.Context, transactionID string, status string, gatewayCode string,
+	reference string, gatewayStrategy []domain.GatewayStrategy) {
+	function := func(session context.Context) error {
+		transaction, err := service.TransactionByID(session, transactionID)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -224,7 +206,7 @@ func commitTransactionGateway(transactionID string, status string, gatewayCode s
 			history := domain.GatewayHistory{
 				Code:      gatewayCode,
 				Reference: reference,
-				Time:      time.Now().Format(os.Getenv("TIME_FORMAT")),
+				Time:      domain.TimestampNow(),
 			}
 			transaction.GatewayHistories = append(transaction.GatewayHistories, history)
 		}
@@ -234,21 +216,15 @@ func commitTransactionGateway(transactionID string, status string, gatewayCode s
 		transaction.GatewayReference = reference
 		transaction.GatewayStrategies = gatewayStrategy
 
-		err = service.TransactionUpdateOne(&transaction, session)
+		err = service.TransactionUpdateOne(session, &transaction)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, function)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, function)
 
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed commit gateway transaction with id  %v because %v ", transactionID, err.Error()))
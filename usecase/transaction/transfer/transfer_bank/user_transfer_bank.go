@@ -1,8 +1,7 @@
 package transfer_bank
 
 import (
-	"os"
-	"time"
+	"context"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/service"
@@ -25,15 +24,15 @@ type UserTransferBank struct {
 	transferBankBase   TransferBank
 }
 
-func (self UserTransferBank) Execute(corporate domain.Corporate, actor domain.ActorAble,
+func (self UserTransferBank) Execute(ctx context.Context, corporate domain.Corporate, actor domain.ActorAble,
 	to domain.TransactionObject, balanceID string, subAmount int, encryptedPIN string, externalID string) (domain.Transaction, error) {
 
-	balance, err := identifyBalance(balanceID)
+	balance, err := identifyBalance(ctx, balanceID)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	from, err := usecase.ActorObjectToActor(balance.Owner.ToActorObject())
+	from, err := usecase.ActorObjectToActor(ctx, balance.Owner.ToActorObject())
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -53,7 +52,7 @@ func (self UserTransferBank) Execute(corporate domain.Corporate, actor domain.Ac
 
 	transaction, transactionStatement := createTransaction(self.corporate, self.fromBalance, self.actor, self.from, to, subAmount, externalID)
 
-	feeStatement, err := self.transactionUsecase.CreateFeeStatement(corporate, self.fromBalance, transaction)
+	feeStatement, err := self.transactionUsecase.CreateFeeStatement(ctx, corporate, self.fromBalance, transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
@@ -66,30 +65,44 @@ func (self UserTransferBank) Execute(corporate domain.Corporate, actor domain.Ac
 		return domain.Transaction{}, err
 	}
 
-	err = validationActor(self.actor, self.fromBalance.ID.Hex(), self.pin)
+	err = validationActor(ctx, self.actor, self.fromBalance.ID.Hex(), self.pin)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	err = validationTransaction(transaction)
+	err = self.transactionUsecase.HoldOnLimit(ctx, corporate, self.actor, &transaction,
+		validationTransaction(ctx, corporate, from, transaction))
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	err = self.transactionUsecase.EvaluateFraud(ctx, corporate, self.actor, self.fromBalance, &transaction)
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
 	self.transferBankBase.SetupGateway(&transaction)
 
-	err = self.transactionUsecase.Commit(statements, &transaction)
+	err = self.transactionUsecase.Commit(ctx, statements, &transaction,
+		self.transactionUsecase.HoldOnBreach(corporate, self.actor, &transaction,
+			enforceTransaction(corporate, from, transaction)))
 	if err != nil {
 		return domain.Transaction{}, err
 	}
 
-	go self.transferBankBase.CreateTransferGateway(transaction)
+	// Continued by reviewer approval
+	if transaction.Status == domain.HELD_STATUS {
+		return transaction, nil
+	}
+
+	// Gateway call and its rollback outlive the request
+	go self.transferBankBase.CreateTransferGateway(context.Background(), transaction)
 
 	return transaction, nil
 }
 
-func identifyBalance(balanceID string) (domain.Balance, error) {
-	balance, err := service.BalanceByIDNoSession(balanceID)
+func identifyBalance(ctx context.Context, balanceID string) (domain.Balance, error) {
+	balance, err := service.BalanceByID(ctx, balanceID)
 	if err != nil {
 		return domain.Balance{}, utils.ErrorBadRequest(utils.InvalidBalanceID, "Balance id not found")
 	}
@@ -120,23 +133,23 @@ func createTransaction(corporate domain.Corporate, balance domain.Balance, actor
 		TotalFee:        totalFee,
 		SubAmount:       subAmount,
 		Amount:          subAmount + totalFee,
-		Time:            time.Now().Format(os.Getenv("TIME_FORMAT")),
+		Time:            domain.TimestampNow(),
 		Notes:           "",
 		Status:          domain.PENDING_STATUS,
 		Unpaid:          false,
 		ExternalID:      externalID,
-		Currency:        corporate.Currency,
+		Currency:        corporate.GetCurrency(),
 	}
 
 	statement := service.WithdrawTransactionStatement(
-		balance.ID, transcation.Time, transcation.TransactionCode, subAmount)
+		balance.ID, transcation.Time, transcation.TransactionCode, domain.NewMoney(subAmount, transcation.Currency))
 
 	return transcation, statement
 }
 
-func validationActor(actor domain.ActorAble, balanceID string, pin string) error {
+func validationActor(ctx context.Context, actor domain.ActorAble, balanceID string, pin string) error {
 
-	err := usecase.ValidateActorPIN(actor, pin)
+	err := usecase.ValidateActorPIN(ctx, actor, pin)
 	if err != nil {
 		return err
 	}
@@ -146,7 +159,7 @@ func validationActor(actor domain.ActorAble, balanceID string, pin string) error
 		return err
 	}
 
-	err = usecase.ValidateIsVerify(actor)
+	err = usecase.ValidateIsVerify(actor, domain.TRANSFER_BANK)
 	if err != nil {
 		return err
 	}
@@ -154,16 +167,15 @@ func validationActor(actor domain.ActorAble, balanceID string, pin string) error
 	return nil
 }
 
-func validationTransaction(transaction domain.Transaction) error {
-	err := validateMaximum(transaction)
-	if err != nil {
-		return err
-	}
+func validationTransaction(ctx context.Context, corporate domain.Corporate, from domain.ActorAble,
+	transaction domain.Transaction) error {
+	return usecase.ValidateOutflowLimit(ctx, corporate, from, transaction)
+}
 
-	err = validateMinimum(transaction)
-	if err != nil {
-		return err
+// Limit checked again inside the commit transaction
+func enforceTransaction(corporate domain.Corporate, from domain.ActorAble,
+	transaction domain.Transaction) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return usecase.EnforceOutflowLimit(ctx, corporate, from, transaction)
 	}
-
-	return nil
 }
@@ -10,107 +10,76 @@ import (
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/usecase/security"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 const (
-	SMS_CHANNEL = "sms"
-	WA_CHANNEL  = "wa"
+	SMS_CHANNEL = utils.NOTIFIER_SMS
+	WA_CHANNEL  = utils.NOTIFIER_WA
 )
 
-func UserSignup(fullName string, email string, phoneNumber string, corporate domain.Corporate, OTPChannel string) error {
+func UserSignup(ctx context.Context, fullName string, email string, phoneNumber string,
+	corporate domain.Corporate, OTPChannel string) error {
 
-	userSignup := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User prelogin start transaction failed")
-		}
+	var signedUp domain.User
+	activationCode := ""
 
+	userSignup := func(session context.Context) error {
 		// validate user fullname
-		err = service.ValidateUserFullname(fullName)
+		err := service.ValidateUserFullname(fullName)
 		if err != nil {
 			return err
 		}
 
 		// validate is user already exist
-		isPending, user, err := service.ValidateUserNotRegisteredYet(corporate, phoneNumber, email, session)
+		isPending, user, err := service.ValidateUserNotRegisteredYet(session, corporate, phoneNumber, email)
 		if err != nil {
 			return err
 		}
 
 		if isPending == false {
-			user, err = service.UserCreate(corporate, email, phoneNumber, fullName, session)
+			user, err = service.UserCreate(session, corporate, email, phoneNumber, fullName)
 			if err != nil {
 				return err
 			}
 		} else {
-			user, err = service.UserCreateUnpending(corporate, user, email, phoneNumber, fullName, session)
+			user, err = service.UserCreateUnpending(session, corporate, user, email, phoneNumber, fullName)
 			if err != nil {
 				return err
 			}
 		}
 
-		err = database.CommitWithRetry(session)
+		code, err := service.OTPIssue(session, user, domain.OTP_PURPOSE_ACTIVATION)
 		if err != nil {
 			return err
 		}
 
-		// sending SMS verification
-		message := fmt.Sprintf(
-			"Your signup number  %v",
-			user.ActivationCode,
-		)
-
-		if OTPChannel == SMS_CHANNEL {
-			go utils.SendSMS(phoneNumber, message)
-		} else {
-			go utils.SendWAHubungi(phoneNumber, user.ActivationCode)
-		}
-
-		go deleteInactiveUser(user.ID.Hex())
+		signedUp = user
+		activationCode = code
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userSignup)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userSignup)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	go service.OTPSend(context.Background(), corporate, signedUp, OTPChannel, domain.OTP_PURPOSE_ACTIVATION, activationCode)
 
-func UserActivation(phoneNumber string, corporate domain.Corporate, code string) (string, error) {
-	token := ""
+	go deleteInactiveUser(context.Background(), signedUp.ID.Hex())
 
-	userActivation := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	return nil
+}
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+func UserActivation(ctx context.Context, phoneNumber string, corporate domain.Corporate, code string,
+	deviceID string) (domain.AuthToken, error) {
+	token := domain.AuthToken{}
+	var activated domain.User
 
-		user, err := service.UserByPhoneNumber(corporate.ID, phoneNumber, session)
+	userActivation := func(session context.Context) error {
+		user, err := service.UserByPhoneNumber(session, corporate.ID, phoneNumber)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -119,326 +88,212 @@ func UserActivation(phoneNumber string, corporate domain.Corporate, code string)
 			return err
 		}
 
-		err = service.ValidateUserActivationCode(user, code)
+		err = service.OTPVerify(session, user, domain.OTP_PURPOSE_ACTIVATION, code)
 		if err != nil {
 			return err
 		}
 
-		err = service.UserActivate(&user, session)
+		err = service.UserActivate(session, &user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		// Generate JWT
-		tokenString, err := utils.JWTEncode(user, corporate)
+		err = ResolvePrivileges(session, corporate, &user)
 		if err != nil {
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		authToken, err := CreateUserSession(session, user, corporate, deviceID)
 		if err != nil {
 			return err
 		}
 
-		go InitializeBalanceUser(user, corporate, "Main")
-
-		token = tokenString
+		activated = user
+		token = authToken
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userActivation)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userActivation)
 
 	if err != nil {
-		return "", err
+		return domain.AuthToken{}, err
 	}
 
+	go InitializeBalanceUser(context.Background(), activated, corporate, "Main")
+
 	return token, nil
 }
 
-func UserPrelogin(phoneNumber string, corporate domain.Corporate, OTPChannel string) error {
+func UserPrelogin(ctx context.Context, phoneNumber string, corporate domain.Corporate, OTPChannel string) error {
 
-	userPrelogin := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	var loginUser domain.User
+	loginCode := ""
 
+	userPrelogin := func(session context.Context) error {
+		user, err := service.UserByPhoneNumber(session, corporate.ID, phoneNumber)
 		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User prelogin start transaction failed")
-		}
-
-		user, err := service.UserByPhoneNumber(corporate.ID, phoneNumber, session)
-		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
 		err = service.ValidateUserLocked(user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
 		err = service.ValidateUserLoginAttempt(user)
 		if err != nil {
-			go security.LockUser(user.ID.Hex())
+			go security.LockUser(context.Background(), user.ID.Hex())
 
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.UserGenerateLoginCode(&user, session)
+		code, err := service.OTPIssue(session, user, domain.OTP_PURPOSE_LOGIN)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserReduceLoginAttempt(session, &user)
 		if err != nil {
 			return err
 		}
 
-		// sending SMS login code
-		message := fmt.Sprintf(
-			"Your login number %v",
-			user.LoginCode,
-		)
-
-		if OTPChannel == SMS_CHANNEL {
-			go utils.SendSMS(phoneNumber, message)
-		} else {
-			go utils.SendWAHubungi(phoneNumber, user.LoginCode)
-		}
-
-		go userRemoveLoginCode(user.ID.Hex(), user.LoginCode)
+		loginUser = user
+		loginCode = code
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userPrelogin)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userPrelogin)
 
 	if err != nil {
 		return err
 	}
 
+	go service.OTPSend(context.Background(), corporate, loginUser, OTPChannel, domain.OTP_PURPOSE_LOGIN, loginCode)
+
 	return nil
 }
 
-func UserLogin(phoneNumber string, corporate domain.Corporate, code string) (string, error) {
-
-	token := ""
+// Device is registered with its public key, see trustLoginDevice
+func UserLogin(ctx context.Context, phoneNumber string, corporate domain.Corporate, code string, deviceID string,
+	deviceName string, devicePublicKey string, encryptedPIN string) (domain.AuthToken, error) {
 
-	userLogin := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	token := domain.AuthToken{}
 
+	userLogin := func(session context.Context) error {
+		user, err := service.UserByPhoneNumber(session, corporate.ID, phoneNumber)
 		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
+			return err
 		}
 
-		user, err := service.UserByPhoneNumber(corporate.ID, phoneNumber, session)
+		err = service.ValidateUserLocked(user)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.ValidateUserLocked(user)
+		err = service.OTPVerify(session, user, domain.OTP_PURPOSE_LOGIN, code)
 		if err != nil {
-			session.AbortTransaction(session)
+			// Reduce user access attempt
+			go security.InvalidUserAuth(context.Background(), user)
 			return err
 		}
 
-		err = service.ValidateUserLoginCode(user, code)
+		err = trustLoginDevice(session, &user, deviceID, deviceName, devicePublicKey, encryptedPIN)
 		if err != nil {
-			// Reduce user access attempt
-			go security.InvalidUserAuth(user)
-			session.AbortTransaction(session)
 			return err
 		}
 
-		// Generate JWT
-		tokenString, err := utils.JWTEncode(user, corporate)
+		err = ResolvePrivileges(session, corporate, &user)
 		if err != nil {
 			return err
 		}
 
-		err = service.UserRefreshAttempt(&user, session)
+		authToken, err := CreateUserSession(session, user, corporate, deviceID)
 		if err != nil {
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserRefreshAttempt(session, &user)
 		if err != nil {
 			return err
 		}
 
-		token = tokenString
+		token = authToken
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userLogin)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userLogin)
 
 	if err != nil {
-		return "", err
+		return domain.AuthToken{}, err
 	}
 
 	return token, nil
 }
 
-func UserFaceLogin(phoneNumber string, corporate domain.Corporate, faceImage string) (string, error) {
+// Face must come with an unused challenge issued to the bound device
+func UserFaceLogin(ctx context.Context, phoneNumber string, corporate domain.Corporate, faceImage string,
+	deviceID string, challengeID string, nonce string) (domain.AuthToken, error) {
 
-	token := ""
-
-	userLogin := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+	user, err := service.UserByPhoneNumberWithoutSession(ctx, corporate.ID, phoneNumber)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
 
-		user, err := service.UserByPhoneNumber(corporate.ID, phoneNumber, session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
+	err = verifyUserFace(ctx, corporate, user, domain.BIOMETRIC_PURPOSE_LOGIN, challengeID, nonce, deviceID, faceImage)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
 
-		err = service.ValidateUserLocked(user)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
+	token := domain.AuthToken{}
 
-		_, err = utils.EKYCVerifyUser(user.NIK, faceImage, user.DigitalID)
+	userLogin := func(session context.Context) error {
+		user, err := service.UserByID(session, user.ID.Hex())
 		if err != nil {
-			go security.InvalidUserAuth(user)
-			session.AbortTransaction(session)
 			return err
 		}
 
-		// Generate JWT
-		tokenString, err := utils.JWTEncode(user, corporate)
+		err = ResolvePrivileges(session, corporate, &user)
 		if err != nil {
 			return err
 		}
 
-		err = service.UserRefreshAttempt(&user, session)
+		authToken, err := CreateUserSession(session, user, corporate, deviceID)
 		if err != nil {
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserRefreshAttempt(session, &user)
 		if err != nil {
 			return err
 		}
 
-		token = tokenString
+		token = authToken
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userLogin)
-		},
-	)
+	err = service.Repositories().Transactor.WithTransaction(ctx, userLogin)
 
 	if err != nil {
-		return "", err
+		return domain.AuthToken{}, err
 	}
 
 	return token, nil
 }
 
-func userRemoveLoginCode(userID string, loginCode string) {
+func deleteInactiveUser(ctx context.Context, userID string) {
 	time.Sleep(120 * time.Second)
-	userRemoveLogin := func(session mongo.SessionContext) error {
-
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
+	userRemoveLogin := func(session context.Context) error {
+		user, err := service.UserByID(session, userID)
 		if err != nil {
 			return err
 		}
 
-		user, err := service.UserByID(userID, session)
+		err = service.UserDeleteInactive(session, &user)
 		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		if user.LoginCode == loginCode {
-			log.Info(fmt.Sprintf("Remove login code for user (%v)", user.PhoneNumber))
-			user.LoginCode = "-"
-			err := service.UserUpdateOne(&user, session)
-			if err != nil {
-				session.AbortTransaction(session)
-				return err
-			}
-		}
-
-		return database.CommitWithRetry(session)
-	}
-
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userRemoveLogin)
-		},
-	)
-
-	if err != nil {
-		log.Error(fmt.Sprintf("Failed remove login code for userID (%v)", userID))
-	}
-}
-
-func deleteInactiveUser(userID string) {
-	time.Sleep(120 * time.Second)
-	userRemoveLogin := func(session mongo.SessionContext) error {
-
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return err
-		}
-
-		user, err := service.UserByID(userID, session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = service.UserDeleteInactive(&user, session)
-		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
@@ -448,15 +303,10 @@ func deleteInactiveUser(userID string) {
 			log.Info("Delete unactive user success")
 		}
 
-		return database.CommitWithRetry(session)
+		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userRemoveLogin)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userRemoveLogin)
 
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed remove login code for userID (%v)", userID))
@@ -2,28 +2,18 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"mime/multipart"
-	"time"
 
-	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/domain/dto"
 	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/utils"
-	"github.com/takeme-id/core/utils/database"
-	"github.com/takeme-id/core/utils/storage"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
-func UserCheck(user domain.User) (dto.User, error) {
+func UserCheck(ctx context.Context, user domain.User) (dto.User, error) {
 
-	result, err := service.UserDTOByID(user.ID.Hex())
+	result, err := service.UserDTOByID(ctx, user.ID.Hex())
 	if err != nil {
 		return dto.User{}, err
 	}
@@ -31,86 +21,58 @@ func UserCheck(user domain.User) (dto.User, error) {
 	return result, nil
 }
 
-func UserUpgrade(user domain.User, nik string, faceImage string, deviceID string) error {
-	body, err := utils.EKYCEnrollUser(nik, faceImage)
+// Face matched by eKYC become the selfie of a full tier case, identity upload
+// is needed only when the case has none yet
+func UserUpgrade(ctx context.Context, user domain.User, nik string, faceImage string, deviceID string,
+	uploads ...KYCUpload) (domain.KYCCase, error) {
+	corporate, err := service.CorporateByID(ctx, user.CorporateID.Hex())
 	if err != nil {
-		return err
+		return domain.KYCCase{}, err
 	}
 
-	userUpgrade := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
-
-		user, err = service.UserByID(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = service.UserVerify(&user, body.DeviceID, body.NIK, body.DigitalID, session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = database.CommitWithRetry(session)
-		if err != nil {
-			return err
-		}
-
-		return nil
-
-	}
-
-	err = database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userUpgrade)
-		},
-	)
-
+	body, err := service.IdentityEnroll(corporate, nik, faceImage, deviceID)
 	if err != nil {
-		return err
+		return domain.KYCCase{}, err
 	}
 
-	return nil
+	documents, err := saveKYCUploads(uploads)
+	if err != nil {
+		return domain.KYCCase{}, err
+	}
+
+	documents = append(documents, domain.KYCDocument{
+		Type:  domain.KYC_DOCUMENT_SELFIE,
+		File:  body.Provider + ":" + body.TransactionID,
+		Score: body.MatchScore,
+		Time:  domain.TimestampNow(),
+	})
+
+	return SubmitKYC(ctx, user, KYCSubmission{
+		Tier:      domain.KYC_TIER_FULL,
+		NIK:       nik,
+		DigitalID: body.DigitalID,
+		DeviceID:  body.DeviceID,
+		Documents: documents,
+	})
 }
 
-func UserSaveBankAccount(user domain.User, name string, bankCode string, accountNumber string) error {
+func UserSaveBankAccount(ctx context.Context, user domain.User, name string, bankCode string,
+	accountNumber string) error {
 	account := domain.Bank{
 		Name:          name,
 		BankCode:      bankCode,
 		AccountNumber: accountNumber,
 	}
 
-	userSaveBank := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	userSaveBank := func(session context.Context) error {
+		var err error
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
-
-		user, err = service.UserByID(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = service.UserAddBankAccount(&user, account, session)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserAddBankAccount(session, &user, account)
 		if err != nil {
 			return err
 		}
@@ -118,12 +80,7 @@ func UserSaveBankAccount(user domain.User, name string, bankCode string, account
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userSaveBank)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userSaveBank)
 
 	if err != nil {
 		return err
@@ -132,35 +89,23 @@ func UserSaveBankAccount(user domain.User, name string, bankCode string, account
 	return nil
 }
 
-func UserDeleteBankAccount(user domain.User, name string, bankCode string, accountNumber string) error {
+func UserDeleteBankAccount(ctx context.Context, user domain.User, name string, bankCode string,
+	accountNumber string) error {
 	account := domain.Bank{
 		Name:          name,
 		BankCode:      bankCode,
 		AccountNumber: accountNumber,
 	}
 
-	userDeleteBank := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+	userDeleteBank := func(session context.Context) error {
+		var err error
 
-		user, err = service.UserByID(user.ID.Hex(), session)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.UserRemoveBankAccount(&user, account, session)
-		if err != nil {
-			return err
-		}
-
-		err = database.CommitWithRetry(session)
+		err = service.UserRemoveBankAccount(session, &user, account)
 		if err != nil {
 			return err
 		}
@@ -168,12 +113,7 @@ func UserDeleteBankAccount(user domain.User, name string, bankCode string, accou
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userDeleteBank)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userDeleteBank)
 
 	if err != nil {
 		return err
@@ -182,10 +122,10 @@ func UserDeleteBankAccount(user domain.User, name string, bankCode string, accou
 	return nil
 }
 
-func CheckUserPhonebook(corporate domain.Corporate, phonebook []domain.Contact) []domain.Contact {
+func CheckUserPhonebook(ctx context.Context, corporate domain.Corporate, phonebook []domain.Contact) []domain.Contact {
 	var members []domain.Contact
 	for _, contact := range phonebook {
-		isExist, name := isPhoneNumberAlreadyExist(corporate, contact.Number)
+		isExist, name := isPhoneNumberAlreadyExist(ctx, corporate, contact.Number)
 
 		if isExist {
 			members = append(members, domain.Contact{
@@ -198,9 +138,9 @@ func CheckUserPhonebook(corporate domain.Corporate, phonebook []domain.Contact)
 	return members
 }
 
-func isPhoneNumberAlreadyExist(corporate domain.Corporate, phoneNumber string) (bool, string) {
+func isPhoneNumberAlreadyExist(ctx context.Context, corporate domain.Corporate, phoneNumber string) (bool, string) {
 
-	user, err := service.UserByPhoneNumberWithoutSession(corporate.ID, phoneNumber)
+	user, err := service.UserByPhoneNumberWithoutSession(ctx, corporate.ID, phoneNumber)
 	if err != nil {
 		return false, ""
 	}
@@ -208,30 +148,16 @@ func isPhoneNumberAlreadyExist(corporate domain.Corporate, phoneNumber string) (
 	return true, user.FullName
 }
 
-func UserSavePIN(user domain.User, encryptedPIN string) error {
-	userSavePIN := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+func UserSavePIN(ctx context.Context, user domain.User, encryptedPIN string) error {
+	userSavePIN := func(session context.Context) error {
+		var err error
 
-		user, err = service.UserByID(user.ID.Hex(), session)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.UserSavePIN(&user, encryptedPIN, session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = database.CommitWithRetry(session)
+		err = service.UserSavePIN(session, &user, encryptedPIN)
 		if err != nil {
 			return err
 		}
@@ -239,12 +165,7 @@ func UserSavePIN(user domain.User, encryptedPIN string) error {
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userSavePIN)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userSavePIN)
 
 	if err != nil {
 		return err
@@ -253,97 +174,66 @@ func UserSavePIN(user domain.User, encryptedPIN string) error {
 	return nil
 }
 
-func UserPreForgotPIN(user domain.User, encryptedPIN string, OTPChannel string) error {
+func UserPreForgotPIN(ctx context.Context, user domain.User, encryptedPIN string, OTPChannel string) error {
 
-	preForgotPIN := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+	var corporate domain.Corporate
+	forgotCode := ""
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+	preForgotPIN := func(session context.Context) error {
+		var err error
 
-		user, err = service.UserByID(user.ID.Hex(), session)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		changePINCode, err := service.UserGenerateChangePINCode(&user, encryptedPIN, session)
+		corporate, err = service.CorporateByID(session, user.CorporateID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		code, err := service.OTPIssue(session, user, domain.OTP_PURPOSE_FORGOT_PIN)
 		if err != nil {
 			return err
 		}
 
-		// sending SMS code
-		phoneNumber := user.PhoneNumber
-		userID := user.ID.Hex()
-		message := fmt.Sprintf("Your forgot number %v",
-			changePINCode)
-
-		if OTPChannel == SMS_CHANNEL {
-			go utils.SendSMS(phoneNumber, message)
-		} else {
-			go utils.SendWAHubungi(phoneNumber, changePINCode)
+		err = service.UserSaveChangePIN(session, &user, encryptedPIN)
+		if err != nil {
+			return err
 		}
 
-		go removeForgotPINCode(userID, changePINCode)
+		forgotCode = code
 
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, preForgotPIN)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, preForgotPIN)
 
 	if err != nil {
 		return err
 	}
 
+	go service.OTPSend(context.Background(), corporate, user, OTPChannel, domain.OTP_PURPOSE_FORGOT_PIN, forgotCode)
+
 	return nil
 }
 
-func UserForgotPIN(user domain.User, code string) error {
-
-	forgotPIN := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+func UserForgotPIN(ctx context.Context, user domain.User, code string) error {
 
-		user, err = service.UserByID(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
+	forgotPIN := func(session context.Context) error {
+		var err error
 
-		err = service.ValidateUserChangePINCode(user, code)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = service.UserChangePIN(&user, session)
+		err = service.OTPVerify(session, user, domain.OTP_PURPOSE_FORGOT_PIN, code)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserChangePIN(session, &user)
 		if err != nil {
 			return err
 		}
@@ -351,12 +241,7 @@ func UserForgotPIN(user domain.User, code string) error {
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, forgotPIN)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, forgotPIN)
 
 	if err != nil {
 		return err
@@ -365,26 +250,11 @@ func UserForgotPIN(user domain.User, code string) error {
 	return nil
 }
 
-func UserMainBalanceVA(user domain.User) ([]domain.VirtualAccount, error) {
+func UserMainBalanceVA(ctx context.Context, user domain.User) ([]domain.VirtualAccount, error) {
 	var va []domain.VirtualAccount
 
-	userMainBalanceVA := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
-
-		balance, err := service.BalanceByID(user.MainBalance.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = database.CommitWithRetry(session)
+	userMainBalanceVA := func(session context.Context) error {
+		balance, err := service.BalanceByID(session, user.MainBalance.Hex())
 		if err != nil {
 			return err
 		}
@@ -394,12 +264,7 @@ func UserMainBalanceVA(user domain.User) ([]domain.VirtualAccount, error) {
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userMainBalanceVA)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userMainBalanceVA)
 
 	if err != nil {
 		return []domain.VirtualAccount{}, err
@@ -408,18 +273,9 @@ func UserMainBalanceVA(user domain.User) ([]domain.VirtualAccount, error) {
 	return va, nil
 }
 
-func UserChangePIN(user domain.User, encryptedOldPIN string, encryptedNewPIN string) error {
-
-	userChangePIN := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
+func UserChangePIN(ctx context.Context, user domain.User, encryptedOldPIN string, encryptedNewPIN string) error {
 
+	userChangePIN := func(session context.Context) error {
 		newPIN, err := utils.RSADecrypt(encryptedNewPIN)
 		if err != nil {
 			return err
@@ -430,24 +286,17 @@ func UserChangePIN(user domain.User, encryptedOldPIN string, encryptedNewPIN str
 			return err
 		}
 
-		user, err = service.UserByID(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = ValidateActorPIN(user, encryptedOldPIN)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
 			return err
 		}
 
-		err = service.UserChangeNewPIN(&user, newPIN, session)
+		err = ValidateActorPIN(ctx, user, encryptedOldPIN)
 		if err != nil {
-			session.AbortTransaction(session)
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserChangeNewPIN(session, &user, newPIN)
 		if err != nil {
 			return err
 		}
@@ -455,12 +304,7 @@ func UserChangePIN(user domain.User, encryptedOldPIN string, encryptedNewPIN str
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userChangePIN)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userChangePIN)
 
 	if err != nil {
 		return err
@@ -469,29 +313,16 @@ func UserChangePIN(user domain.User, encryptedOldPIN string, encryptedNewPIN str
 	return nil
 }
 
-func UserChangeFaceAsPIN(user domain.User, isFaceAsPIN bool) error {
-	userChangeFaceAsPIN := func(session mongo.SessionContext) error {
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
+func UserChangeFaceAsPIN(ctx context.Context, user domain.User, isFaceAsPIN bool) error {
+	userChangeFaceAsPIN := func(session context.Context) error {
+		var err error
 
-		if err != nil {
-			return utils.ErrorInternalServer(utils.DBStartTransactionFailed, "User login start transaction failed")
-		}
-
-		user, err = service.UserByID(user.ID.Hex(), session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		err = service.UserChangeFaceAsPIN(&user, isFaceAsPIN, session)
+		user, err = service.UserByID(session, user.ID.Hex())
 		if err != nil {
 			return err
 		}
 
-		err = database.CommitWithRetry(session)
+		err = service.UserChangeFaceAsPIN(session, &user, isFaceAsPIN)
 		if err != nil {
 			return err
 		}
@@ -499,12 +330,7 @@ func UserChangeFaceAsPIN(user domain.User, isFaceAsPIN bool) error {
 		return nil
 	}
 
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userChangeFaceAsPIN)
-		},
-	)
+	err := service.Repositories().Transactor.WithTransaction(ctx, userChangeFaceAsPIN)
 
 	if err != nil {
 		return err
@@ -513,7 +339,7 @@ func UserChangeFaceAsPIN(user domain.User, isFaceAsPIN bool) error {
 	return nil
 }
 
-func UserTransactions(user domain.User, page string, limit string) ([]domain.Transaction, error) {
+func UserTransactions(ctx context.Context, user domain.User, page string, limit string) ([]domain.Transaction, error) {
 	var ownBalances []primitive.ObjectID
 
 	for _, index := range user.ListBalance {
@@ -522,7 +348,7 @@ func UserTransactions(user domain.User, page string, limit string) ([]domain.Tra
 		}
 	}
 
-	transactions, err := service.TransactionsByActorNoSession(user.PhoneNumber, ownBalances, page, limit)
+	transactions, err := service.TransactionsByActor(ctx, user.PhoneNumber, ownBalances, page, limit)
 	if err != nil {
 		return []domain.Transaction{}, err
 	}
@@ -530,120 +356,62 @@ func UserTransactions(user domain.User, page string, limit string) ([]domain.Tra
 	return transactions, nil
 }
 
-func UserTemporaryPIN(faceImage string, user domain.User) (string, error) {
-	_, err := utils.EKYCVerifyUser(user.NIK, faceImage, user.DigitalID)
-	if err != nil {
-		return "", err
-	}
-
-	temporaryPIN := generateTemporaryPIN(user)
-	go removeTemporaryPIN(user)
-
-	return temporaryPIN, nil
-}
-
-func UserVerify(aktaImage multipart.File, aktaHeader *multipart.FileHeader,
-	npwpImage multipart.File, npwpHeader *multipart.FileHeader, nibImage multipart.File,
-	nibHeader *multipart.FileHeader, identityImage multipart.File, identityHeader *multipart.FileHeader,
-	nik string, legalName string, legalAddress string, userID string, verifyType string) error {
+// Face used as PIN give a single use temporary PIN, it expire after
+// TEMPORARY_PIN_EXPIRED_SECOND
+func UserTemporaryPIN(ctx context.Context, faceImage string, user domain.User, deviceID string, challengeID string,
+	nonce string) (string, error) {
 
-	user, err := service.UserByIDNoSession(userID)
+	corporate, err := service.CorporateByID(ctx, user.CorporateID.Hex())
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	err, akta := storage.SaveFile(aktaImage, *aktaHeader)
+	err = verifyUserFace(ctx, corporate, user, domain.BIOMETRIC_PURPOSE_TEMPORARY_PIN, challengeID, nonce, deviceID, faceImage)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	err, npwp := storage.SaveFile(npwpImage, *npwpHeader)
-	if err != nil {
-		return err
-	}
+	temporaryPIN := ""
+	userTemporaryPIN := func(session context.Context) error {
+		code, err := service.OTPIssue(session, user, domain.OTP_PURPOSE_TEMPORARY_PIN)
+		if err != nil {
+			return err
+		}
 
-	err, nib := storage.SaveFile(nibImage, *nibHeader)
-	if err != nil {
-		return err
-	}
+		temporaryPIN = code
 
-	err, identity := storage.SaveFile(identityImage, *identityHeader)
-	if err != nil {
-		return err
+		return nil
 	}
 
-	user.VerifyData.AktaImage = akta
-	user.VerifyData.NPWPImage = npwp
-	user.VerifyData.NIBImage = nib
-	user.VerifyData.IdentityImage = identity
-	user.VerifyData.NIK = nik
-	user.VerifyData.LegalName = legalName
-	user.VerifyData.LegalAddress = legalAddress
-	user.VerifyData.Type = verifyType
-	user.Verified = true
+	err = service.Repositories().Transactor.WithTransaction(ctx, userTemporaryPIN)
 
-	err = service.UserUpdateOneNoSession(&user)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return temporaryPIN, nil
 }
 
-func removeForgotPINCode(userID string, code string) {
-	time.Sleep(120 * time.Second)
-	userRemoveForgot := func(session mongo.SessionContext) error {
-
-		err := session.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority())),
-		)
-
-		if err != nil {
-			return err
-		}
-
-		user, err := service.UserByID(userID, session)
-		if err != nil {
-			session.AbortTransaction(session)
-			return err
-		}
-
-		if user.ChangePINCode == code {
-			log.Info(fmt.Sprintf("Remove login code for user (%v)", user.PhoneNumber))
-			user.ChangePINCode = "-"
-			err := service.UserUpdateOne(&user, session)
-			if err != nil {
-				session.AbortTransaction(session)
-				return err
-			}
-		}
-
-		return database.CommitWithRetry(session)
-	}
-
-	err := database.DBClient.UseSessionWithOptions(
-		context.TODO(), options.Session().SetDefaultReadPreference(readpref.Primary()),
-		func(sctx mongo.SessionContext) error {
-			return database.RunTransactionWithRetry(sctx, userRemoveForgot)
-		},
-	)
+// Document upload open a KYC case, user is verified only after review
+func UserVerify(ctx context.Context, aktaImage multipart.File, aktaHeader *multipart.FileHeader,
+	npwpImage multipart.File, npwpHeader *multipart.FileHeader, nibImage multipart.File,
+	nibHeader *multipart.FileHeader, identityImage multipart.File, identityHeader *multipart.FileHeader,
+	nik string, legalName string, legalAddress string, userID string, verifyType string) (domain.KYCCase, error) {
 
+	user, err := service.UserByID(ctx, userID)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed remove preforgot code for userID (%v)", userID))
+		return domain.KYCCase{}, err
 	}
-}
-
-func generateTemporaryPIN(user domain.User) string {
-	temporaryPIN := utils.GenerateShortCode()
-	user.TemporaryPIN = temporaryPIN
-	database.UpdateOne(domain.USER_COLLECTION, &user)
 
-	return temporaryPIN
-}
+	tier := domain.KYC_TIER_BASIC
+	if verifyType == domain.VERIFY_ORGANIZATION_TYPE {
+		tier = domain.KYC_TIER_ORGANIZATION
+	}
 
-func removeTemporaryPIN(user domain.User) {
-	time.Sleep(600 * time.Second)
-	user.TemporaryPIN = " "
-	database.UpdateOne(domain.USER_COLLECTION, &user)
+	return SubmitKYCDocuments(ctx, user, tier, nik, legalName, legalAddress, []KYCUpload{
+		{Type: domain.KYC_DOCUMENT_IDENTITY, File: identityImage, Header: identityHeader},
+		{Type: domain.KYC_DOCUMENT_AKTA, File: aktaImage, Header: aktaHeader},
+		{Type: domain.KYC_DOCUMENT_NPWP, File: npwpImage, Header: npwpHeader},
+		{Type: domain.KYC_DOCUMENT_NIB, File: nibImage, Header: nibHeader},
+	})
 }
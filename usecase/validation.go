@@ -1,7 +1,12 @@
 package usecase
 
 import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
+	"github.com/takeme-id/core/service"
 	"github.com/takeme-id/core/usecase/security"
 	"github.com/takeme-id/core/utils"
 )
@@ -16,7 +21,7 @@ func ValidateFormatPIN(pin string) error {
 	return nil
 }
 
-func ValidateActorPIN(actor domain.ActorAble, pinEncrypted string) error {
+func ValidateActorPIN(ctx context.Context, actor domain.ActorAble, pinEncrypted string) error {
 
 	if actor.IsFaceAsPIN() == false {
 		if pinEncrypted == "" {
@@ -31,19 +36,24 @@ func ValidateActorPIN(actor domain.ActorAble, pinEncrypted string) error {
 			}
 		}
 
-		if pin != actor.GetPIN() {
+		valid, needRehash := utils.VerifyPIN(actor.GetPIN(), pin)
+		if !valid {
 
 			a, ok := actor.(domain.User)
 			if ok {
-				go security.InvalidUserAuth(a)
+				go security.InvalidUserAuth(context.Background(), a)
 			} else {
 				a, _ := actor.(domain.Corporate)
-				go security.InvalidCorporateAuth(a)
+				go security.InvalidCorporateAuth(context.Background(), a)
 			}
 
 			return utils.ErrorForbidden()
 		}
 
+		if needRehash {
+			migratePINHash(ctx, actor, pin)
+		}
+
 		return nil
 	} else {
 		pin, err := utils.RSADecrypt(pinEncrypted)
@@ -52,16 +62,15 @@ func ValidateActorPIN(actor domain.ActorAble, pinEncrypted string) error {
 			return utils.ErrorInternalServer(utils.DecryptError, "Decrypt error")
 		}
 
-		if pin != actor.GetTemporaryPIN() {
-
-			a, ok := actor.(domain.User)
-			if ok {
-				go security.InvalidUserAuth(a)
-			} else {
-				a, _ := actor.(domain.Corporate)
-				go security.InvalidCorporateAuth(a)
-			}
+		// Only user can use face as PIN, the temporary PIN is single use
+		user, ok := actor.(domain.User)
+		if !ok {
+			return utils.ErrorForbidden()
+		}
 
+		err = service.OTPConsume(ctx, user, domain.OTP_PURPOSE_TEMPORARY_PIN, pin)
+		if err != nil {
+			go security.InvalidUserAuth(context.Background(), user)
 			return utils.ErrorForbidden()
 		}
 
@@ -87,10 +96,10 @@ func ValidateAccessBalance(actor domain.ActorAble, balanceID string) error {
 	if isFound == false {
 		a, ok := actor.(domain.User)
 		if ok {
-			go security.InvalidUserAuth(a)
+			go security.InvalidUserAuth(context.Background(), a)
 		} else {
 			a, _ := actor.(domain.Corporate)
-			go security.InvalidCorporateAuth(a)
+			go security.InvalidCorporateAuth(context.Background(), a)
 		}
 
 		return utils.ErrorBadRequest(utils.InvalidBalanceAccess, "Invalid balance access")
@@ -131,10 +140,30 @@ func IsAccessBalanceAlreadyHave(actor domain.ActorAble, balanceID string) bool {
 	return isFound
 }
 
-func ValidateIsVerify(actor domain.ActorAble) error {
+// Actor must be verified and its KYC tier must grant the transaction type
+func ValidateIsVerify(actor domain.ActorAble, transactionType string) error {
 	if actor.IsVerify() == false {
 		return utils.ErrorBadRequest(utils.UpgradeAccountFirst, "Unverified user attempt to transfer")
 	}
 
+	if !domain.IsTransactionAllowedForTier(actor.GetKYCTier(), transactionType) {
+		return utils.ErrorBadRequest(utils.UpgradeAccountFirst, "KYC tier not allowed for "+transactionType)
+	}
+
 	return nil
 }
+
+// Plaintext or outdated PIN hash is replaced on successful PIN entry,
+// failure is only logged so the actor can still continue
+func migratePINHash(ctx context.Context, actor domain.ActorAble, pin string) {
+	var err error
+	if actor.GetActorType() == domain.ACTOR_TYPE_USER {
+		err = service.UserUpdatePINHash(ctx, actor.GetActorID(), pin)
+	} else {
+		err = service.CorporateUpdatePINHash(ctx, actor.GetActorID(), pin)
+	}
+
+	if err != nil {
+		log.Error(fmt.Sprintf("Migrate PIN hash of %v failed because %v", actor.GetActorID().Hex(), err.Error()))
+	}
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	crand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"strconv"
 	"time"
@@ -17,6 +19,17 @@ func GenerateShortCode() string {
 	return strconv.Itoa(rand.Intn((max - min) + min))
 }
 
+// Numeric code from crypto/rand, used for every code sent to user
+func GenerateSecureCode(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	number, err := crand.Int(crand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", digits, number), nil
+}
+
 func GenerateMediumCode() string {
 	rand.Seed(time.Now().UnixNano())
 	min := 1000000000
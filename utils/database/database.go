@@ -15,13 +15,13 @@ import (
 
 var DBClient *mongo.Client
 
-func FindCount(colName string, query bson.M) (int64, error) {
+func FindCount(ctx context.Context, colName string, query bson.M) (int64, error) {
 
 	opts := options.CountOptions{}
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	total, err := collection.CountDocuments(
-		context.TODO(),
+		ctx,
 		query,
 		&opts,
 	)
@@ -33,11 +33,11 @@ func FindCount(colName string, query bson.M) (int64, error) {
 	return total, nil
 }
 
-func FindWithJoin(colName string, query []bson.M) (*mongo.Cursor, error) {
+func FindWithJoin(ctx context.Context, colName string, query []bson.M) (*mongo.Cursor, error) {
 	a := true
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Aggregate(
-		context.TODO(),
+		ctx,
 		query,
 		&options.AggregateOptions{
 			AllowDiskUse: &a,
@@ -52,27 +52,27 @@ func FindWithJoin(colName string, query []bson.M) (*mongo.Cursor, error) {
 	return cursor, nil
 }
 
-func FindOneByID(colName string, ID string) *mongo.SingleResult {
+func FindOneByID(ctx context.Context, colName string, ID string) *mongo.SingleResult {
 	objectID, _ := primitive.ObjectIDFromHex(ID)
 
 	query := bson.M{"_id": objectID}
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
-	result := collection.FindOne(context.TODO(), query)
+	result := collection.FindOne(ctx, query)
 
 	return result
 }
 
-func FindOne(colName string, query bson.M) *mongo.SingleResult {
+func FindOne(ctx context.Context, colName string, query bson.M) *mongo.SingleResult {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
-	result := collection.FindOne(context.TODO(), query)
+	result := collection.FindOne(ctx, query)
 
 	return result
 }
 
-func Find(colName string, query bson.M, page string, limit string) (*mongo.Cursor, error) {
+func Find(ctx context.Context, colName string, query bson.M, page string, limit string) (*mongo.Cursor, error) {
 
 	opts := options.Find()
-	opts.SetSort(bson.D{{"time", -1}})
+	opts.SetSort(bson.D{{Key: "time", Value: -1}})
 
 	if page != "" && limit != "" {
 		p, _ := strconv.Atoi(page)
@@ -83,7 +83,7 @@ func Find(colName string, query bson.M, page string, limit string) (*mongo.Curso
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Find(
-		context.TODO(),
+		ctx,
 		query,
 		opts,
 	)
@@ -94,10 +94,11 @@ func Find(colName string, query bson.M, page string, limit string) (*mongo.Curso
 	return cursor, nil
 }
 
-func FindOrderByID(colName string, query bson.M, page string, limit string) (*mongo.Cursor, error) {
+func FindOrderByID(ctx context.Context, colName string, query bson.M, page string,
+	limit string) (*mongo.Cursor, error) {
 
 	opts := options.Find()
-	opts.SetSort(bson.D{{"_id", -1}})
+	opts.SetSort(bson.D{{Key: "_id", Value: -1}})
 
 	if page != "" && limit != "" {
 		p, _ := strconv.Atoi(page)
@@ -108,7 +109,7 @@ func FindOrderByID(colName string, query bson.M, page string, limit string) (*mo
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Find(
-		context.TODO(),
+		ctx,
 		query,
 		opts,
 	)
@@ -119,10 +120,11 @@ func FindOrderByID(colName string, query bson.M, page string, limit string) (*mo
 	return cursor, nil
 }
 
-func FindAllOrderByID(colName string, query bson.M, page string, limit string) (*mongo.Cursor, error) {
+func FindAllOrderByID(ctx context.Context, colName string, query bson.M, page string,
+	limit string) (*mongo.Cursor, error) {
 
 	opts := options.Find()
-	opts.SetSort(bson.D{{"_id", -1}})
+	opts.SetSort(bson.D{{Key: "_id", Value: -1}})
 
 	if page != "" && limit != "" {
 		p, _ := strconv.Atoi(page)
@@ -133,7 +135,7 @@ func FindAllOrderByID(colName string, query bson.M, page string, limit string) (
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Find(
-		context.TODO(),
+		ctx,
 		query,
 		opts,
 	)
@@ -144,10 +146,10 @@ func FindAllOrderByID(colName string, query bson.M, page string, limit string) (
 	return cursor, nil
 }
 
-func Aggregate(colName string, query []bson.M) (*mongo.Cursor, error) {
+func Aggregate(ctx context.Context, colName string, query []bson.M) (*mongo.Cursor, error) {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Aggregate(
-		context.TODO(),
+		ctx,
 		query,
 	)
 
@@ -158,13 +160,13 @@ func Aggregate(colName string, query []bson.M) (*mongo.Cursor, error) {
 	return cursor, nil
 }
 
-func IsExist(colName string, query bson.M) (*mongo.Cursor, error) {
+func IsExist(ctx context.Context, colName string, query bson.M) (*mongo.Cursor, error) {
 	opts := options.Find()
 	opts.SetLimit(1)
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	cursor, err := collection.Find(
-		context.TODO(),
+		ctx,
 		query,
 		opts,
 	)
@@ -175,10 +177,10 @@ func IsExist(colName string, query bson.M) (*mongo.Cursor, error) {
 	return cursor, nil
 }
 
-func SaveOne(colName string, domain domain.BaseModel) error {
+func SaveOne(ctx context.Context, colName string, domain domain.BaseModel) error {
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
-	result, err := collection.InsertOne(context.TODO(), domain)
+	result, err := collection.InsertOne(ctx, domain)
 	if err != nil {
 		return utils.ErrorInternalServer(utils.InsertFailed, err.Error())
 	}
@@ -189,7 +191,7 @@ func SaveOne(colName string, domain domain.BaseModel) error {
 	return nil
 }
 
-func UpdateOne(colName string, domain domain.BaseModel) error {
+func UpdateOne(ctx context.Context, colName string, domain domain.BaseModel) error {
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	document, err := toDoc(domain)
@@ -201,7 +203,7 @@ func UpdateOne(colName string, domain domain.BaseModel) error {
 	update := bson.M{"$set": document}
 
 	_, err = collection.UpdateOne(
-		context.TODO(),
+		ctx,
 		filter,
 		update,
 	)
@@ -212,14 +214,14 @@ func UpdateOne(colName string, domain domain.BaseModel) error {
 	return nil
 }
 
-func UpdateQuery(colName string, id primitive.ObjectID, update bson.M) error {
+func UpdateQuery(ctx context.Context, colName string, id primitive.ObjectID, update bson.M) error {
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 
 	filter := bson.M{"_id": bson.M{"$eq": id}}
 
 	_, err := collection.UpdateOne(
-		context.TODO(),
+		ctx,
 		filter,
 		update,
 	)
@@ -230,9 +232,8 @@ func UpdateQuery(colName string, id primitive.ObjectID, update bson.M) error {
 	return nil
 }
 
-func UpdateMany(domains []domain.BaseModel) error {
+func UpdateMany(ctx context.Context, domains []domain.BaseModel) error {
 
-	ctx := context.TODO()
 	session, err := DBClient.StartSession()
 	if err != nil {
 		return utils.ErrorInternalServer(utils.UpdateFailed, err.Error())
@@ -279,10 +280,10 @@ func UpdateMany(domains []domain.BaseModel) error {
 	return nil
 }
 
-func Update(colName string, filter bson.M, changes bson.D) (*mongo.UpdateResult, error) {
+func Update(ctx context.Context, colName string, filter bson.M, changes bson.D) (*mongo.UpdateResult, error) {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	result, err := collection.UpdateMany(
-		context.TODO(),
+		ctx,
 		filter,
 		changes,
 	)
@@ -293,9 +294,9 @@ func Update(colName string, filter bson.M, changes bson.D) (*mongo.UpdateResult,
 	return result, nil
 }
 
-func DeleteOne(colName string, domain domain.BaseModel) error {
+func DeleteOne(ctx context.Context, colName string, domain domain.BaseModel) error {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
-	_, err := collection.DeleteOne(context.TODO(), bson.M{"ID": domain.GetDocumentID()})
+	_, err := collection.DeleteOne(ctx, bson.M{"ID": domain.GetDocumentID()})
 	if err != nil {
 		return utils.ErrorInternalServer(utils.DeleteFailed, err.Error())
 	}
@@ -303,10 +304,10 @@ func DeleteOne(colName string, domain domain.BaseModel) error {
 	return nil
 }
 
-func DeleteInActive(colName string, domain domain.BaseModel) error {
+func DeleteInActive(ctx context.Context, colName string, domain domain.BaseModel) error {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 
-	_, err := collection.DeleteOne(context.TODO(), bson.M{"_id": domain.GetDocumentID(), "active": false, "pending": false})
+	_, err := collection.DeleteOne(ctx, bson.M{"_id": domain.GetDocumentID(), "active": false, "pending": false})
 	if err != nil {
 		return utils.ErrorInternalServer(utils.DeleteFailed, err.Error())
 	}
@@ -314,13 +315,13 @@ func DeleteInActive(colName string, domain domain.BaseModel) error {
 	return nil
 }
 
-func SetupDB() error {
+func SetupDB(ctx context.Context) error {
 
 	// Set client options
 	clientOptions := options.Client().ApplyURI(os.Getenv("MONGO_CLUSTER_URL"))
 
 	// Connect to MongoDB
-	client, err := mongo.Connect(context.TODO(), clientOptions)
+	client, err := mongo.Connect(ctx, clientOptions)
 
 	// Return error if there problem
 	if err != nil {
@@ -332,8 +333,8 @@ func SetupDB() error {
 	return nil
 }
 
-func CloseDB() error {
-	err := DBClient.Disconnect(context.TODO())
+func CloseDB(ctx context.Context) error {
+	err := DBClient.Disconnect(ctx)
 	// Return error if there problem
 	if err != nil {
 		return err
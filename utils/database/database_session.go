@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"os"
+	"strconv"
 
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
@@ -10,39 +12,88 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// Retry of a transaction or its commit, can be overridden from env or per context
+const TRANSACTION_DEFAULT_MAX_RETRY = 5
+
+type retryBudgetKey struct{}
+
+// Limit how many time transaction and commit run with the context are retried
+func WithRetryBudget(ctx context.Context, retry int) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, retry)
+}
+
+func retryBudget(ctx context.Context) int {
+	if retry, ok := ctx.Value(retryBudgetKey{}).(int); ok && retry >= 0 {
+		return retry
+	}
+
+	retry, err := strconv.Atoi(os.Getenv("MONGO_TRANSACTION_MAX_RETRY"))
+	if err != nil || retry < 0 {
+		return TRANSACTION_DEFAULT_MAX_RETRY
+	}
+
+	return retry
+}
+
+// Context with the deadline, cancellation and value of ctx but without its
+// session, for query that must stay outside the running transaction
+func WithoutSession(ctx context.Context) context.Context {
+	return withoutSession{ctx}
+}
+
+type withoutSession struct {
+	context.Context
+}
+
+func (ctx withoutSession) Value(key interface{}) interface{} {
+	value := ctx.Context.Value(key)
+	if _, ok := value.(mongo.Session); ok {
+		return nil
+	}
+
+	return value
+}
+
 func CommitWithRetry(sctx mongo.SessionContext) error {
-	for {
+	budget := retryBudget(sctx)
+	for retry := 0; ; retry++ {
 		err := sctx.CommitTransaction(sctx)
-		switch e := err.(type) {
-		case nil:
+		if err == nil {
 			return nil
-		case mongo.CommandError:
-			if e.HasErrorLabel("UnknownTransactionCommitResult") {
-				continue
-			}
-			return e
-		default:
-			return e
+		}
+
+		if !isRetryable(sctx, err, "UnknownTransactionCommitResult", retry, budget) {
+			return err
 		}
 	}
 }
 
+// Stop retrying once the context is done, the session is aborted when it end
 func RunTransactionWithRetry(sessionCtx mongo.SessionContext, function func(mongo.SessionContext) error) error {
-	for {
+	budget := retryBudget(sessionCtx)
+	for retry := 0; ; retry++ {
 		err := function(sessionCtx)
 		if err == nil {
 			return nil
 		}
 
-		if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.HasErrorLabel("TransientTransactionError") {
-			continue
+		if !isRetryable(sessionCtx, err, "TransientTransactionError", retry, budget) {
+			return err
 		}
+	}
+}
 
-		return err
+func isRetryable(ctx context.Context, err error, label string, retry int, budget int) bool {
+	if ctx.Err() != nil || retry >= budget {
+		return false
 	}
+
+	serverErr, ok := err.(mongo.ServerError)
+
+	return ok && serverErr.HasErrorLabel(label)
 }
 
-func SessionFindOneByID(colName string, ID string, session mongo.SessionContext) *mongo.SingleResult {
+func SessionFindOneByID(session mongo.SessionContext, colName string, ID string) *mongo.SingleResult {
 	objectID, _ := primitive.ObjectIDFromHex(ID)
 
 	query := bson.M{"_id": objectID}
@@ -52,14 +103,14 @@ func SessionFindOneByID(colName string, ID string, session mongo.SessionContext)
 	return result
 }
 
-func SessionFindOne(colName string, query bson.M, session mongo.SessionContext) *mongo.SingleResult {
+func SessionFindOne(session mongo.SessionContext, colName string, query bson.M) *mongo.SingleResult {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(colName)
 	result := collection.FindOne(session, query)
 
 	return result
 }
 
-func SessionUpdateOne(domain domain.BaseModel, session mongo.SessionContext) error {
+func SessionUpdateOne(session mongo.SessionContext, domain domain.BaseModel) error {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(domain.CollectionName())
 	document, err := toDoc(domain)
 	if err != nil {
@@ -81,7 +132,7 @@ func SessionUpdateOne(domain domain.BaseModel, session mongo.SessionContext) err
 	return nil
 }
 
-func SessionSaveOne(domain domain.BaseModel, session mongo.SessionContext) error {
+func SessionSaveOne(session mongo.SessionContext, domain domain.BaseModel) error {
 
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(domain.CollectionName())
 	result, err := collection.InsertOne(session, domain)
@@ -95,7 +146,7 @@ func SessionSaveOne(domain domain.BaseModel, session mongo.SessionContext) error
 	return nil
 }
 
-func SessionDeleteInactive(domain domain.BaseModel, session mongo.SessionContext) error {
+func SessionDeleteInactive(session mongo.SessionContext, domain domain.BaseModel) error {
 	collection := DBClient.Database(os.Getenv("MONGO_DB_NAME")).Collection(domain.CollectionName())
 
 	_, err := collection.DeleteOne(session, bson.M{"_id": domain.GetDocumentID(), "active": false, "pending": false})
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+)
+
+// Device public key is base64 of a PKIX DER key, only ECDSA P-256 and
+// Ed25519 are accepted
+func ParseDevicePublicKey(publicKey string) (crypto.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, ErrorBadRequest(InvalidDeviceKey, "Invalid device key encoding")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, ErrorBadRequest(InvalidDeviceKey, "Invalid device key")
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, ErrorBadRequest(InvalidDeviceKey, "Device key must use P-256 curve")
+		}
+
+		return key, nil
+	case ed25519.PublicKey:
+		return key, nil
+	}
+
+	return nil, ErrorBadRequest(InvalidDeviceKey, "Device key type not supported")
+}
+
+// Signature is base64, ECDSA signature is ASN.1 over SHA-256 of the message
+// and Ed25519 signature is over the message itself
+func VerifyDeviceSignature(publicKey string, message []byte, signature string) error {
+	key, err := ParseDevicePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrorBadRequest(InvalidDeviceSignature, "Invalid device signature encoding")
+	}
+
+	valid := false
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		valid = ecdsa.VerifyASN1(key, digest[:], raw)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, message, raw)
+	}
+
+	if !valid {
+		return ErrorBadRequest(InvalidDeviceSignature, "Invalid device signature")
+	}
+
+	return nil
+}
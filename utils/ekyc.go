@@ -4,91 +4,94 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	log "github.com/sirupsen/logrus"
 )
 
-func EKYCEnrollUser(nik string, faceBase64 string) (EkycRequestEnroll, error) {
-	body := createEkycEnrollPayloadUser(nik, faceBase64)
-	_, err := callEnroll(body)
-	if err != nil {
-		return EkycRequestEnroll{}, err
-	}
+// Vendor verifier at EKYC_URL. Vendor score is between 0 and 10, it is scaled
+// to 0 to 1. Vendor give no score for enroll and liveness, liveness is checked
+// as part of enroll or verify when threshold require it.
+type EKYCVerifier struct{}
 
-	return body, nil
+func (self EKYCVerifier) Provider() string {
+	return IDENTITY_PROVIDER_EKYC
 }
 
-func EKYCEnroll(nik string, faceBase64 string) (EkycRequestEnroll, error) {
-	body := createEkycEnrollPayload(nik, faceBase64)
-	_, err := callEnroll(body)
+func (self EKYCVerifier) Enroll(request IdentityRequest) (IdentityResult, error) {
+	body := createEkycEnrollPayload(request)
+	response, err := callEnroll(body)
 	if err != nil {
-		return EkycRequestEnroll{}, err
+		return IdentityResult{}, err
 	}
 
-	return body, nil
+	return IdentityResult{
+		Provider:      IDENTITY_PROVIDER_EKYC,
+		TransactionID: response.TransactionID,
+		DigitalID:     body.DigitalID,
+		DeviceID:      body.DeviceID,
+		Matched:       true,
+		Live:          body.Liveness == "true",
+	}, nil
 }
 
-func EKYCVerify(nik string, faceBase64 string) (EkycRequestVerify, error) {
-	body := createEkycVerifyPayload(nik, faceBase64)
-	_, err := callVerify(body)
+func (self EKYCVerifier) Verify(request IdentityRequest) (IdentityResult, error) {
+	body := createEkycVerifyPayload(request)
+	response, err := callVerify(body)
 	if err != nil {
-		return EkycRequestVerify{}, err
+		return IdentityResult{}, err
 	}
 
-	return body, nil
-}
+	result := IdentityResult{
+		Provider:      IDENTITY_PROVIDER_EKYC,
+		TransactionID: response.TransactionID,
+		DigitalID:     body.DigitalID,
+		DeviceID:      body.DeviceID,
+		Matched:       true,
+		Live:          body.Liveness == "true",
+	}
 
-func EKYCVerifyUser(nik string, faceBase64 string, digitalID string) (EkycRequestVerify, error) {
-	body := createEkycVerifyPayloadUser(nik, faceBase64, digitalID)
-	_, err := callVerify(body)
-	if err != nil {
-		return EkycRequestVerify{}, err
+	// Vendor already matched the face, score is checked again only when reported
+	score, err := strconv.ParseFloat(response.Score, 64)
+	if err == nil {
+		result.MatchScore = score / 10
+		result.Matched = result.MatchScore >= request.Threshold.Match
 	}
 
-	return body, nil
+	if !result.Matched {
+		return result, ErrorBadRequest(FaceNotRecognize, "Biometric failed")
+	}
+
+	return result, nil
 }
 
-func createEkycEnrollPayload(nik string, faceBase64 string) EkycRequestEnroll {
-	return EkycRequestEnroll{
-		TransactionID:      createTransactionID(),
-		Component:          "Takeme App",
-		CustomerID:         "Takeme",
-		DigitalID:          "TAKEME-" + nik,
-		RequestType:        "enroll",
-		NIK:                nik,
-		DeviceID:           "DEVICE-" + nik,
-		AppVersion:         "1.0",
-		SDKVersion:         "1.0",
-		FaceThreshold:      "6",
-		Liveness:           "false",
-		VerifyBeforeEnroll: "true",
-		Biometrics: []Biometrics{{
-			Image:    faceBase64,
-			Position: "F",
-			Type:     "Face",
-			Template: nil,
-		}},
+func (self EKYCVerifier) Liveness(request IdentityRequest) (IdentityResult, error) {
+	if request.Threshold.Liveness <= 0 {
+		request.Threshold.Liveness = 1
 	}
+
+	return self.Verify(request)
 }
 
-func createEkycEnrollPayloadUser(nik string, faceBase64 string) EkycRequestEnroll {
+func createEkycEnrollPayload(request IdentityRequest) EkycRequestEnroll {
 	return EkycRequestEnroll{
 		TransactionID:      createTransactionID(),
-		Component:          "Takeme App",
-		CustomerID:         "Takeme",
-		DigitalID:          GenerateMediumCode() + nik,
+		Component:          ekycComponent(request),
+		CustomerID:         os.Getenv("EKYC_CUSTOMER_ID"),
+		DigitalID:          GenerateMediumCode() + request.NIK,
 		RequestType:        "enroll",
-		NIK:                nik,
-		DeviceID:           "DEVICE-" + nik,
+		NIK:                request.NIK,
+		DeviceID:           ekycDeviceID(request),
 		AppVersion:         "1.0",
 		SDKVersion:         "1.0",
-		FaceThreshold:      "6",
-		Liveness:           "false",
+		FaceThreshold:      ekycFaceThreshold(request),
+		Liveness:           strconv.FormatBool(request.Threshold.Liveness > 0),
 		VerifyBeforeEnroll: "true",
 		Biometrics: []Biometrics{{
-			Image:    faceBase64,
+			Image:    request.FaceImage,
 			Position: "F",
 			Type:     "Face",
 			Template: nil,
@@ -96,22 +99,22 @@ func createEkycEnrollPayloadUser(nik string, faceBase64 string) EkycRequestEnrol
 	}
 }
 
-func createEkycVerifyPayload(nik string, faceBase64 string) EkycRequestVerify {
+func createEkycVerifyPayload(request IdentityRequest) EkycRequestVerify {
 	return EkycRequestVerify{
 		TransactionID:     createTransactionID(),
-		Component:         "Takeme App",
-		CustomerID:        "Takeme",
-		DigitalID:         "TAKEME-" + nik,
+		Component:         ekycComponent(request),
+		CustomerID:        os.Getenv("EKYC_CUSTOMER_ID"),
+		DigitalID:         request.DigitalID,
 		RequestType:       "verify",
-		NIK:               nik,
-		DeviceID:          "DEVICE-" + nik,
+		NIK:               request.NIK,
+		DeviceID:          ekycDeviceID(request),
 		AppVersion:        "1.0",
 		SDKVersion:        "1.0",
-		Liveness:          "false",
+		Liveness:          strconv.FormatBool(request.Threshold.Liveness > 0),
 		LocalVerification: "true",
-		FaceThreshold:     "6",
+		FaceThreshold:     ekycFaceThreshold(request),
 		Biometrics: []Biometrics{{
-			Image:    faceBase64,
+			Image:    request.FaceImage,
 			Position: "F",
 			Type:     "Face",
 			Template: nil,
@@ -119,31 +122,29 @@ func createEkycVerifyPayload(nik string, faceBase64 string) EkycRequestVerify {
 	}
 }
 
-func createEkycVerifyPayloadUser(nik string, faceBase64 string, digitalID string) EkycRequestVerify {
-	return EkycRequestVerify{
-		TransactionID:     createTransactionID(),
-		Component:         "Takeme App",
-		CustomerID:        "Takeme",
-		DigitalID:         digitalID,
-		RequestType:       "verify",
-		NIK:               nik,
-		DeviceID:          "DEVICE-" + nik,
-		AppVersion:        "1.0",
-		SDKVersion:        "1.0",
-		Liveness:          "false",
-		LocalVerification: "true",
-		FaceThreshold:     "6",
-		Biometrics: []Biometrics{{
-			Image:    faceBase64,
-			Position: "F",
-			Type:     "Face",
-			Template: nil,
-		}},
+func ekycComponent(request IdentityRequest) string {
+	if request.Component != "" {
+		return request.Component
 	}
+
+	return os.Getenv("EKYC_COMPONENT")
+}
+
+func ekycDeviceID(request IdentityRequest) string {
+	if request.DeviceID != "" {
+		return request.DeviceID
+	}
+
+	return "DEVICE-" + request.NIK
+}
+
+func ekycFaceThreshold(request IdentityRequest) string {
+	return strconv.FormatFloat(request.Threshold.Match*10, 'f', -1, 64)
 }
 
 func createTransactionID() string {
-	transactionID := "TAKEME" + GenerateShortCode() + time.Now().Format(os.Getenv("TIME_FORMAT"))
+	transactionID := strings.ToUpper(os.Getenv("EKYC_CUSTOMER_ID")) + GenerateShortCode() +
+		time.Now().Format(os.Getenv("TIME_FORMAT"))
 	return transactionID
 }
 
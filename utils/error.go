@@ -69,6 +69,40 @@ const (
 	CurrencyError                      = 897
 	OnlySupportOnIDR                   = 898
 	WrongAcceptCardFee                 = 899
+	LimitNotFound                      = 8100
+	InvalidLimit                       = 8101
+	DailyLimitExceeded                 = 8102
+	MonthlyLimitExceeded               = 8103
+	BalanceLimitExceeded               = 8104
+	TransactionNotHeld                 = 8105
+	TransactionBlocked                 = 8106
+	InvalidReviewer                    = 8107
+	BulkApprovalIncomplete             = 8108
+	InvalidBulkApprover                = 8109
+	InvalidBulkApprovalThreshold       = 8110
+	PermissionDenied                   = 8111
+	RoleNotFound                       = 8112
+	InvalidRole                        = 8113
+	SessionNotFound                    = 8114
+	InvalidRequestTimestamp            = 8115
+	DuplicateRequestID                 = 8116
+	InvalidIPAllowlist                 = 8117
+	AccountInactive                    = 8118
+	AccountNotLocked                   = 8119
+	InvalidUnlockCode                  = 8120
+	OTPResendThrottled                 = 8121
+	InvalidMessageTemplate             = 8122
+	InvalidMessagingSetting            = 8123
+	KYCCaseNotFound                    = 8124
+	InvalidKYCState                    = 8125
+	KYCDocumentMissing                 = 8126
+	InvalidKYCTier                     = 8127
+	InvalidIdentitySetting             = 8128
+	InvalidBiometricChallenge          = 8129
+	DeviceNotBound                     = 8130
+	InvalidDeviceKey                   = 8131
+	InvalidDeviceSignature             = 8132
+	DuplicateExternalID                = 8133
 
 	// Internal server
 	QueryFailed               = 901
@@ -108,6 +142,9 @@ const (
 	DBStartTransactionFailed  = 935
 	StripeAPICallFail         = 936
 	SaveFileFailed            = 937
+	PINHashFailed             = 938
+	OTPGenerateFailed         = 939
+	NotificationFailed        = 940
 )
 
 type CustomError struct {
@@ -1,8 +1,13 @@
 package gateway
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/takeme-id/core/domain"
 )
 
@@ -17,9 +22,24 @@ const (
 
 type Gateway interface {
 	Name() string
-	CreateVA(balanceID string, nameVA string, bankCode string) (string, error)
+	CreateVA(ctx context.Context, balanceID string, nameVA string, bankCode string) (string, error)
 	CallbackVA(w http.ResponseWriter, r *http.Request) (string, int, domain.Bank, string, error)
-	CreateTransfer(transaction domain.Transaction) (string, error)
+	CreateTransfer(ctx context.Context, transaction domain.Transaction) (string, error)
 	CallbackTransfer(w http.ResponseWriter, r *http.Request) (string, string, string, error)
-	Inquiry(bankCode string, accountNumber string) (string, error)
+	Inquiry(ctx context.Context, bankCode string, accountNumber string) (string, error)
+}
+
+// Provider call end at the caller context deadline or at the timeout, the
+// timeout can be shortened for every provider from env
+func gatewayTimeout(fallback time.Duration) time.Duration {
+	second, err := strconv.Atoi(os.Getenv("GATEWAY_TIMEOUT_SECOND"))
+	if err != nil || second <= 0 || time.Duration(second)*time.Second > fallback {
+		return fallback
+	}
+
+	return time.Duration(second) * time.Second
+}
+
+func gatewayClient(fallback time.Duration) *resty.Client {
+	return resty.New().SetTimeout(gatewayTimeout(fallback))
 }
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"os"
@@ -8,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-resty/resty/v2"
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
@@ -21,7 +21,8 @@ func (gateway MMBCGateway) Name() string {
 	return MMBC
 }
 
-func (gateway MMBCGateway) CreateVA(balanceID string, nameVA string, bankCode string) (string, error) {
+func (gateway MMBCGateway) CreateVA(ctx context.Context, balanceID string, nameVA string,
+	bankCode string) (string, error) {
 	return "", nil
 }
 
@@ -29,12 +30,12 @@ func (gateway MMBCGateway) CallbackVA(w http.ResponseWriter, r *http.Request) (s
 	return "", 0, domain.Bank{}, "", nil
 }
 
-func (gateway MMBCGateway) CreateTransfer(transaction domain.Transaction) (string, error) {
+func (gateway MMBCGateway) CreateTransfer(ctx context.Context, transaction domain.Transaction) (string, error) {
 	if checkIsTransferToWallet(transaction.To.InstitutionCode) {
-		referece, err := createTransferToWallet(transaction)
+		referece, err := createTransferToWallet(ctx, transaction)
 		return referece, err
 	} else {
-		referece, err := createTransferToBank(transaction)
+		referece, err := createTransferToBank(ctx, transaction)
 		return referece, err
 	}
 }
@@ -56,13 +57,12 @@ func (gateway MMBCGateway) CallbackTransfer(w http.ResponseWriter, r *http.Reque
 	return transactionCode, reference, status, nil
 }
 
-func (gateway MMBCGateway) Inquiry(bankCode string, accountNumber string) (string, error) {
+func (gateway MMBCGateway) Inquiry(ctx context.Context, bankCode string, accountNumber string) (string, error) {
 	return "", nil
 }
 
-func createTransferToBank(transaction domain.Transaction) (string, error) {
-	client := resty.New()
-	client.SetTimeout(10 * time.Minute)
+func createTransferToBank(ctx context.Context, transaction domain.Transaction) (string, error) {
+	client := gatewayClient(10 * time.Minute)
 	url := os.Getenv("MMBC_TRANSFER_API_URL")
 
 	bankCode := utils.ConvertBankCodeMMBC(transaction.To.InstitutionCode)
@@ -77,6 +77,7 @@ func createTransferToBank(transaction domain.Transaction) (string, error) {
 
 	var result MMBCTransferResponse
 	_, err := client.R().
+		SetContext(ctx).
 		SetFormData(map[string]string{
 			"username":           os.Getenv("MMBC_USERNAME"),
 			"password":           os.Getenv("MMBC_PASSWORD"),
@@ -105,9 +106,8 @@ func createTransferToBank(transaction domain.Transaction) (string, error) {
 	return result.Invoice, nil
 }
 
-func createTransferToWallet(transaction domain.Transaction) (string, error) {
-	client := resty.New()
-	client.SetTimeout(10 * time.Minute)
+func createTransferToWallet(ctx context.Context, transaction domain.Transaction) (string, error) {
+	client := gatewayClient(10 * time.Minute)
 	url := os.Getenv("MMBC_TRANSFER_WALLET_API_URL")
 
 	bankCode := utils.ConvertBankCodeMMBC(transaction.To.InstitutionCode)
@@ -121,6 +121,7 @@ func createTransferToWallet(transaction domain.Transaction) (string, error) {
 
 	var result MMBCTransferWalletResponse
 	_, err := client.R().
+		SetContext(ctx).
 		SetFormData(map[string]string{
 			"username":               os.Getenv("MMBC_USERNAME"),
 			"password":               os.Getenv("MMBC_PASSWORD"),
@@ -1,11 +1,11 @@
 package gateway
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/go-resty/resty/v2"
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
@@ -18,16 +18,16 @@ func (gateway OYGateway) Name() string {
 	return OY
 }
 
-func (gateway OYGateway) CreateVA(balanceID string, nameVA string, bankCode string) (string, error) {
+func (gateway OYGateway) CreateVA(ctx context.Context, balanceID string, nameVA string,
+	bankCode string) (string, error) {
 	return "", nil
 }
 func (gateway OYGateway) CallbackVA(w http.ResponseWriter, r *http.Request) (string, int, domain.Bank, string, error) {
 	return "", 0, domain.Bank{}, "", nil
 }
 
-func (gateway OYGateway) CreateTransfer(transaction domain.Transaction) (string, error) {
-	client := resty.New()
-	client.SetTimeout(10 * time.Minute)
+func (gateway OYGateway) CreateTransfer(ctx context.Context, transaction domain.Transaction) (string, error) {
+	client := gatewayClient(10 * time.Minute)
 	url := os.Getenv("OY_TRANSFER_API_URL")
 
 	bank := transaction.To.InstitutionCode
@@ -45,6 +45,7 @@ func (gateway OYGateway) CreateTransfer(transaction domain.Transaction) (string,
 	}
 
 	resp, err := client.R().
+		SetContext(ctx).
 		SetHeaders(map[string]string{
 			"Content-Type":  "application/json",
 			"x-oy-username": os.Getenv("OY_PUBLIC_KEY"),
@@ -85,10 +86,9 @@ func (gateway OYGateway) CallbackTransfer(w http.ResponseWriter, r *http.Request
 	return transactionCode, reference, status, nil
 }
 
-func (gateway OYGateway) Inquiry(bankCode string, accountNumber string) (string, error) {
+func (gateway OYGateway) Inquiry(ctx context.Context, bankCode string, accountNumber string) (string, error) {
 
-	client := resty.New()
-	client.SetTimeout(20 * time.Second)
+	client := gatewayClient(20 * time.Second)
 	client.SetRetryCount(1)
 
 	url := os.Getenv("OY_INQUIRY_API_URL")
@@ -105,6 +105,7 @@ func (gateway OYGateway) Inquiry(bankCode string, accountNumber string) (string,
 	}
 
 	resp, err := client.R().
+		SetContext(ctx).
 		SetHeaders(map[string]string{
 			"Content-Type":  "application/json",
 			"x-oy-username": os.Getenv("OY_PUBLIC_KEY"),
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -26,6 +27,12 @@ const (
 	CHARGE_CARD_STATUS_PENDING   = "Pending"
 )
 
+// Minor unit digits Stripe use where it differ from domain currency exponent,
+// IDR is two decimal on Stripe while balances keep whole rupiah
+var stripeExponent = map[string]int{
+	domain.CURRENCY_IDR: 2,
+}
+
 type StripeGateway struct {
 }
 
@@ -33,7 +40,8 @@ func (gateway StripeGateway) Name() string {
 	return Stripe
 }
 
-func (gateway StripeGateway) CreateVA(balanceID string, nameVA string, bankCode string) (string, error) {
+func (gateway StripeGateway) CreateVA(ctx context.Context, balanceID string, nameVA string,
+	bankCode string) (string, error) {
 	return "", nil
 }
 
@@ -41,7 +49,7 @@ func (gateway StripeGateway) CallbackVA(w http.ResponseWriter, r *http.Request)
 	return "", 0, domain.Bank{}, "", nil
 }
 
-func (gateway StripeGateway) CreateTransfer(transaction domain.Transaction) (string, error) {
+func (gateway StripeGateway) CreateTransfer(ctx context.Context, transaction domain.Transaction) (string, error) {
 	return "", nil
 }
 
@@ -49,13 +57,19 @@ func (gateway StripeGateway) CallbackTransfer(w http.ResponseWriter, r *http.Req
 	return "", "", "", nil
 }
 
-func (gateway StripeGateway) Inquiry(bankCode string, accountNumber string) (string, error) {
+func (gateway StripeGateway) Inquiry(ctx context.Context, bankCode string, accountNumber string) (string, error) {
 	return "", nil
 }
 
-func (gateway StripeGateway) ChargeCard(balanceID string, amount int, returnURL string, card domain.Card, externalID string) (string, string, error) {
+func (gateway StripeGateway) ChargeCard(ctx context.Context, balanceID string, amount domain.Money,
+	returnURL string, card domain.Card, externalID string) (string, string, error) {
 	stripe.Key = os.Getenv("STRIPE_SECRET")
 
+	stripeAmount, err := toStripeAmount(amount)
+	if err != nil {
+		return "", "", err
+	}
+
 	expM, err := strconv.ParseInt(card.ExpMonth, 10, 64)
 	expY, err := strconv.ParseInt(card.ExpYear, 10, 64)
 	params := &stripe.PaymentMethodParams{
@@ -67,6 +81,7 @@ func (gateway StripeGateway) ChargeCard(balanceID string, amount int, returnURL
 		},
 		Type: stripe.String("card"),
 	}
+	params.Context = ctx
 	pm, _ := paymentmethod.New(params)
 
 	paymentMethodID := pm.ID
@@ -74,14 +89,15 @@ func (gateway StripeGateway) ChargeCard(balanceID string, amount int, returnURL
 	reference := balanceID
 
 	params2 := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(int64(amount)),
-		Currency: stripe.String(string(stripe.CurrencyUSD)),
+		Amount:   stripe.Int64(stripeAmount),
+		Currency: stripe.String(amount.Currency),
 		PaymentMethodTypes: []*string{
 			stripe.String("card"),
 		},
 		UseStripeSDK:  stripe.Bool(false),
 		PaymentMethod: &paymentMethodID,
 	}
+	params2.Context = ctx
 
 	params2.AddMetadata("reference", reference)
 	params2.AddMetadata("external_id", externalID)
@@ -96,6 +112,7 @@ func (gateway StripeGateway) ChargeCard(balanceID string, amount int, returnURL
 		UseStripeSDK: stripe.Bool(false),
 		ReturnURL:    &returnURL,
 	}
+	params3.Context = ctx
 
 	pi2, err := paymentintent.Confirm(
 		pi.ID,
@@ -117,11 +134,16 @@ func (gateway StripeGateway) ChargeCard(balanceID string, amount int, returnURL
 	return status, authURL, nil
 }
 
-func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, returnURL string, card domain.Card, externalID string, interval string) (
+func (gateway StripeGateway) ChargeCardSubscribe(ctx context.Context, balanceID string, amount domain.Money, returnURL string, card domain.Card, externalID string, interval string) (
 	string, string, string, error) {
 	stripe.Key = os.Getenv("STRIPE_SECRET")
 	reference := balanceID
 
+	stripeAmount, err := toStripeAmount(amount)
+	if err != nil {
+		return "", "", "", err
+	}
+
 	expM, err := strconv.ParseInt(card.ExpMonth, 10, 64)
 	expY, err := strconv.ParseInt(card.ExpYear, 10, 64)
 	params := &stripe.PaymentMethodParams{
@@ -133,6 +155,7 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 		},
 		Type: stripe.String("card"),
 	}
+	params.Context = ctx
 	params.AddMetadata("reference", reference)
 	pm, err := paymentmethod.New(params)
 	if err != nil {
@@ -144,18 +167,20 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 	params2 := &stripe.ProductParams{
 		Name: stripe.String("Gold Special"),
 	}
+	params2.Context = ctx
 	pro, err := product.New(params2)
 
 	productID := pro.ID
 
 	priceParam := &stripe.PriceParams{
-		Currency: stripe.String(string(stripe.CurrencyUSD)),
+		Currency: stripe.String(amount.Currency),
 		Product:  &productID,
 		Recurring: &stripe.PriceRecurringParams{
 			Interval: &interval,
 		},
-		UnitAmount: stripe.Int64(int64(amount)),
+		UnitAmount: stripe.Int64(stripeAmount),
 	}
+	priceParam.Context = ctx
 	pr, _ := price.New(priceParam)
 	priceID := pr.ID
 
@@ -166,6 +191,7 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 			DefaultPaymentMethod: &paymentMethodID,
 		},
 	}
+	custParams.Context = ctx
 
 	c, err := customer.New(custParams)
 	if err != nil {
@@ -184,6 +210,7 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 		},
 		PaymentBehavior: &behaviour,
 	}
+	subParam.Context = ctx
 	s, err := subscription.New(subParam)
 
 	subscriptionID := s.ID
@@ -191,7 +218,7 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 
 	in, err := invoice.Get(
 		invoiceID,
-		nil,
+		&stripe.InvoiceParams{Params: stripe.Params{Context: ctx}},
 	)
 	if err != nil {
 		return "", "", "", utils.ErrorInternalServer(utils.StripeAPICallFail, "Stripe API call fail")
@@ -201,9 +228,10 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 
 	pi, _ := paymentintent.Get(
 		paymentIntentID,
-		nil,
+		&stripe.PaymentIntentParams{Params: stripe.Params{Context: ctx}},
 	)
 	paramsX := &stripe.PaymentIntentParams{}
+	paramsX.Context = ctx
 	paramsX.AddMetadata("reference", balanceID)
 	paramsX.AddMetadata("external_id", externalID)
 
@@ -216,6 +244,7 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 		UseStripeSDK: stripe.Bool(false),
 		ReturnURL:    &returnURL,
 	}
+	params3.Context = ctx
 
 	pi2, err := paymentintent.Confirm(
 		pi.ID,
@@ -237,12 +266,12 @@ func (gateway StripeGateway) ChargeCardSubscribe(balanceID string, amount int, r
 	return status, authURL, subscriptionID, nil
 }
 
-func (gateway StripeGateway) CancelSubscribe(subsID string) error {
+func (gateway StripeGateway) CancelSubscribe(ctx context.Context, subsID string) error {
 	stripe.Key = os.Getenv("STRIPE_SECRET")
 
 	_, err := subscription.Cancel(
 		subsID,
-		nil,
+		&stripe.SubscriptionCancelParams{Params: stripe.Params{Context: ctx}},
 	)
 	if err != nil {
 		return utils.ErrorInternalServer(utils.StripeAPICallFail, "Stripe API call fail")
@@ -289,10 +318,48 @@ func (gateway StripeGateway) CallbackAcceptPaymentCard(w http.ResponseWriter, r
 		Network:       string(paymentIntent.Charges.Data[0].PaymentMethodDetails.Card.Brand),
 	}
 
-	amount := int(paymentIntent.Amount)
+	amount, err := fromStripeAmount(paymentIntent.Amount, string(paymentIntent.Currency))
+	if err != nil {
+		return "", 0, domain.Card{}, "", "", err
+	}
 	reference := paymentIntent.ID
 	balanceID := paymentIntent.Metadata["reference"]
 	externalID := paymentIntent.Metadata["external_id"]
 
 	return balanceID, amount, card, reference, externalID, nil
 }
+
+// Amount in the unit Stripe expect for the currency, unknown or empty currency
+// is rejected before any API call
+func toStripeAmount(amount domain.Money) (int64, error) {
+	exponent, err := domain.CurrencyExponent(amount.Currency)
+	if err != nil {
+		return 0, utils.ErrorBadRequest(utils.CurrencyError, "Currency not supported")
+	}
+
+	return int64(amount.Amount) * stripeScale(amount.Currency, exponent), nil
+}
+
+// Stripe amount back in the minor unit of the currency
+func fromStripeAmount(amount int64, currency string) (int, error) {
+	exponent, err := domain.CurrencyExponent(currency)
+	if err != nil {
+		return 0, utils.ErrorBadRequest(utils.CurrencyError, "Currency not supported")
+	}
+
+	return int(amount / stripeScale(currency, exponent)), nil
+}
+
+func stripeScale(currency string, exponent int) int64 {
+	target, ok := stripeExponent[domain.NormalizeCurrency(currency)]
+	if !ok {
+		return 1
+	}
+
+	scale := int64(1)
+	for i := exponent; i < target; i++ {
+		scale = scale * 10
+	}
+
+	return scale
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,7 +12,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-resty/resty/v2"
 	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 	"github.com/takeme-id/core/utils"
@@ -24,8 +24,9 @@ func (gateway XenditGateway) Name() string {
 	return Xendit
 }
 
-func (gateway XenditGateway) CreateVA(balanceID string, nameVA string, bankCode string) (string, error) {
-	client := resty.New().SetTimeout(60 * time.Second)
+func (gateway XenditGateway) CreateVA(ctx context.Context, balanceID string, nameVA string,
+	bankCode string) (string, error) {
+	client := gatewayClient(60 * time.Second)
 	url := os.Getenv("XENDIT_VA_API_URL")
 
 	token := fmt.Sprintf("%v:", os.Getenv("XENDIT_API_KEY"))
@@ -43,7 +44,7 @@ func (gateway XenditGateway) CreateVA(balanceID string, nameVA string, bankCode
 	client.SetRetryCount(1)
 
 	var result XenditCreateVAResponse
-	resp, err := client.R().SetResult(&result).Post(url)
+	resp, err := client.R().SetContext(ctx).SetResult(&result).Post(url)
 
 	utils.LoggingAPICall(resp.StatusCode(), map[string]string{
 		"external_id": balanceID,
@@ -93,7 +94,7 @@ func (gateway XenditGateway) CallbackVA(w http.ResponseWriter, r *http.Request)
 	return balanceID, amount, bank, reference, nil
 }
 
-func (gateway XenditGateway) CreateTransfer(transaction domain.Transaction) (string, error) {
+func (gateway XenditGateway) CreateTransfer(ctx context.Context, transaction domain.Transaction) (string, error) {
 	apiUrl := os.Getenv("XENDIT_TRANSFER_API_URL")
 	data := url.Values{}
 	data.Set("external_id", transaction.TransactionCode)
@@ -102,11 +103,11 @@ func (gateway XenditGateway) CreateTransfer(transaction domain.Transaction) (str
 	data.Set("account_number", transaction.To.GetAccountNumber())
 	data.Set("description", "FSND "+transaction.From.Name)
 
-	client := &http.Client{}
+	client := &http.Client{Timeout: gatewayTimeout(10 * time.Minute)}
 	token := fmt.Sprintf("%v:", os.Getenv("XENDIT_API_KEY"))
 	basicAuth := fmt.Sprintf("Basic %v", base64.StdEncoding.EncodeToString([]byte(token)))
 
-	r, _ := http.NewRequest("POST", apiUrl, strings.NewReader(data.Encode()+fmt.Sprintf("&amount=%v", transaction.SubAmount))) // URL-encoded payload
+	r, _ := http.NewRequestWithContext(ctx, "POST", apiUrl, strings.NewReader(data.Encode()+fmt.Sprintf("&amount=%v", transaction.SubAmount))) // URL-encoded payload
 	r.Header.Add("Authorization", basicAuth)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	r.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
@@ -154,7 +155,7 @@ func (gateway XenditGateway) CallbackTransfer(w http.ResponseWriter, r *http.Req
 	return transactionCode, reference, status, nil
 }
 
-func (gateway XenditGateway) Inquiry(bankCode string, accountNumber string) (string, error) {
+func (gateway XenditGateway) Inquiry(ctx context.Context, bankCode string, accountNumber string) (string, error) {
 	return "", nil
 }
 
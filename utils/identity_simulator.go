@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Face image starting with these prefix make the simulator fail the check
+const (
+	SIMULATOR_MISMATCH_FACE = "mismatch"
+	SIMULATOR_SPOOF_FACE    = "spoof"
+)
+
+const (
+	simulatorHighScore = 0.95
+	simulatorLowScore  = 0.2
+)
+
+// Deterministic local provider without any call outside, the same request
+// always give the same result so it can back test and staging. Enroll need a
+// 16 digit NIK, face match unless image start with SIMULATOR_MISMATCH_FACE and
+// face is live unless image start with SIMULATOR_SPOOF_FACE.
+type SimulatorVerifier struct{}
+
+func (self SimulatorVerifier) Provider() string {
+	return IDENTITY_PROVIDER_SIMULATOR
+}
+
+func (self SimulatorVerifier) Enroll(request IdentityRequest) (IdentityResult, error) {
+	if len(request.NIK) != 16 || strings.Trim(request.NIK, "0123456789") != "" {
+		return IdentityResult{}, ErrorBadRequest(BiometricFail, "Biometric failed")
+	}
+
+	request.DigitalID = simulatorDigitalID(request.NIK)
+
+	return self.check(request)
+}
+
+func (self SimulatorVerifier) Verify(request IdentityRequest) (IdentityResult, error) {
+	if request.DigitalID == "" {
+		return IdentityResult{}, ErrorBadRequest(BiometricFail, "Biometric failed")
+	}
+
+	return self.check(request)
+}
+
+func (self SimulatorVerifier) Liveness(request IdentityRequest) (IdentityResult, error) {
+	result := IdentityResult{
+		Provider:      IDENTITY_PROVIDER_SIMULATOR,
+		TransactionID: simulatorTransactionID(request),
+		DigitalID:     request.DigitalID,
+		DeviceID:      request.DeviceID,
+		LivenessScore: simulatorHighScore,
+	}
+
+	if request.FaceImage == "" || strings.HasPrefix(request.FaceImage, SIMULATOR_SPOOF_FACE) {
+		result.LivenessScore = simulatorLowScore
+	}
+
+	threshold := request.Threshold.Liveness
+	if threshold <= 0 {
+		threshold = simulatorHighScore
+	}
+
+	result.Live = result.LivenessScore >= threshold
+	if !result.Live {
+		return result, ErrorBadRequest(FaceNotRecognize, "Liveness check failed")
+	}
+
+	return result, nil
+}
+
+// Match face, and liveness when threshold require it like the vendor does
+func (self SimulatorVerifier) check(request IdentityRequest) (IdentityResult, error) {
+	result := self.match(request)
+	if !result.Matched {
+		return result, ErrorBadRequest(FaceNotRecognize, "Biometric failed")
+	}
+
+	if request.Threshold.Liveness <= 0 {
+		return result, nil
+	}
+
+	liveness, err := self.Liveness(request)
+	result.Live = liveness.Live
+	result.LivenessScore = liveness.LivenessScore
+
+	return result, err
+}
+
+func (self SimulatorVerifier) match(request IdentityRequest) IdentityResult {
+	result := IdentityResult{
+		Provider:      IDENTITY_PROVIDER_SIMULATOR,
+		TransactionID: simulatorTransactionID(request),
+		DigitalID:     request.DigitalID,
+		DeviceID:      request.DeviceID,
+		MatchScore:    simulatorHighScore,
+	}
+
+	if request.FaceImage == "" || strings.HasPrefix(request.FaceImage, SIMULATOR_MISMATCH_FACE) {
+		result.MatchScore = simulatorLowScore
+	}
+
+	result.Matched = result.MatchScore >= request.Threshold.Match
+	log.Info(fmt.Sprintf("Identity simulator : NIK %v matched %v with score %v", request.NIK, result.Matched, result.MatchScore))
+
+	return result
+}
+
+func simulatorDigitalID(nik string) string {
+	hash := sha256.Sum256([]byte(nik))
+	return "SIM-" + hex.EncodeToString(hash[:8])
+}
+
+func simulatorTransactionID(request IdentityRequest) string {
+	hash := sha256.Sum256([]byte(request.NIK + request.DigitalID + request.FaceImage))
+	return "SIM" + hex.EncodeToString(hash[:8])
+}
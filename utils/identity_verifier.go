@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	IDENTITY_PROVIDER_EKYC      = "ekyc"
+	IDENTITY_PROVIDER_SIMULATOR = "simulator"
+)
+
+// Score is a confidence between 0 and 1, face match or liveness below the
+// threshold is rejected even when provider report success. Zero liveness
+// threshold skip the liveness check of enroll and verify.
+type IdentityThreshold struct {
+	Match    float64
+	Liveness float64
+}
+
+type IdentityRequest struct {
+	NIK       string
+	DigitalID string
+	DeviceID  string
+	FaceImage string
+	Component string
+	Threshold IdentityThreshold
+}
+
+type IdentityResult struct {
+	Provider      string
+	TransactionID string
+	DigitalID     string
+	DeviceID      string
+	Matched       bool
+	MatchScore    float64
+	Live          bool
+	LivenessScore float64
+}
+
+type IdentityVerifier interface {
+	Provider() string
+	// Register face of the NIK, DigitalID of the result identify the face afterward
+	Enroll(request IdentityRequest) (IdentityResult, error)
+	// Match face against the enrolled DigitalID
+	Verify(request IdentityRequest) (IdentityResult, error)
+	// Check face is taken from a live person
+	Liveness(request IdentityRequest) (IdentityResult, error)
+}
+
+var identityVerifiers = map[string]IdentityVerifier{
+	IDENTITY_PROVIDER_EKYC:      EKYCVerifier{},
+	IDENTITY_PROVIDER_SIMULATOR: SimulatorVerifier{},
+}
+
+func IsIdentityProvider(name string) bool {
+	_, ok := identityVerifiers[name]
+	return ok
+}
+
+// Verifier by name, EKYC_PROVIDER is used when name is empty or unknown.
+// EKYC_SIMULATOR=true force the simulator for test and staging.
+func IdentityVerifierByName(name string) IdentityVerifier {
+	if os.Getenv("EKYC_SIMULATOR") == "true" {
+		return SimulatorVerifier{}
+	}
+
+	verifier, ok := identityVerifiers[name]
+	if ok {
+		return verifier
+	}
+
+	verifier, ok = identityVerifiers[os.Getenv("EKYC_PROVIDER")]
+	if ok {
+		return verifier
+	}
+
+	return EKYCVerifier{}
+}
+
+// Threshold from EKYC_MATCH_THRESHOLD and EKYC_LIVENESS_THRESHOLD, liveness
+// is not checked unless configured
+func DefaultIdentityThreshold() IdentityThreshold {
+	return IdentityThreshold{
+		Match:    envScore("EKYC_MATCH_THRESHOLD", 0.6),
+		Liveness: envScore("EKYC_LIVENESS_THRESHOLD", 0),
+	}
+}
+
+func IsIdentityScore(score float64) bool {
+	return score >= 0 && score <= 1
+}
+
+func envScore(key string, fallback float64) float64 {
+	score, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || !IsIdentityScore(score) {
+		return fallback
+	}
+
+	return score
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Allowlist is stored as comma separated IP or CIDR, single IP is treated as /32 or /128
+func ParseIPAllowlist(value string) ([]*net.IPNet, error) {
+	var results []*net.IPNet
+	for _, entry := range SplitIPAllowlist(value) {
+		network, err := ParseIPNetwork(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, network)
+	}
+
+	return results, nil
+}
+
+func SplitIPAllowlist(value string) []string {
+	var results []string
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';' || r == '\n'
+	}) {
+		if entry != "" {
+			results = append(results, entry)
+		}
+	}
+
+	return results
+}
+
+func ParseIPNetwork(entry string) (*net.IPNet, error) {
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %v", entry)
+		}
+
+		return network, nil
+	}
+
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP %v", entry)
+	}
+
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+func IPInNetworks(ip net.IP, networks []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Client IP is the remote address unless it is a trusted proxy, then
+// X-Forwarded-For is read from the right skipping every trusted proxy
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if !IPInNetworks(ip, trustedProxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+
+		ip = hop
+		if !IPInNetworks(hop, trustedProxies) {
+			return hop
+		}
+	}
+
+	return ip
+}
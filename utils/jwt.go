@@ -1,28 +1,86 @@
 package utils
 
 import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	log "github.com/sirupsen/logrus"
 	"github.com/takeme-id/core/domain"
 )
 
+// Token is signed with RS256 by JWT_CURRENT_KEY_ID from JWT_KEYS, configured the
+// same way as RSA_KEYS. Verification pick the key by kid header so previous key
+// keep working until its retire date. HS256 with JWT_SECRET_KEY is only used
+// when no signing key configured and accepted until JWT_HS256_DISABLED is true.
+const JWT_KEY_ID_HEADER = "kid"
+
+var (
+	jwtKeyring     *RSAKeyring
+	jwtKeyringErr  error
+	jwtKeyringOnce sync.Once
+)
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func JWTKeys() (*RSAKeyring, error) {
+	jwtKeyringOnce.Do(func() {
+		jwtKeyring, jwtKeyringErr = loadRSAKeyring(os.Getenv("JWT_KEYS"), os.Getenv("JWT_CURRENT_KEY_ID"))
+		if jwtKeyringErr != nil {
+			log.Error(fmt.Sprintf("Load JWT keyring failed because %v", jwtKeyringErr.Error()))
+		}
+	})
+
+	return jwtKeyring, jwtKeyringErr
+}
+
+// Public keys for partner service verifying token without the signing secret
+func JWKS() (JSONWebKeySet, error) {
+	keyring, err := JWTKeys()
+	if err != nil {
+		return JSONWebKeySet{}, ErrorInternalServer(DecodeTokenFailed, "JWT keyring not loaded")
+	}
+
+	result := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range keyring.ActiveKeys() {
+		publicKey := key.PrivateKey.PublicKey
+		result.Keys = append(result.Keys, JSONWebKey{
+			KeyType:   "RSA",
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodRS256.Alg(),
+			Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+
+	return result, nil
+}
+
 func JWTDecode(tokenString string) (domain.Claims, error) {
 
 	claims := domain.Claims{}
 
 	// Parse the JWT string and store the result in `claims`.
-	// Note that we are passing the key in this method as well. This method will return an error
-	// if the token is invalid (if it has expired according to the expiry time we set on sign in),
-	// or if the signature does not match
-	tkn, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
+	// Key is chosen by signing method and kid, never trust the alg alone
+	// otherwise public key could be used as HMAC secret.
+	tkn, err := jwt.ParseWithClaims(tokenString, &claims, jwtVerificationKey)
 	if err != nil {
-		if err == jwt.ErrSignatureInvalid {
-			return domain.Claims{}, ErrorUnauthorized()
-		}
 		return domain.Claims{}, ErrorUnauthorized()
 	}
 	if !tkn.Valid {
@@ -32,13 +90,44 @@ func JWTDecode(tokenString string) (domain.Claims, error) {
 	return claims, nil
 }
 
-func JWTEncode(claimsAble domain.ClaimsAble, corporate domain.Corporate) (string, error) {
-	expirationTime := time.Now().Add(time.Minute * time.Duration(corporate.TokenExpired))
+func jwtVerificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+		}
+
+		keyID, _ := token.Header[JWT_KEY_ID_HEADER].(string)
+		keyring, err := JWTKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := keyring.Key(keyID)
+		if !ok {
+			return nil, fmt.Errorf("unknown JWT key %v", keyID)
+		}
+
+		return &key.PrivateKey.PublicKey, nil
+	case *jwt.SigningMethodHMAC:
+		if os.Getenv("JWT_HS256_DISABLED") == "true" || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("HMAC token not accepted")
+		}
+
+		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	}
+
+	return nil, fmt.Errorf("unexpected signing method %v", token.Method.Alg())
+}
+
+// Access token is bound to a session, tokenID is the jti used for revocation
+func JWTEncode(claimsAble domain.ClaimsAble, corporate domain.Corporate, sessionID string,
+	tokenID string, expirationTime time.Time) (string, error) {
 
 	claims := &domain.Claims{
 		SocketID:        claimsAble.GetID(),
 		FullName:        claimsAble.GetFullName(),
-		PhoneNumber:     claimsAble.GetFullName(),
+		PhoneNumber:     claimsAble.GetPhoneNumber(),
 		Verified:        claimsAble.GetVerified(),
 		IsPinAlreadySet: claimsAble.GetIsPinAlreadySet(),
 		CorporateID:     claimsAble.GetCorporateID(),
@@ -48,18 +137,37 @@ func JWTEncode(claimsAble domain.ClaimsAble, corporate domain.Corporate) (string
 		Resources:       corporate.Products,
 		SAAS:            corporate.SAAS,
 		CorporateURL:    corporate.DashboardURL,
+		SessionID:       sessionID,
 		StandardClaims: jwt.StandardClaims{
-			// In JWT, the expiry time is expressed as unix milliseconds
+			Id:       tokenID,
+			IssuedAt: time.Now().Unix(),
+			// In JWT, the expiry time is expressed as unix seconds
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretKey := []byte(os.Getenv("JWT_SECRET_KEY"))
-	tokenString, err := token.SignedString(secretKey)
+	var signingKey interface{}
+	var token *jwt.Token
+
+	keyring, err := JWTKeys()
+	if err != nil {
+		return "", ErrorInternalServer(EncodeTokenFailed, "JWT keyring not loaded")
+	}
+
+	key, ok := keyring.Current()
+	if ok {
+		token = jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header[JWT_KEY_ID_HEADER] = key.ID
+		signingKey = key.PrivateKey
+	} else {
+		token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signingKey = []byte(os.Getenv("JWT_SECRET_KEY"))
+	}
+
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
 		// If there is an error in creating the JWT return an internal server error
-		return "", ErrorInternalServer(DecodeTokenFailed, err.Error())
+		return "", ErrorInternalServer(EncodeTokenFailed, err.Error())
 	}
 
 	return tokenString, nil
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	NOTIFIER_SMS       = "sms"
+	NOTIFIER_WA        = "wa"
+	NOTIFIER_WA_QONTAK = "wa_qontak"
+	NOTIFIER_FAKE      = "fake"
+)
+
+// Message sent to user. Text is the rendered message, WhatsApp channel send it
+// as the only parameter of the approved template configured on env.
+type Notification struct {
+	To   string
+	Code string
+	Text string
+}
+
+type Notifier interface {
+	Channel() string
+	Notify(notification Notification) error
+}
+
+type SMSNotifier struct{}
+
+func (self SMSNotifier) Channel() string {
+	return NOTIFIER_SMS
+}
+
+func (self SMSNotifier) Notify(notification Notification) error {
+	return SendSMS(notification.To, notification.Text)
+}
+
+type WAHubungiNotifier struct{}
+
+func (self WAHubungiNotifier) Channel() string {
+	return NOTIFIER_WA
+}
+
+func (self WAHubungiNotifier) Notify(notification Notification) error {
+	if len(notification.To) < 2 {
+		return ErrorInternalServer(QontakAPICallFailed, "Invalid phone number")
+	}
+
+	return SendWAHubungi(notification.To, notification.Text)
+}
+
+type WAQontakNotifier struct{}
+
+func (self WAQontakNotifier) Channel() string {
+	return NOTIFIER_WA_QONTAK
+}
+
+func (self WAQontakNotifier) Notify(notification Notification) error {
+	if len(notification.To) < 2 {
+		return ErrorInternalServer(QontakAPICallFailed, "Invalid phone number")
+	}
+
+	return SendWA(notification.To, notification.Text)
+}
+
+// Local provider which keep message in memory instead of sending it, used in
+// test and local environment with NOTIFIER_FAKE=true
+type FakeNotifier struct{}
+
+var (
+	fakeNotifications      []Notification
+	fakeNotificationsMutex sync.Mutex
+)
+
+func (self FakeNotifier) Channel() string {
+	return NOTIFIER_FAKE
+}
+
+func (self FakeNotifier) Notify(notification Notification) error {
+	fakeNotificationsMutex.Lock()
+	defer fakeNotificationsMutex.Unlock()
+
+	fakeNotifications = append(fakeNotifications, notification)
+	log.Info(fmt.Sprintf("Fake notifier : Sending message for %v to %v", notification.To, notification.Text))
+
+	return nil
+}
+
+// Message received by FakeNotifier, oldest first
+func FakeNotifications() []Notification {
+	fakeNotificationsMutex.Lock()
+	defer fakeNotificationsMutex.Unlock()
+
+	return append([]Notification{}, fakeNotifications...)
+}
+
+func ResetFakeNotifications() {
+	fakeNotificationsMutex.Lock()
+	defer fakeNotificationsMutex.Unlock()
+
+	fakeNotifications = nil
+}
+
+var defaultNotifiers = map[string]Notifier{
+	NOTIFIER_SMS:       SMSNotifier{},
+	NOTIFIER_WA:        WAHubungiNotifier{},
+	NOTIFIER_WA_QONTAK: WAQontakNotifier{},
+	NOTIFIER_FAKE:      FakeNotifier{},
+}
+
+var (
+	notifiers      = defaultNotifiers
+	notifiersMutex sync.Mutex
+)
+
+// Replace the providers by name, nil restore the real providers. Test use it
+// to make a provider fail.
+func UseNotifiers(providers map[string]Notifier) {
+	notifiersMutex.Lock()
+	defer notifiersMutex.Unlock()
+
+	if providers == nil {
+		providers = defaultNotifiers
+	}
+
+	notifiers = providers
+}
+
+func registeredNotifiers() map[string]Notifier {
+	notifiersMutex.Lock()
+	defer notifiersMutex.Unlock()
+
+	return notifiers
+}
+
+var defaultNotifierOrder = []string{NOTIFIER_WA, NOTIFIER_WA_QONTAK, NOTIFIER_SMS}
+
+func IsNotifier(name string) bool {
+	_, ok := registeredNotifiers()[name]
+	return ok
+}
+
+// Notifiers to try in order, preferred channel first followed by the rest of
+// the order. Empty order allow every provider, preferred channel outside the
+// order is ignored.
+func Notifiers(preferred string, order []string) []Notifier {
+	if os.Getenv("NOTIFIER_FAKE") == "true" {
+		return []Notifier{FakeNotifier{}}
+	}
+
+	if len(order) == 0 {
+		order = defaultNotifierOrder
+	}
+
+	notifiers := registeredNotifiers()
+
+	results := []Notifier{}
+	for _, name := range order {
+		notifier, ok := notifiers[name]
+		if ok && name == preferred {
+			results = append(results, notifier)
+			break
+		}
+	}
+
+	for _, name := range order {
+		notifier, ok := notifiers[name]
+		if ok && name != preferred {
+			results = append(results, notifier)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PIN is stored as PHC string $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>.
+// Parameter follow OWASP minimum recommendation for argon2id.
+const (
+	PIN_HASH_PREFIX  = "$argon2id$"
+	PIN_HASH_MEMORY  = 19 * 1024
+	PIN_HASH_TIME    = 2
+	PIN_HASH_THREADS = 1
+	PIN_HASH_KEY_LEN = 32
+	PIN_SALT_LEN     = 16
+)
+
+func HashPIN(pin string) (string, error) {
+	salt := make([]byte, PIN_SALT_LEN)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", ErrorInternalServer(PINHashFailed, "Generate PIN salt failed")
+	}
+
+	hash := argon2.IDKey([]byte(pin), salt, PIN_HASH_TIME, PIN_HASH_MEMORY, PIN_HASH_THREADS, PIN_HASH_KEY_LEN)
+
+	return fmt.Sprintf("%vv=%d$m=%d,t=%d,p=%d$%v$%v", PIN_HASH_PREFIX, argon2.Version,
+		PIN_HASH_MEMORY, PIN_HASH_TIME, PIN_HASH_THREADS,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash)), nil
+}
+
+// VerifyPIN compare pin with stored value in constant time. Stored value
+// which is still plaintext or hashed with old parameter need to be rehashed.
+func VerifyPIN(stored string, pin string) (valid bool, needRehash bool) {
+	if stored == "" || pin == "" {
+		return false, false
+	}
+
+	if !IsHashedPIN(stored) {
+		valid = subtle.ConstantTimeCompare([]byte(stored), []byte(pin)) == 1
+		return valid, valid
+	}
+
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 {
+		return false, false
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return false, false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	if err != nil {
+		return false, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false
+	}
+
+	hash := argon2.IDKey([]byte(pin), salt, time, memory, threads, uint32(len(expected)))
+	valid = subtle.ConstantTimeCompare(hash, expected) == 1
+	needRehash = valid && (memory != PIN_HASH_MEMORY || time != PIN_HASH_TIME ||
+		threads != PIN_HASH_THREADS || len(expected) != PIN_HASH_KEY_LEN)
+
+	return valid, needRehash
+}
+
+func IsHashedPIN(stored string) bool {
+	return strings.HasPrefix(stored, PIN_HASH_PREFIX)
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Random token for refresh token and other opaque secret
+func GenerateSecureToken(length int) (string, error) {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", ErrorInternalServer(EncodeTokenFailed, "Generate secure token failed")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Opaque token is stored as sha256, it already has enough entropy so no salt needed
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}